@@ -0,0 +1,84 @@
+// Command calibrate-questions fits the difficulty of every question from
+// the answers in submitted quiz attempts and stores the statistics on the
+// questions. Run it periodically, e.g. nightly.
+//
+//	go run ./cmd/calibrate-questions -model 2pl -min-responses 20
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/config"
+	db "github.com/tharindulakmal/sl-edu-service/internal/database"
+	"github.com/tharindulakmal/sl-edu-service/internal/irt"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+type summary struct {
+	Responses  int            `json:"responses"`
+	Questions  int            `json:"questions"`
+	Calibrated int            `json:"calibrated"`
+	Flagged    map[string]int `json:"flagged"`
+	DryRun     bool           `json:"dryRun"`
+}
+
+func main() {
+	model := flag.String("model", string(irt.TwoPL), "1pl or 2pl")
+	minResponses := flag.Int("min-responses", 20, "answers a question needs before IRT parameters are fitted")
+	dryRun := flag.Bool("dry-run", false, "fit and report without storing the results")
+	flag.Parse()
+
+	if *model != string(irt.OnePL) && *model != string(irt.TwoPL) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err == nil {
+		err = cfg.DB.Validate()
+	}
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	conn, err := db.Connect(cfg.DB, cfg.Log.SlowQuery.Duration)
+	if err != nil {
+		log.Fatalf("could not connect to DB: %v", err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	repo := repository.NewCalibrationRepository(conn)
+	responses, err := repo.Responses(ctx)
+	if err != nil {
+		log.Fatalf("could not load answers: %v", err)
+	}
+
+	stats := irt.Fit(responses, irt.Options{Model: irt.Model(*model), MinResponses: *minResponses})
+	if !*dryRun {
+		if err := repo.Save(ctx, stats); err != nil {
+			log.Fatalf("could not save statistics: %v", err)
+		}
+	}
+
+	sum := summary{Responses: len(responses), Questions: len(stats), Flagged: map[string]int{}, DryRun: *dryRun}
+	for _, s := range stats {
+		if s.Difficulty != nil {
+			sum.Calibrated++
+		}
+		if s.Responses < *minResponses {
+			continue
+		}
+		for _, f := range irt.Flags(s.PValue, s.PointBiserial, s.Discrimination) {
+			sum.Flagged[f]++
+		}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(sum); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,84 @@
+// Command import-questions bulk-loads MCQs from a CSV or JSON file using the
+// same validation as POST /api/v1/mcq/questions/import.
+//
+//	go run ./cmd/import-questions -file questions.csv -dry-run
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/config"
+	db "github.com/tharindulakmal/sl-edu-service/internal/database"
+	"github.com/tharindulakmal/sl-edu-service/internal/dedupe"
+	"github.com/tharindulakmal/sl-edu-service/internal/importer"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+func main() {
+	file := flag.String("file", "", "path to the CSV or JSON file to import")
+	format := flag.String("format", "", "csv or json (defaults to the file extension)")
+	dryRun := flag.Bool("dry-run", false, "validate rows without writing them")
+	tutorID := flag.Int("tutor-id", 0, "assign every imported question to this tutor")
+	duplicates := flag.String("duplicates", dedupe.ModeWarn, "warn, block or ignore rows that look like existing questions")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format == "" {
+		*format = importer.FormatFromName(*file)
+	}
+	if *duplicates != dedupe.ModeWarn && *duplicates != dedupe.ModeBlock && *duplicates != dedupe.ModeIgnore {
+		log.Fatalf("-duplicates must be warn, block or ignore")
+	}
+
+	cfg, err := config.Load()
+	if err == nil {
+		err = cfg.DB.Validate()
+	}
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("could not open %s: %v", *file, err)
+	}
+	defer f.Close()
+
+	rows, err := importer.Parse(f, *format)
+	if err != nil {
+		log.Fatalf("could not read %s: %v", *file, err)
+	}
+
+	conn, err := db.Connect(cfg.DB, cfg.Log.SlowQuery.Duration)
+	if err != nil {
+		log.Fatalf("could not connect to DB: %v", err)
+	}
+	defer conn.Close()
+
+	opts := importer.Options{DryRun: *dryRun, Duplicates: *duplicates}
+	if *tutorID > 0 {
+		opts.TutorID = tutorID
+	}
+
+	im := importer.New(repository.NewMenuConfigRepository(conn), repository.NewQuestionRepository(conn))
+	report, err := im.Run(context.Background(), rows, opts)
+	if err != nil {
+		log.Fatalf("import failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -1,68 +1,159 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	_ "github.com/go-sql-driver/mysql"
-	"github.com/joho/godotenv"
+	"github.com/tharindulakmal/sl-edu-service/internal/auth"
+	"github.com/tharindulakmal/sl-edu-service/internal/config"
+	db "github.com/tharindulakmal/sl-edu-service/internal/database"
+	"github.com/tharindulakmal/sl-edu-service/internal/health"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/migrate"
 	"github.com/tharindulakmal/sl-edu-service/internal/routes"
-	// db "github.com/tharindulakmal/sl-edu-service/internal/database"
 )
 
 func main() {
-	r := gin.Default()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("could not load configuration: %v", err)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(migrateCommand(cfg, os.Args[2:]))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
+	}
+	// the standard log package writes through this too
+	slog.SetDefault(logging.New(os.Stderr, cfg.Log.SlogLevel()))
+	gin.DebugPrintFunc = func(format string, values ...interface{}) {
+		slog.Debug(strings.TrimSpace(fmt.Sprintf(format, values...)))
+	}
+
+	r := gin.New()
+	// lets handlers that pass the gin.Context to repositories reach the
+	// request's context, and with it the request-scoped logger
+	r.ContextWithFallback = true
+	r.Use(logging.RequestID(), logging.AccessLog(), logging.Recovery())
 	r.Use(cors.New(cors.Config{
-		AllowOrigins: []string{
-			"http://localhost:3000",
-			"http://sl-edu-service-env.eba-f8bzvpsg.us-east-1.elasticbeanstalk.com",
-			"https://sl-edu-service-env.eba-f8bzvpsg.us-east-1.elasticbeanstalk.com",
-		},
+		AllowOrigins:     cfg.CORS.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", logging.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", logging.RequestIDHeader},
 		AllowCredentials: false, // set true only if you actually use cookies/auth headers
 		MaxAge:           12 * time.Hour,
 	}))
-	// Load .env
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using system env")
-	}
 
-	if os.Getenv("SKIP_DB") == "true" {
+	checker := health.NewChecker()
+	checker.Routes(r)
+
+	var conn *sql.DB
+	if cfg.DB.Skip {
 		log.Println("Skipping database connection — running in placeholder mode")
+		checker.Register("db", func(context.Context) (string, error) {
+			return "", errors.New("skipped by SKIP_DB; API routes are not registered")
+		})
 	} else {
-		dbUser := os.Getenv("DB_USER")
-		dbPass := os.Getenv("DB_PASSWORD")
-		dbHost := os.Getenv("DB_HOST")
-		dbPort := os.Getenv("DB_PORT")
-		dbName := os.Getenv("DB_NAME")
-
-		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s",
-			dbUser, dbPass, dbHost, dbPort, dbName,
-		)
-
-		db, err := sql.Open("mysql", dsn)
-
+		conn, err = db.Connect(cfg.DB, cfg.Log.SlowQuery.Duration)
 		if err != nil {
 			log.Fatalf("could not connect to DB: %v", err)
 		}
-		if err := db.Ping(); err != nil {
-			log.Fatalf("could not ping DB: %v", err)
+		runner, err := newRunner(conn)
+		if err != nil {
+			log.Fatalf("could not load migrations: %v", err)
+		}
+		if err := migrateOnStart(context.Background(), runner, cfg.DB.AutoMigrate); err != nil {
+			log.Fatalf("could not migrate DB: %v", err)
+		}
+		registerChecks(checker, conn, runner)
+		tokens := auth.NewTokenManager([]byte(cfg.Auth.JWTSecret), cfg.Auth.TokenTTL.Duration)
+
+		if cfg.Auth.AdminEmail != "" {
+			if err := routes.EnsureAdmin(context.Background(), conn, cfg.Auth.AdminEmail, cfg.Auth.AdminPassword); err != nil {
+				log.Fatalf("could not bootstrap admin account: %v", err)
+			}
 		}
+
 		// Register all routes in one place
-		routes.RegisterRoutes(r, db)
+		routes.RegisterRoutes(r, conn, tokens, cfg)
 	}
 
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
+	srv := &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if err := serve(srv, cfg.Server, checker); err != nil {
+		log.Fatal(err)
+	}
+	if conn != nil {
+		conn.Close()
+	}
+	log.Println("Server stopped")
+}
+
+// serve runs srv until SIGINT or SIGTERM, then stops taking new connections
+// and gives in-flight requests up to the shutdown timeout to finish.
+func serve(srv *http.Server, cfg config.ServerConfig, checker *health.Checker) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	failed := make(chan error, 1)
+	go func() {
+		var err error
+		if cfg.TLS() {
+			log.Printf("Starting server on %s (TLS)", srv.Addr)
+			err = srv.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			log.Printf("Starting server on %s", srv.Addr)
+			err = srv.ListenAndServe()
+		}
+		failed <- err
+	}()
 
-	log.Println("Starting server on :8080")
-	r.Run(":8080")
+	select {
+	case err := <-failed:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+	log.Printf("Shutting down, draining requests for up to %s", cfg.ShutdownTimeout)
+	checker.Drain()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout.Duration)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	return nil
+}
+
+// registerChecks adds the readiness checks for the database and its schema.
+func registerChecks(checker *health.Checker, conn *sql.DB, runner *migrate.Runner) {
+	checker.Register("db", func(ctx context.Context) (string, error) {
+		return "", conn.PingContext(ctx)
+	})
+	checker.Register("migrations", func(ctx context.Context) (string, error) {
+		version, pending, err := runner.Current(ctx)
+		detail := fmt.Sprintf("version %d", version)
+		if err == nil && pending > 0 {
+			err = fmt.Errorf("%d migrations pending", pending)
+		}
+		return detail, err
+	})
 }
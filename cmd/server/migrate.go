@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/tharindulakmal/sl-edu-service/db/migrations"
+	"github.com/tharindulakmal/sl-edu-service/internal/config"
+	db "github.com/tharindulakmal/sl-edu-service/internal/database"
+	"github.com/tharindulakmal/sl-edu-service/internal/migrate"
+)
+
+const migrateUsage = `usage: server migrate <command>
+
+commands:
+  up            apply every pending migration
+  down [n]      roll back the last n migrations (default 1)
+  to <version>  migrate up or down to version; 0 rolls everything back
+  status        list migrations and whether they are applied`
+
+func newRunner(db *sql.DB) (*migrate.Runner, error) {
+	all, err := migrate.Load(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+	runner := migrate.NewRunner(db, all)
+	runner.Logf = log.Printf
+	return runner, nil
+}
+
+// migrateOnStart applies pending migrations when auto is set; otherwise it
+// only refuses to start when an applied migration was edited.
+func migrateOnStart(ctx context.Context, runner *migrate.Runner, auto bool) error {
+	if !auto {
+		pending, err := runner.Verify(ctx)
+		if pending > 0 {
+			log.Printf("%d migrations are pending; run `server migrate up`", pending)
+		}
+		return err
+	}
+	ran, err := runner.Up(ctx)
+	if len(ran) > 0 {
+		log.Printf("applied %d migrations", len(ran))
+	}
+	return err
+}
+
+// migrateCommand runs `server migrate ...` and returns the exit code.
+func migrateCommand(cfg config.Config, args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprintln(fs.Output(), migrateUsage) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	if err := cfg.DB.Validate(); err != nil {
+		log.Printf("invalid configuration:\n%v", err)
+		return 1
+	}
+	conn, err := db.Connect(cfg.DB, cfg.Log.SlowQuery.Duration)
+	if err != nil {
+		log.Printf("could not connect to DB: %v", err)
+		return 1
+	}
+	defer conn.Close()
+	runner, err := newRunner(conn)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+
+	ctx := context.Background()
+	cmd, rest := fs.Arg(0), fs.Args()[1:]
+	switch {
+	case cmd == "up" && len(rest) == 0:
+		_, err = runner.Up(ctx)
+	case cmd == "down" && len(rest) <= 1:
+		n := 1
+		if len(rest) == 1 {
+			if n, err = strconv.Atoi(rest[0]); err != nil || n < 1 {
+				log.Printf("invalid count %q", rest[0])
+				return 2
+			}
+		}
+		_, err = runner.Down(ctx, n)
+	case cmd == "to" && len(rest) == 1:
+		version, perr := strconv.ParseInt(rest[0], 10, 64)
+		if perr != nil || version < 0 {
+			log.Printf("invalid version %q", rest[0])
+			return 2
+		}
+		_, err = runner.To(ctx, version)
+	case cmd == "status" && len(rest) == 0:
+		var statuses []migrate.Status
+		if statuses, err = runner.Status(ctx); err == nil {
+			err = printStatus(statuses)
+		}
+	default:
+		fs.Usage()
+		return 2
+	}
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	return 0
+}
+
+func printStatus(statuses []migrate.Status) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, s := range statuses {
+		state := "pending"
+		switch {
+		case s.Dirty:
+			state = "dirty"
+		case s.Modified:
+			state = "modified"
+		case s.Applied && s.Name == "":
+			state = "unknown"
+		case s.Applied:
+			state = "applied"
+		}
+		fmt.Fprintf(w, "%06d\t%s\t%s\t%s\n", s.Version, s.Name, state, s.AppliedAt)
+	}
+	return w.Flush()
+}
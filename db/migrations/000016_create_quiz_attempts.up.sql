@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS quiz_attempts (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    student_id INT NULL,
+
+    -- filters the attempt was started with
+    grade_id INT NULL,
+    lesson_id INT NULL,
+    topic_id INT NULL,
+    subtopic_id INT NULL,
+
+    status VARCHAR(20) NOT NULL DEFAULT 'in_progress',
+    score INT NOT NULL DEFAULT 0,
+    total_questions INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    submitted_at TIMESTAMP NULL,
+
+    INDEX idx_attempts_student (student_id, id),
+    INDEX idx_attempts_lesson (lesson_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS quiz_attempt_answers (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    attempt_id INT NOT NULL,
+    question_id INT NOT NULL,
+    position INT NOT NULL,
+
+    -- options in the shuffled order they were shown to the student
+    options JSON NOT NULL,
+    selected_answer VARCHAR(255) NULL,
+    is_correct BOOLEAN NULL,
+    answered_at TIMESTAMP NULL,
+
+    UNIQUE KEY uq_attempt_answers_question (attempt_id, question_id),
+    INDEX idx_attempt_answers_attempt (attempt_id, position)
+);
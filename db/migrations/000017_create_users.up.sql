@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS users (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    email VARCHAR(160) NOT NULL,
+    name VARCHAR(120) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+
+    -- admin, content_editor, tutor or student
+    role VARCHAR(20) NOT NULL DEFAULT 'student',
+
+    -- links a tutor account to the tutors table so question ownership can be enforced
+    tutor_id INT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_users_email (email),
+    INDEX idx_users_tutor_id (tutor_id)
+);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS smart_note_revisions;
+
+ALTER TABLE smart_notes
+    DROP INDEX uq_notes_default_lesson,
+    DROP COLUMN default_lesson_id,
+    DROP COLUMN updated_at,
+    DROP COLUMN updated_by,
+    DROP COLUMN revision;
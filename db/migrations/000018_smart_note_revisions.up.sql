@@ -0,0 +1,42 @@
+-- Keep only the oldest default note per lesson before enforcing uniqueness
+UPDATE smart_notes sn
+JOIN (
+    SELECT lesson_id, MIN(id) AS keep_id
+    FROM smart_notes
+    WHERE is_default = TRUE
+    GROUP BY lesson_id
+) d ON d.lesson_id = sn.lesson_id
+SET sn.is_default = FALSE
+WHERE sn.is_default = TRUE AND sn.id <> d.keep_id;
+
+ALTER TABLE smart_notes
+    ADD COLUMN revision INT NOT NULL DEFAULT 1 AFTER is_default,
+    ADD COLUMN updated_by INT NULL AFTER revision,
+    ADD COLUMN updated_at TIMESTAMP NULL DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP AFTER created_at,
+    -- NULL for non-default notes, so the unique key only applies to defaults
+    ADD COLUMN default_lesson_id INT AS (IF(is_default, lesson_id, NULL)) STORED,
+    ADD UNIQUE INDEX uq_notes_default_lesson (default_lesson_id);
+
+CREATE TABLE IF NOT EXISTS smart_note_revisions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    smart_note_id INT NOT NULL,
+    revision INT NOT NULL,
+
+    lesson_id INT NOT NULL,
+    topic_id INT NULL,
+    subtopic_id INT NULL,
+    sub_topic_name VARCHAR(255) NOT NULL,
+    image_def_url VARCHAR(512),
+    definition TEXT,
+    theory TEXT,
+    image_theory_url VARCHAR(512),
+    example TEXT,
+    image_example_url VARCHAR(512),
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- author of this version of the note
+    edited_by INT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uq_note_revisions_note_revision (smart_note_id, revision)
+);
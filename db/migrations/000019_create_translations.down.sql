@@ -0,0 +1,9 @@
+ALTER TABLE quiz_attempts DROP COLUMN lang;
+
+DROP TABLE IF EXISTS question_translations;
+DROP TABLE IF EXISTS smart_note_translations;
+DROP TABLE IF EXISTS subtopic_translations;
+DROP TABLE IF EXISTS topic_translations;
+DROP TABLE IF EXISTS lesson_translations;
+DROP TABLE IF EXISTS subject_translations;
+DROP TABLE IF EXISTS grade_translations;
@@ -0,0 +1,92 @@
+-- Per-language overrides of the curriculum and question text. The base
+-- columns on each entity hold the English text; a missing translation (or a
+-- NULL column in one) falls back to it.
+
+CREATE TABLE IF NOT EXISTS grade_translations (
+    grade_id INT NOT NULL,
+    lang VARCHAR(5) NOT NULL,
+    name VARCHAR(120) NOT NULL,
+    updated_by INT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (grade_id, lang),
+    INDEX idx_grade_translations_lang (lang, grade_id),
+    CONSTRAINT fk_grade_translations_grade FOREIGN KEY (grade_id) REFERENCES grades(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS subject_translations (
+    subject_id INT NOT NULL,
+    lang VARCHAR(5) NOT NULL,
+    name VARCHAR(120) NOT NULL,
+    updated_by INT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (subject_id, lang),
+    INDEX idx_subject_translations_lang (lang, subject_id),
+    CONSTRAINT fk_subject_translations_subject FOREIGN KEY (subject_id) REFERENCES subjects(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS lesson_translations (
+    lesson_id INT NOT NULL,
+    lang VARCHAR(5) NOT NULL,
+    name VARCHAR(120) NOT NULL,
+    updated_by INT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (lesson_id, lang),
+    INDEX idx_lesson_translations_lang (lang, lesson_id),
+    CONSTRAINT fk_lesson_translations_lesson FOREIGN KEY (lesson_id) REFERENCES lessons(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS topic_translations (
+    topic_id INT NOT NULL,
+    lang VARCHAR(5) NOT NULL,
+    name VARCHAR(120) NOT NULL,
+    updated_by INT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (topic_id, lang),
+    INDEX idx_topic_translations_lang (lang, topic_id),
+    CONSTRAINT fk_topic_translations_topic FOREIGN KEY (topic_id) REFERENCES topics(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS subtopic_translations (
+    subtopic_id INT NOT NULL,
+    lang VARCHAR(5) NOT NULL,
+    name VARCHAR(120) NOT NULL,
+    updated_by INT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (subtopic_id, lang),
+    INDEX idx_subtopic_translations_lang (lang, subtopic_id),
+    CONSTRAINT fk_subtopic_translations_subtopic FOREIGN KEY (subtopic_id) REFERENCES subtopics(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS smart_note_translations (
+    smart_note_id INT NOT NULL,
+    lang VARCHAR(5) NOT NULL,
+    sub_topic_name VARCHAR(255) NOT NULL,
+    definition TEXT,
+    theory TEXT,
+    example TEXT,
+    updated_by INT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (smart_note_id, lang),
+    INDEX idx_smart_note_translations_lang (lang, smart_note_id),
+    CONSTRAINT fk_smart_note_translations_note FOREIGN KEY (smart_note_id) REFERENCES smart_notes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS question_translations (
+    question_id INT NOT NULL,
+    lang VARCHAR(5) NOT NULL,
+    question TEXT NOT NULL,
+    correct_answer VARCHAR(255) NOT NULL,
+    other_answers JSON NOT NULL,
+    theory TEXT,
+    solution TEXT,
+    updated_by INT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (question_id, lang),
+    INDEX idx_question_translations_lang (lang, question_id),
+    CONSTRAINT fk_question_translations_question FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE
+);
+
+-- Attempts remember the medium they were started in so grading compares the
+-- answer in the same language the options were shown in.
+ALTER TABLE quiz_attempts
+    ADD COLUMN lang VARCHAR(5) NOT NULL DEFAULT 'en' AFTER subtopic_id;
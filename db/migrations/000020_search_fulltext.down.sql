@@ -0,0 +1,11 @@
+ALTER TABLE question_translations DROP INDEX ft_question_translations_text;
+ALTER TABLE smart_note_translations DROP INDEX ft_smart_note_translations_text;
+ALTER TABLE subtopic_translations DROP INDEX ft_subtopic_translations_name;
+ALTER TABLE topic_translations DROP INDEX ft_topic_translations_name;
+ALTER TABLE lesson_translations DROP INDEX ft_lesson_translations_name;
+
+ALTER TABLE questions DROP INDEX ft_questions_text;
+ALTER TABLE smart_notes DROP INDEX ft_smart_notes_text;
+ALTER TABLE subtopics DROP INDEX ft_subtopics_name;
+ALTER TABLE topics DROP INDEX ft_topics_name;
+ALTER TABLE lessons DROP INDEX ft_lessons_name;
@@ -0,0 +1,13 @@
+-- FULLTEXT indexes backing /api/v1/search, on the base text and on the
+-- translated text of the same entities.
+ALTER TABLE lessons ADD FULLTEXT INDEX ft_lessons_name (name);
+ALTER TABLE topics ADD FULLTEXT INDEX ft_topics_name (name);
+ALTER TABLE subtopics ADD FULLTEXT INDEX ft_subtopics_name (name);
+ALTER TABLE smart_notes ADD FULLTEXT INDEX ft_smart_notes_text (sub_topic_name, definition, theory, example);
+ALTER TABLE questions ADD FULLTEXT INDEX ft_questions_text (question);
+
+ALTER TABLE lesson_translations ADD FULLTEXT INDEX ft_lesson_translations_name (name);
+ALTER TABLE topic_translations ADD FULLTEXT INDEX ft_topic_translations_name (name);
+ALTER TABLE subtopic_translations ADD FULLTEXT INDEX ft_subtopic_translations_name (name);
+ALTER TABLE smart_note_translations ADD FULLTEXT INDEX ft_smart_note_translations_text (sub_topic_name, definition, theory, example);
+ALTER TABLE question_translations ADD FULLTEXT INDEX ft_question_translations_text (question);
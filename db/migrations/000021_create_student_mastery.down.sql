@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS student_answers;
+DROP TABLE IF EXISTS student_mastery;
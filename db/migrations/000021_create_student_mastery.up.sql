@@ -0,0 +1,24 @@
+-- per student knowledge estimate for each subtopic, updated on every graded answer
+CREATE TABLE IF NOT EXISTS student_mastery (
+    student_id INT NOT NULL,
+    subtopic_id INT NOT NULL,
+    p_known DOUBLE NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    correct INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (student_id, subtopic_id),
+    CONSTRAINT fk_student_mastery_subtopic FOREIGN KEY (subtopic_id) REFERENCES subtopics(id) ON DELETE CASCADE
+);
+
+-- every graded answer, so practice can skip recently seen questions
+CREATE TABLE IF NOT EXISTS student_answers (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    student_id INT NOT NULL,
+    question_id INT NOT NULL,
+    subtopic_id INT NULL,
+    is_correct BOOLEAN NOT NULL,
+    answered_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_student_answers_student (student_id, id)
+);
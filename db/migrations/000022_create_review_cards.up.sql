@@ -0,0 +1,19 @@
+-- spaced-repetition deck per student; item_id points at a question or a smart note
+CREATE TABLE IF NOT EXISTS review_cards (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    student_id INT NOT NULL,
+    card_type VARCHAR(20) NOT NULL,
+    item_id INT NOT NULL,
+
+    ease_factor DOUBLE NOT NULL DEFAULT 2.5,
+    interval_days INT NOT NULL DEFAULT 0,
+    repetitions INT NOT NULL DEFAULT 0,
+    lapses INT NOT NULL DEFAULT 0,
+    -- stored in UTC
+    due_at DATETIME NOT NULL,
+    last_reviewed_at DATETIME NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uq_review_cards_item (student_id, card_type, item_id),
+    INDEX idx_review_cards_due (student_id, due_at)
+);
@@ -0,0 +1,12 @@
+ALTER TABLE quiz_attempts DROP COLUMN paper_id;
+
+ALTER TABLE questions
+    DROP FOREIGN KEY fk_questions_paper,
+    DROP FOREIGN KEY fk_questions_year,
+    DROP INDEX uq_questions_paper_number,
+    DROP INDEX idx_questions_year,
+    DROP COLUMN question_number,
+    DROP COLUMN paper_id,
+    DROP COLUMN year_id;
+
+DROP TABLE IF EXISTS past_papers;
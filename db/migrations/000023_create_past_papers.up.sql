@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS past_papers (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    -- grade5_scholarship, ol or al
+    exam_type VARCHAR(30) NOT NULL,
+    year_id INT NOT NULL,
+    subject_id INT NULL,
+    paper_number INT NOT NULL DEFAULT 1,
+    name VARCHAR(160) NOT NULL,
+    duration_minutes INT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uq_past_papers_paper (exam_type, year_id, subject_id, paper_number),
+    INDEX idx_past_papers_year (year_id),
+    CONSTRAINT fk_past_papers_year FOREIGN KEY (year_id) REFERENCES years(id) ON DELETE CASCADE,
+    CONSTRAINT fk_past_papers_subject FOREIGN KEY (subject_id) REFERENCES subjects(id) ON DELETE SET NULL
+);
+
+-- a question can be tagged with the year it appeared in, and with its place
+-- in a specific paper; year_id follows the paper when paper_id is set
+ALTER TABLE questions
+    ADD COLUMN year_id INT NULL,
+    ADD COLUMN paper_id INT NULL,
+    ADD COLUMN question_number INT NULL,
+    ADD UNIQUE KEY uq_questions_paper_number (paper_id, question_number),
+    ADD INDEX idx_questions_year (year_id),
+    ADD CONSTRAINT fk_questions_year FOREIGN KEY (year_id) REFERENCES years(id) ON DELETE SET NULL,
+    ADD CONSTRAINT fk_questions_paper FOREIGN KEY (paper_id) REFERENCES past_papers(id) ON DELETE SET NULL;
+
+ALTER TABLE quiz_attempts ADD COLUMN paper_id INT NULL AFTER subtopic_id;
@@ -0,0 +1,10 @@
+ALTER TABLE quiz_attempts
+    DROP COLUMN late,
+    DROP COLUMN late_policy,
+    DROP COLUMN deadline_at,
+    DROP COLUMN time_limit_minutes,
+    DROP COLUMN seed,
+    DROP COLUMN blueprint_id;
+
+DROP TABLE IF EXISTS exam_blueprint_sections;
+DROP TABLE IF EXISTS exam_blueprints;
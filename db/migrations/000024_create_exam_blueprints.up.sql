@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS exam_blueprints (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(160) NOT NULL,
+    grade_id INT NULL,
+    total_questions INT NOT NULL,
+    duration_minutes INT NOT NULL,
+    -- what happens to submissions after the time limit: mark or reject
+    late_policy VARCHAR(10) NOT NULL DEFAULT 'mark',
+    created_by INT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL ON UPDATE CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS exam_blueprint_sections (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    blueprint_id INT NOT NULL,
+    position INT NOT NULL,
+    name VARCHAR(160) NOT NULL DEFAULT '',
+    lesson_id INT NULL,
+    topic_id INT NULL,
+    subtopic_id INT NULL,
+    -- exactly one of count and percent is set
+    question_count INT NULL,
+    percent INT NULL,
+
+    INDEX idx_blueprint_sections_blueprint (blueprint_id, position),
+    CONSTRAINT fk_blueprint_sections_blueprint FOREIGN KEY (blueprint_id) REFERENCES exam_blueprints(id) ON DELETE CASCADE
+);
+
+ALTER TABLE quiz_attempts
+    ADD COLUMN blueprint_id INT NULL AFTER paper_id,
+    ADD COLUMN seed BIGINT NULL AFTER blueprint_id,
+    ADD COLUMN time_limit_minutes INT NULL,
+    ADD COLUMN deadline_at TIMESTAMP NULL,
+    ADD COLUMN late_policy VARCHAR(10) NULL,
+    ADD COLUMN late BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,6 @@
+ALTER TABLE quiz_attempt_answers MODIFY selected_answer VARCHAR(255) NULL;
+
+ALTER TABLE questions
+    DROP INDEX idx_questions_type,
+    DROP COLUMN answer_spec,
+    DROP COLUMN question_type;
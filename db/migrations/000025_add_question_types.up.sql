@@ -0,0 +1,11 @@
+-- single_choice, multi_select, true_false, numeric, fill_blank or matching;
+-- existing questions are single choice. Types other than single choice and
+-- true/false keep their answer key in answer_spec, with a readable form of
+-- it in correct_answer.
+ALTER TABLE questions
+    ADD COLUMN question_type VARCHAR(20) NOT NULL DEFAULT 'single_choice' AFTER other_answers,
+    ADD COLUMN answer_spec JSON NULL AFTER question_type,
+    ADD INDEX idx_questions_type (question_type);
+
+-- multi-value answers are stored as a JSON list
+ALTER TABLE quiz_attempt_answers MODIFY selected_answer TEXT NULL;
@@ -0,0 +1,8 @@
+ALTER TABLE questions
+    DROP INDEX idx_questions_difficulty,
+    DROP COLUMN calibrated_at,
+    DROP COLUMN discrimination,
+    DROP COLUMN difficulty,
+    DROP COLUMN point_biserial,
+    DROP COLUMN p_value,
+    DROP COLUMN response_count;
@@ -0,0 +1,11 @@
+-- item statistics written by cmd/calibrate-questions from the answers in
+-- submitted quiz attempts; difficulty and discrimination are the IRT b and a
+-- parameters and stay NULL until a question has enough answers
+ALTER TABLE questions
+    ADD COLUMN response_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN p_value DOUBLE NULL,
+    ADD COLUMN point_biserial DOUBLE NULL,
+    ADD COLUMN difficulty DOUBLE NULL,
+    ADD COLUMN discrimination DOUBLE NULL,
+    ADD COLUMN calibrated_at TIMESTAMP NULL,
+    ADD INDEX idx_questions_difficulty (difficulty);
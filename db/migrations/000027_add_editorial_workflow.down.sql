@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS editorial_events;
+
+ALTER TABLE smart_notes
+    DROP INDEX idx_smart_notes_status,
+    DROP COLUMN status;
+
+ALTER TABLE questions
+    DROP INDEX idx_questions_status,
+    DROP COLUMN status;
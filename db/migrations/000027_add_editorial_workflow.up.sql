@@ -0,0 +1,26 @@
+-- questions and smart notes move through draft -> in_review -> approved ->
+-- published -> retired; only published content is served publicly. What is
+-- already in the bank stays live.
+ALTER TABLE questions
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    ADD INDEX idx_questions_status (status);
+UPDATE questions SET status = 'published';
+
+ALTER TABLE smart_notes
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    ADD INDEX idx_smart_notes_status (status);
+UPDATE smart_notes SET status = 'published';
+
+-- status changes and reviewer comments; a plain comment has no statuses
+CREATE TABLE IF NOT EXISTS editorial_events (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    content_type VARCHAR(20) NOT NULL,
+    content_id INT NOT NULL,
+    from_status VARCHAR(20) NULL,
+    to_status VARCHAR(20) NULL,
+    comment TEXT NULL,
+    user_id INT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_editorial_events_content (content_type, content_id, id)
+);
@@ -0,0 +1,17 @@
+-- one row per admin change, written in the same transaction as the change;
+-- before/after are the whole row, NULL for creates and deletes respectively
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    actor_id INT NULL,
+    actor_role VARCHAR(20) NULL,
+    action VARCHAR(20) NOT NULL,
+    entity_type VARCHAR(40) NOT NULL,
+    entity_id BIGINT NOT NULL,
+    before_json JSON NULL,
+    after_json JSON NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_audit_log_entity (entity_type, entity_id, id),
+    INDEX idx_audit_log_actor (actor_id, id),
+    INDEX idx_audit_log_created (created_at)
+);
@@ -0,0 +1,9 @@
+-- trashed rows become live again; delete them first to discard them
+ALTER TABLE tutorials DROP INDEX idx_tutorials_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE years DROP INDEX idx_years_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE tutors DROP INDEX idx_tutors_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE subtopics DROP INDEX idx_subtopics_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE topics DROP INDEX idx_topics_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE lessons DROP INDEX idx_lessons_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE subjects DROP INDEX idx_subjects_deleted_at, DROP COLUMN deleted_at;
+ALTER TABLE grades DROP INDEX idx_grades_deleted_at, DROP COLUMN deleted_at;
@@ -0,0 +1,11 @@
+-- menu config rows are moved to the trash instead of being deleted, so the
+-- questions and smart notes that point at them are never orphaned; rows
+-- trashed together by a cascade share the same deleted_at
+ALTER TABLE grades ADD COLUMN deleted_at TIMESTAMP NULL, ADD INDEX idx_grades_deleted_at (deleted_at);
+ALTER TABLE subjects ADD COLUMN deleted_at TIMESTAMP NULL, ADD INDEX idx_subjects_deleted_at (deleted_at);
+ALTER TABLE lessons ADD COLUMN deleted_at TIMESTAMP NULL, ADD INDEX idx_lessons_deleted_at (deleted_at);
+ALTER TABLE topics ADD COLUMN deleted_at TIMESTAMP NULL, ADD INDEX idx_topics_deleted_at (deleted_at);
+ALTER TABLE subtopics ADD COLUMN deleted_at TIMESTAMP NULL, ADD INDEX idx_subtopics_deleted_at (deleted_at);
+ALTER TABLE tutors ADD COLUMN deleted_at TIMESTAMP NULL, ADD INDEX idx_tutors_deleted_at (deleted_at);
+ALTER TABLE years ADD COLUMN deleted_at TIMESTAMP NULL, ADD INDEX idx_years_deleted_at (deleted_at);
+ALTER TABLE tutorials ADD COLUMN deleted_at TIMESTAMP NULL, ADD INDEX idx_tutorials_deleted_at (deleted_at);
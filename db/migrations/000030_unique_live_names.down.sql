@@ -0,0 +1,19 @@
+-- fails while a trashed row shares its name with a live one; purge or
+-- rename it first
+ALTER TABLE years DROP INDEX uq_years_value, ADD UNIQUE INDEX uq_years_value (value);
+ALTER TABLE years DROP COLUMN live_value;
+
+ALTER TABLE subtopics DROP INDEX uq_subtopics_topic_name, ADD UNIQUE INDEX uq_subtopics_topic_name (topic_id, name);
+ALTER TABLE subtopics DROP COLUMN live_name;
+
+ALTER TABLE topics DROP INDEX uq_topics_lesson_name, ADD UNIQUE INDEX uq_topics_lesson_name (lesson_id, name);
+ALTER TABLE topics DROP COLUMN live_name;
+
+ALTER TABLE lessons DROP INDEX uq_lessons_subject_name, ADD UNIQUE INDEX uq_lessons_subject_name (subject_id, name);
+ALTER TABLE lessons DROP COLUMN live_name;
+
+ALTER TABLE subjects DROP INDEX uq_subjects_grade_name, ADD UNIQUE INDEX uq_subjects_grade_name (grade_id, name);
+ALTER TABLE subjects DROP COLUMN live_name;
+
+ALTER TABLE grades DROP INDEX uq_grades_name, ADD UNIQUE INDEX uq_grades_name (name);
+ALTER TABLE grades DROP COLUMN live_name;
@@ -0,0 +1,20 @@
+-- names only have to be unique among live rows, so a trashed row doesn't
+-- block creating its replacement; the live_* columns are NULL in the trash
+-- and the uq_* indexes keep their names
+ALTER TABLE grades ADD COLUMN live_name VARCHAR(255) AS (IF(deleted_at IS NULL, name, NULL)) VIRTUAL;
+ALTER TABLE grades DROP INDEX uq_grades_name, ADD UNIQUE INDEX uq_grades_name (live_name);
+
+ALTER TABLE subjects ADD COLUMN live_name VARCHAR(255) AS (IF(deleted_at IS NULL, name, NULL)) VIRTUAL;
+ALTER TABLE subjects DROP INDEX uq_subjects_grade_name, ADD UNIQUE INDEX uq_subjects_grade_name (grade_id, live_name);
+
+ALTER TABLE lessons ADD COLUMN live_name VARCHAR(255) AS (IF(deleted_at IS NULL, name, NULL)) VIRTUAL;
+ALTER TABLE lessons DROP INDEX uq_lessons_subject_name, ADD UNIQUE INDEX uq_lessons_subject_name (subject_id, live_name);
+
+ALTER TABLE topics ADD COLUMN live_name VARCHAR(255) AS (IF(deleted_at IS NULL, name, NULL)) VIRTUAL;
+ALTER TABLE topics DROP INDEX uq_topics_lesson_name, ADD UNIQUE INDEX uq_topics_lesson_name (lesson_id, live_name);
+
+ALTER TABLE subtopics ADD COLUMN live_name VARCHAR(255) AS (IF(deleted_at IS NULL, name, NULL)) VIRTUAL;
+ALTER TABLE subtopics DROP INDEX uq_subtopics_topic_name, ADD UNIQUE INDEX uq_subtopics_topic_name (topic_id, live_name);
+
+ALTER TABLE years ADD COLUMN live_value INT AS (IF(deleted_at IS NULL, value, NULL)) VIRTUAL;
+ALTER TABLE years DROP INDEX uq_years_value, ADD UNIQUE INDEX uq_years_value (live_value);
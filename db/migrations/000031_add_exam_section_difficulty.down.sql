@@ -0,0 +1 @@
+ALTER TABLE exam_blueprint_sections DROP COLUMN difficulty;
@@ -0,0 +1,2 @@
+-- a section may draw only from one difficulty band (easy, medium or hard)
+ALTER TABLE exam_blueprint_sections ADD COLUMN difficulty VARCHAR(10) NULL AFTER subtopic_id;
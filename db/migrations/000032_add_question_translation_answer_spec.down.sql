@@ -0,0 +1 @@
+ALTER TABLE question_translations DROP COLUMN answer_spec;
@@ -0,0 +1,3 @@
+-- the translated key of multi select, fill in the blank and matching
+-- questions; NULL keeps the base answer spec
+ALTER TABLE question_translations ADD COLUMN answer_spec JSON NULL AFTER other_answers;
@@ -0,0 +1,9 @@
+// Package migrations embeds the schema migrations so the server binary can
+// apply them itself. Files are named NNNNNN_name.up.sql and .down.sql; the
+// numbers only need to increase, so the gap after 000008 is harmless.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestTokenRoundTrip(t *testing.T) {
+	tutorID := 7
+	tokens := NewTokenManager(testSecret, time.Hour)
+
+	token, _, err := tokens.Issue(Claims{UserID: 3, Role: models.RoleTutor, TutorID: &tutorID})
+	assert.NoError(t, err)
+
+	claims, err := tokens.Parse(token)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, claims.UserID)
+	assert.Equal(t, models.RoleTutor, claims.Role)
+	assert.Equal(t, 7, *claims.TutorID)
+}
+
+func TestParseRejectsTamperedAndExpiredTokens(t *testing.T) {
+	tokens := NewTokenManager(testSecret, time.Hour)
+	token, _, _ := tokens.Issue(Claims{UserID: 1, Role: models.RoleStudent})
+
+	other := NewTokenManager([]byte("another-secret-another-secret-00"), time.Hour)
+	_, err := other.Parse(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	tokens.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = tokens.Parse(token)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokens := NewTokenManager(testSecret, time.Hour)
+
+	router := gin.New()
+	router.GET("/admin", Authenticate(tokens), RequireRole(models.RoleContentEditor), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	cases := []struct {
+		role string
+		want int
+	}{
+		{"", http.StatusUnauthorized},
+		{models.RoleStudent, http.StatusForbidden},
+		{models.RoleContentEditor, http.StatusOK},
+		{models.RoleAdmin, http.StatusOK},
+	}
+	for _, tc := range cases {
+		req, _ := http.NewRequest("GET", "/admin", nil)
+		if tc.role != "" {
+			token, _, _ := tokens.Issue(Claims{UserID: 1, Role: tc.role})
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tc.want, w.Code, "role %q", tc.role)
+	}
+}
+
+func TestDummyPasswordHashCostsAsMuchAsARealOne(t *testing.T) {
+	cost, err := bcrypt.Cost([]byte(DummyPasswordHash))
+	assert.NoError(t, err)
+	assert.Equal(t, bcrypt.DefaultCost, cost)
+	assert.False(t, CheckPassword(DummyPasswordHash, ""))
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+const claimsKey = "auth.claims"
+
+// Authenticate rejects requests without a valid bearer token and stores the
+// token claims on the context for downstream handlers.
+func Authenticate(tokens *TokenManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := claimsFromHeader(c, tokens)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if claims == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization required"})
+			return
+		}
+		setClaims(c, claims)
+		c.Next()
+	}
+}
+
+// OptionalAuthenticate behaves like Authenticate but lets anonymous requests
+// through. A malformed or expired token is still rejected.
+func OptionalAuthenticate(tokens *TokenManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := claimsFromHeader(c, tokens)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if claims != nil {
+			setClaims(c, claims)
+		}
+		c.Next()
+	}
+}
+
+// RequireRole must run after Authenticate. It rejects callers whose role is
+// not in roles. Admins are always allowed.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := ClaimsFrom(c)
+		if claims == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization required"})
+			return
+		}
+		if claims.Role == models.RoleAdmin {
+			c.Next()
+			return
+		}
+		for _, role := range roles {
+			if claims.Role == role {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+	}
+}
+
+// ClaimsFrom returns the authenticated caller, or nil for anonymous requests.
+func ClaimsFrom(c *gin.Context) *Claims {
+	v, ok := c.Get(claimsKey)
+	if !ok {
+		return nil
+	}
+	claims, _ := v.(*Claims)
+	return claims
+}
+
+// ClaimsFromContext returns the caller of the request ctx belongs to, for
+// code below the handlers such as the audit log.
+func ClaimsFromContext(ctx context.Context) *Claims {
+	claims, _ := ctx.Value(claimsContextKey{}).(*Claims)
+	return claims
+}
+
+type claimsContextKey struct{}
+
+// setClaims stores the caller on the Gin context and on the request context.
+func setClaims(c *gin.Context, claims *Claims) {
+	c.Set(claimsKey, claims)
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), claimsContextKey{}, claims))
+}
+
+func claimsFromHeader(c *gin.Context, tokens *TokenManager) (*Claims, error) {
+	header := strings.TrimSpace(c.GetHeader("Authorization"))
+	if header == "" {
+		return nil, nil
+	}
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return nil, errors.New("invalid authorization header")
+	}
+	claims, err := tokens.Parse(strings.TrimSpace(token))
+	if err != nil {
+		if errors.Is(err, ErrExpiredToken) {
+			return nil, errors.New("token expired")
+		}
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const minPasswordLength = 8
+
+var ErrWeakPassword = errors.New("password must be at least 8 characters")
+
+// DummyPasswordHash is checked instead of a real hash when a login names an
+// unknown email, so it costs as much bcrypt time as a wrong password and the
+// response time doesn't tell which emails are registered. It matches no
+// password anyone would send.
+const DummyPasswordHash = "$2a$10$V3IToTWxGnnzAwl4xLnI2e8D5uVFxs0MjOL9RiqgBCCRBaMeiT6Tu"
+
+// HashPassword returns the bcrypt hash of password.
+func HashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", ErrWeakPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches the stored hash.
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,87 @@
+// Package auth issues and verifies bearer tokens and provides the Gin
+// middleware that enforces account roles.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("auth: invalid token")
+	ErrExpiredToken = errors.New("auth: token expired")
+)
+
+// Claims is the payload carried by an access token.
+type Claims struct {
+	UserID    int    `json:"sub"`
+	Role      string `json:"role"`
+	TutorID   *int   `json:"tutorId,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// TokenManager signs HS256 JWTs with a shared secret.
+type TokenManager struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewTokenManager(secret []byte, ttl time.Duration) *TokenManager {
+	return &TokenManager{secret: secret, ttl: ttl, now: time.Now}
+}
+
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Issue returns a signed token for the given claims and its expiry time.
+// IssuedAt and ExpiresAt are filled in by the manager.
+func (m *TokenManager) Issue(claims Claims) (string, time.Time, error) {
+	now := m.now()
+	expires := now.Add(m.ttl)
+	claims.IssuedAt = now.Unix()
+	claims.ExpiresAt = expires.Unix()
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	signingInput := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + m.sign(signingInput), expires, nil
+}
+
+// Parse verifies the token signature and expiry and returns its claims.
+func (m *TokenManager) Parse(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return nil, ErrInvalidToken
+	}
+	expected := m.sign(parts[0] + "." + parts[1])
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if m.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+	return &claims, nil
+}
+
+func (m *TokenManager) sign(input string) string {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(input))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
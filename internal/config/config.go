@@ -0,0 +1,329 @@
+// Package config loads the service's settings. Values come from, in rising
+// priority: the defaults below, the YAML file named by CONFIG_FILE, a .env
+// file in the working directory, and the process environment.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Server ServerConfig `yaml:"server" json:"server"`
+	DB     DBConfig     `yaml:"db" json:"db"`
+	Auth   AuthConfig   `yaml:"auth" json:"auth"`
+	CORS   CORSConfig   `yaml:"cors" json:"cors"`
+	Log    LogConfig    `yaml:"log" json:"log"`
+}
+
+// ServerConfig is where the HTTP server listens. It serves HTTPS when both
+// TLS files are set.
+type ServerConfig struct {
+	Port        int    `yaml:"port" json:"port"`
+	TLSCertFile string `yaml:"tls_cert_file" json:"tlsCertFile"`
+	TLSKeyFile  string `yaml:"tls_key_file" json:"tlsKeyFile"`
+	// ShutdownTimeout is how long in-flight requests get to finish after
+	// SIGINT or SIGTERM.
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" json:"shutdownTimeout"`
+}
+
+func (s ServerConfig) TLS() bool {
+	return s.TLSCertFile != "" && s.TLSKeyFile != ""
+}
+
+type DBConfig struct {
+	Host     string `yaml:"host" json:"host"`
+	Port     string `yaml:"port" json:"port"`
+	User     string `yaml:"user" json:"user"`
+	Password string `yaml:"password" json:"password"`
+	Name     string `yaml:"name" json:"name"`
+
+	MaxOpenConns    int      `yaml:"max_open_conns" json:"maxOpenConns"`
+	MaxIdleConns    int      `yaml:"max_idle_conns" json:"maxIdleConns"`
+	ConnMaxLifetime Duration `yaml:"conn_max_lifetime" json:"connMaxLifetime"`
+	ConnMaxIdleTime Duration `yaml:"conn_max_idle_time" json:"connMaxIdleTime"`
+
+	// AutoMigrate applies pending migrations on startup; otherwise the
+	// server only checks that the applied ones are intact.
+	AutoMigrate bool `yaml:"auto_migrate" json:"autoMigrate"`
+	// Skip runs the server without a database, serving only the probes.
+	Skip bool `yaml:"skip" json:"skip"`
+}
+
+type AuthConfig struct {
+	JWTSecret string   `yaml:"jwt_secret" json:"jwtSecret"`
+	TokenTTL  Duration `yaml:"token_ttl" json:"tokenTtl"`
+	// AdminEmail and AdminPassword create the first admin account on
+	// startup when there is none yet.
+	AdminEmail    string `yaml:"admin_email" json:"adminEmail"`
+	AdminPassword string `yaml:"admin_password" json:"adminPassword"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins" json:"allowedOrigins"`
+}
+
+type LogConfig struct {
+	// Level is debug, info, warn or error.
+	Level string `yaml:"level" json:"level"`
+	// SlowQuery logs repository queries taking at least this long; 0 turns
+	// the slow query log off.
+	SlowQuery Duration `yaml:"slow_query" json:"slowQuery"`
+}
+
+// SlogLevel is Level for log/slog. Validate has checked it parses.
+func (l LogConfig) SlogLevel() slog.Level {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(l.Level))
+	return level
+}
+
+// Duration is a time.Duration written like "90s" or "5m" in YAML, the
+// environment and JSON.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	d.Duration = parsed
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Default is the configuration before any file or variable is applied.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{Port: 8080, ShutdownTimeout: Duration{30 * time.Second}},
+		DB: DBConfig{
+			Port:            "3306",
+			MaxOpenConns:    20,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: Duration{5 * time.Minute},
+			ConnMaxIdleTime: Duration{time.Minute},
+			AutoMigrate:     true,
+		},
+		Auth: AuthConfig{TokenTTL: Duration{24 * time.Hour}},
+		CORS: CORSConfig{AllowedOrigins: []string{
+			"http://localhost:3000",
+			"http://sl-edu-service-env.eba-f8bzvpsg.us-east-1.elasticbeanstalk.com",
+			"https://sl-edu-service-env.eba-f8bzvpsg.us-east-1.elasticbeanstalk.com",
+		}},
+		Log: LogConfig{Level: "info", SlowQuery: Duration{200 * time.Millisecond}},
+	}
+}
+
+// Load reads the configuration without validating it, so tools that only
+// need part of it can check just that part.
+func Load() (Config, error) {
+	// .env never overrides variables that are already set, and may name
+	// the YAML file itself
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Default(), err
+	}
+
+	cfg := Default()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, err
+		}
+		if err := yaml.Unmarshal(raw, &cfg); err != nil {
+			return cfg, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if err := applyEnv(&cfg, os.LookupEnv); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides cfg with the environment variables that are set.
+func applyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	vars := []struct {
+		name string
+		dest interface{}
+	}{
+		{"PORT", &cfg.Server.Port},
+		{"TLS_CERT_FILE", &cfg.Server.TLSCertFile},
+		{"TLS_KEY_FILE", &cfg.Server.TLSKeyFile},
+		{"SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout},
+		{"DB_HOST", &cfg.DB.Host},
+		{"DB_PORT", &cfg.DB.Port},
+		{"DB_USER", &cfg.DB.User},
+		{"DB_PASSWORD", &cfg.DB.Password},
+		{"DB_NAME", &cfg.DB.Name},
+		{"DB_MAX_OPEN_CONNS", &cfg.DB.MaxOpenConns},
+		{"DB_MAX_IDLE_CONNS", &cfg.DB.MaxIdleConns},
+		{"DB_CONN_MAX_LIFETIME", &cfg.DB.ConnMaxLifetime},
+		{"DB_CONN_MAX_IDLE_TIME", &cfg.DB.ConnMaxIdleTime},
+		{"AUTO_MIGRATE", &cfg.DB.AutoMigrate},
+		{"SKIP_DB", &cfg.DB.Skip},
+		{"JWT_SECRET", &cfg.Auth.JWTSecret},
+		{"JWT_TTL", &cfg.Auth.TokenTTL},
+		{"ADMIN_EMAIL", &cfg.Auth.AdminEmail},
+		{"ADMIN_PASSWORD", &cfg.Auth.AdminPassword},
+		{"CORS_ALLOWED_ORIGINS", &cfg.CORS.AllowedOrigins},
+		{"LOG_LEVEL", &cfg.Log.Level},
+		{"SLOW_QUERY_THRESHOLD", &cfg.Log.SlowQuery},
+	}
+
+	var errs []error
+	for _, v := range vars {
+		raw, ok := lookup(v.name)
+		if !ok {
+			continue
+		}
+		var err error
+		switch dest := v.dest.(type) {
+		case *string:
+			*dest = raw
+		case *int:
+			*dest, err = strconv.Atoi(strings.TrimSpace(raw))
+		case *bool:
+			*dest, err = strconv.ParseBool(strings.TrimSpace(raw))
+		case *Duration:
+			dest.Duration, err = time.ParseDuration(strings.TrimSpace(raw))
+		case *[]string:
+			*dest = splitList(raw)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", v.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// splitList reads a comma separated list, dropping blanks.
+func splitList(raw string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Validate reports every problem with the configuration at once. Without a
+// database only the HTTP settings matter.
+func (c Config) Validate() error {
+	var errs []error
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, errors.New("server port must be between 1 and 65535"))
+	}
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		errs = append(errs, errors.New("TLS needs both a certificate and a key file"))
+	}
+	if c.Server.ShutdownTimeout.Duration < 0 {
+		errs = append(errs, errors.New("shutdown timeout can't be negative"))
+	}
+	for _, origin := range c.CORS.AllowedOrigins {
+		if !validOrigin(origin) {
+			errs = append(errs, fmt.Errorf("CORS origin %q must be a scheme and host such as https://example.com", origin))
+		}
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("log level %q must be debug, info, warn or error", c.Log.Level))
+	}
+	if c.Log.SlowQuery.Duration < 0 {
+		errs = append(errs, errors.New("slow query threshold can't be negative"))
+	}
+	if c.DB.Skip {
+		return errors.Join(errs...)
+	}
+
+	if err := c.DB.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(c.Auth.JWTSecret) < 32 {
+		errs = append(errs, errors.New("JWT secret must be at least 32 characters"))
+	}
+	if c.Auth.TokenTTL.Duration <= 0 {
+		errs = append(errs, errors.New("token TTL must be positive"))
+	}
+	if (c.Auth.AdminEmail == "") != (c.Auth.AdminPassword == "") {
+		errs = append(errs, errors.New("admin email and password must be set together"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the settings needed to connect to the database.
+func (d DBConfig) Validate() error {
+	var errs []error
+	for _, required := range []struct{ name, value string }{
+		{"host", d.Host}, {"port", d.Port}, {"user", d.User}, {"name", d.Name},
+	} {
+		if strings.TrimSpace(required.value) == "" {
+			errs = append(errs, fmt.Errorf("database %s is required", required.name))
+		}
+	}
+	if d.MaxOpenConns < 0 || d.MaxIdleConns < 0 || d.ConnMaxLifetime.Duration < 0 || d.ConnMaxIdleTime.Duration < 0 {
+		errs = append(errs, errors.New("database pool settings can't be negative"))
+	}
+	if d.MaxOpenConns > 0 && d.MaxIdleConns > d.MaxOpenConns {
+		errs = append(errs, errors.New("database max idle connections can't exceed max open connections"))
+	}
+	return errors.Join(errs...)
+}
+
+// MySQL is the driver configuration. Times are parsed into time.Time, in UTC.
+func (d DBConfig) MySQL() *mysql.Config {
+	dsn := mysql.NewConfig()
+	dsn.User = d.User
+	dsn.Passwd = d.Password
+	dsn.Net = "tcp"
+	dsn.Addr = net.JoinHostPort(d.Host, d.Port)
+	dsn.DBName = d.Name
+	dsn.ParseTime = true
+	return dsn
+}
+
+// DSN is the MySQL data source name.
+func (d DBConfig) DSN() string {
+	return d.MySQL().FormatDSN()
+}
+
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+		(u.Path == "" || u.Path == "/") && u.RawQuery == ""
+}
+
+const redacted = "[redacted]"
+
+// Redacted returns a copy safe to show, with secrets masked.
+func (c Config) Redacted() Config {
+	mask := func(s *string) {
+		if *s != "" {
+			*s = redacted
+		}
+	}
+	mask(&c.DB.Password)
+	mask(&c.Auth.JWTSecret)
+	mask(&c.Auth.AdminPassword)
+	c.CORS.AllowedOrigins = append([]string(nil), c.CORS.AllowedOrigins...)
+	return c
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func valid() Config {
+	cfg := Default()
+	cfg.DB.Host, cfg.DB.User, cfg.DB.Name = "localhost", "edu", "edu"
+	cfg.Auth.JWTSecret = strings.Repeat("s", 32)
+	return cfg
+}
+
+func TestLoadLayersYAMLUnderEnv(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+server:
+  port: 9000
+db:
+  host: yaml-host
+  max_open_conns: 5
+  conn_max_lifetime: 90s
+cors:
+  allowed_origins: [https://a.example]
+`), 0o600))
+	t.Chdir(dir)
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("DB_HOST", "env-host")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://b.example, ,https://c.example")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 9000, cfg.Server.Port)
+	assert.Equal(t, "env-host", cfg.DB.Host)
+	assert.Equal(t, 5, cfg.DB.MaxOpenConns)
+	assert.Equal(t, 90*time.Second, cfg.DB.ConnMaxLifetime.Duration)
+	assert.Equal(t, "3306", cfg.DB.Port)
+	assert.Equal(t, []string{"https://b.example", "https://c.example"}, cfg.CORS.AllowedOrigins)
+}
+
+func TestApplyEnvReportsEveryBadValue(t *testing.T) {
+	env := map[string]string{"PORT": "http", "SKIP_DB": "yes please", "JWT_TTL": "1d", "DB_NAME": "edu"}
+	cfg := Default()
+	err := applyEnv(&cfg, func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	})
+	require.Error(t, err)
+	for _, name := range []string{"PORT", "SKIP_DB", "JWT_TTL"} {
+		assert.Contains(t, err.Error(), name)
+	}
+	assert.Equal(t, "edu", cfg.DB.Name)
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, valid().Validate())
+
+	cfg := valid()
+	cfg.Server.Port = 0
+	cfg.Server.TLSCertFile = "cert.pem"
+	cfg.CORS.AllowedOrigins = []string{"example.com"}
+	cfg.DB.Host = ""
+	cfg.DB.MaxIdleConns = 50
+	cfg.Auth.JWTSecret = "short"
+	cfg.Auth.AdminEmail = "admin@example.com"
+	cfg.Log.Level = "loud"
+	err := cfg.Validate()
+	require.Error(t, err)
+	for _, want := range []string{"port", "TLS", "example.com", "host", "idle", "JWT", "admin", "loud"} {
+		assert.Contains(t, err.Error(), want)
+	}
+
+	skip := Default()
+	skip.DB.Skip = true
+	assert.NoError(t, skip.Validate())
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := valid()
+	cfg.DB.Password = "pw"
+	cfg.Auth.AdminPassword = "admin-pw"
+	red := cfg.Redacted()
+	assert.Equal(t, redacted, red.DB.Password)
+	assert.Equal(t, redacted, red.Auth.JWTSecret)
+	assert.Equal(t, redacted, red.Auth.AdminPassword)
+	assert.Equal(t, "pw", cfg.DB.Password)
+
+	red.CORS.AllowedOrigins[0] = "changed"
+	assert.NotEqual(t, "changed", cfg.CORS.AllowedOrigins[0])
+}
+
+func TestDSN(t *testing.T) {
+	cfg := valid().DB
+	cfg.Password = "p@ss:word"
+	assert.Equal(t, "edu:p@ss:word@tcp(localhost:3306)/edu?parseTime=true", cfg.DSN())
+}
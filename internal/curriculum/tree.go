@@ -0,0 +1,127 @@
+// Package curriculum assembles the grade → subject → lesson → topic →
+// subtopic tree from flat per-level rows, so it can be loaded with one query
+// per level instead of one per node.
+package curriculum
+
+import "github.com/tharindulakmal/sl-edu-service/internal/models"
+
+// Row is one curriculum entity. ParentID is the id of the entity one level
+// up and is unused for grades.
+type Row struct {
+	ID       int64
+	ParentID int64
+	Name     string
+}
+
+// CountRow is the number of questions or smart notes filed under one
+// lesson/topic/subtopic combination.
+type CountRow struct {
+	LessonID   int64
+	TopicID    *int64
+	SubtopicID *int64
+	N          int
+}
+
+// Flat is the tree as loaded from the database, one slice per level. The
+// count slices are nil when counts were not requested.
+type Flat struct {
+	Grades     []Row
+	Subjects   []Row
+	Lessons    []Row
+	Topics     []Row
+	Subtopics  []Row
+	Questions  []CountRow
+	SmartNotes []CountRow
+}
+
+// WithCounts reports whether the count rows were loaded.
+func (f *Flat) WithCounts() bool {
+	return f.Questions != nil || f.SmartNotes != nil
+}
+
+type tally map[int64]*models.CurriculumCounts
+
+func (t tally) add(id int64, questions, notes int) {
+	c, ok := t[id]
+	if !ok {
+		c = &models.CurriculumCounts{}
+		t[id] = c
+	}
+	c.Questions += questions
+	c.SmartNotes += notes
+}
+
+func (t tally) get(id int64) *models.CurriculumCounts {
+	if c, ok := t[id]; ok {
+		return c
+	}
+	return &models.CurriculumCounts{}
+}
+
+// Build nests the rows into a tree, keeping the order of each slice. Rows
+// whose parent is not loaded are dropped. Counts are attached to every node
+// when f.WithCounts() and roll up from subtopics to grades.
+func Build(f *Flat) []models.CurriculumNode {
+	withCounts := f.WithCounts()
+	lessonCounts, topicCounts, subtopicCounts := tally{}, tally{}, tally{}
+	for _, rows := range []struct {
+		rows      []CountRow
+		questions bool
+	}{{f.Questions, true}, {f.SmartNotes, false}} {
+		for _, r := range rows.rows {
+			q, n := 0, r.N
+			if rows.questions {
+				q, n = r.N, 0
+			}
+			lessonCounts.add(r.LessonID, q, n)
+			if r.TopicID != nil {
+				topicCounts.add(*r.TopicID, q, n)
+			}
+			if r.SubtopicID != nil {
+				subtopicCounts.add(*r.SubtopicID, q, n)
+			}
+		}
+	}
+
+	level := func(typ string, rows []Row, children map[int64][]models.CurriculumNode, counts tally) map[int64][]models.CurriculumNode {
+		byParent := make(map[int64][]models.CurriculumNode)
+		for _, r := range rows {
+			node := models.CurriculumNode{Type: typ, ID: r.ID, Name: r.Name, Children: children[r.ID]}
+			if node.Children == nil {
+				node.Children = []models.CurriculumNode{}
+			}
+			if withCounts {
+				if counts != nil {
+					node.Counts = counts.get(r.ID)
+				} else {
+					node.Counts = sumCounts(node.Children)
+				}
+			}
+			byParent[r.ParentID] = append(byParent[r.ParentID], node)
+		}
+		return byParent
+	}
+
+	subtopics := level(models.EntitySubtopic, f.Subtopics, nil, subtopicCounts)
+	topics := level(models.EntityTopic, f.Topics, subtopics, topicCounts)
+	lessons := level(models.EntityLesson, f.Lessons, topics, lessonCounts)
+	subjects := level(models.EntitySubject, f.Subjects, lessons, nil)
+	grades := level(models.EntityGrade, f.Grades, subjects, nil)
+
+	// grades have no parent, so they are all filed under 0
+	if grades[0] == nil {
+		return []models.CurriculumNode{}
+	}
+	return grades[0]
+}
+
+func sumCounts(nodes []models.CurriculumNode) *models.CurriculumCounts {
+	total := &models.CurriculumCounts{}
+	for _, n := range nodes {
+		if n.Counts != nil {
+			total.Questions += n.Counts.Questions
+			total.SmartNotes += n.Counts.SmartNotes
+		}
+	}
+	return total
+}
@@ -0,0 +1,69 @@
+package curriculum
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+func id(v int64) *int64 { return &v }
+
+func sampleFlat() *Flat {
+	return &Flat{
+		Grades:    []Row{{ID: 6, Name: "Grade 6"}},
+		Subjects:  []Row{{ID: 1, ParentID: 6, Name: "Maths"}, {ID: 2, ParentID: 6, Name: "Science"}},
+		Lessons:   []Row{{ID: 10, ParentID: 1, Name: "Geometry"}, {ID: 11, ParentID: 99, Name: "Orphan"}},
+		Topics:    []Row{{ID: 100, ParentID: 10, Name: "Shapes"}},
+		Subtopics: []Row{{ID: 1000, ParentID: 100, Name: "Triangles"}, {ID: 1001, ParentID: 100, Name: "Squares"}},
+	}
+}
+
+func TestBuildNestsLevelsInOrder(t *testing.T) {
+	tree := Build(sampleFlat())
+
+	require.Len(t, tree, 1)
+	grade := tree[0]
+	assert.Equal(t, models.EntityGrade, grade.Type)
+	assert.Nil(t, grade.Counts)
+	require.Len(t, grade.Children, 2)
+
+	maths, science := grade.Children[0], grade.Children[1]
+	assert.Equal(t, "Maths", maths.Name)
+	assert.NotNil(t, science.Children, "empty levels encode as [] rather than null")
+	assert.Empty(t, science.Children)
+
+	require.Len(t, maths.Children, 1, "lessons whose subject is not loaded are dropped")
+	subtopics := maths.Children[0].Children[0].Children
+	require.Len(t, subtopics, 2)
+	assert.Equal(t, "Triangles", subtopics[0].Name)
+	assert.Equal(t, models.EntitySubtopic, subtopics[1].Type)
+}
+
+func TestBuildRollsUpCounts(t *testing.T) {
+	f := sampleFlat()
+	f.Questions = []CountRow{
+		{LessonID: 10, N: 2},
+		{LessonID: 10, TopicID: id(100), N: 3},
+		{LessonID: 10, TopicID: id(100), SubtopicID: id(1000), N: 4},
+	}
+	f.SmartNotes = []CountRow{{LessonID: 10, TopicID: id(100), SubtopicID: id(1001), N: 1}}
+
+	tree := Build(f)
+	grade := tree[0]
+	maths := grade.Children[0]
+	lesson := maths.Children[0]
+	topic := lesson.Children[0]
+
+	assert.Equal(t, &models.CurriculumCounts{Questions: 4}, topic.Children[0].Counts)
+	assert.Equal(t, &models.CurriculumCounts{SmartNotes: 1}, topic.Children[1].Counts)
+	assert.Equal(t, &models.CurriculumCounts{Questions: 7, SmartNotes: 1}, topic.Counts)
+	assert.Equal(t, &models.CurriculumCounts{Questions: 9, SmartNotes: 1}, lesson.Counts)
+	assert.Equal(t, &models.CurriculumCounts{Questions: 9, SmartNotes: 1}, grade.Counts)
+	assert.Equal(t, &models.CurriculumCounts{}, grade.Children[1].Counts)
+}
+
+func TestBuildEmpty(t *testing.T) {
+	assert.Equal(t, []models.CurriculumNode{}, Build(&Flat{}))
+}
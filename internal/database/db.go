@@ -2,29 +2,30 @@ package db
 
 import (
 	"database/sql"
-	"fmt"
-	"os"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql" // MySQL driver
+	"github.com/go-sql-driver/mysql"
+	"github.com/tharindulakmal/sl-edu-service/internal/config"
+	"github.com/tharindulakmal/sl-edu-service/internal/metrics"
 )
 
-func Connect() (*sql.DB, error) {
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true",
-		user, password, host, port, dbname)
-
-	db, err := sql.Open("mysql", dsn)
+// Connect opens the database with cfg's pool settings and checks that it is
+// reachable. Queries are timed for /metrics, and those taking at least slow
+// are logged; 0 logs none.
+func Connect(cfg config.DBConfig, slow time.Duration) (*sql.DB, error) {
+	connector, err := mysql.NewConnector(cfg.MySQL())
 	if err != nil {
 		return nil, err
 	}
+	db := sql.OpenDB(metrics.InstrumentConnector(connector, slow))
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime.Duration)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime.Duration)
 
 	// test connection
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, err
 	}
 
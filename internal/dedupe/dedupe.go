@@ -0,0 +1,276 @@
+// Package dedupe finds questions that say the same thing in slightly
+// different words. Question text is compared by the Jaccard similarity of
+// its character shingles and the answers as sets; across the whole bank,
+// MinHash signatures narrow the pairs worth comparing.
+package dedupe
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+// What to do when a new question looks like an existing one.
+const (
+	ModeWarn   = "warn"   // save it and report the matches
+	ModeBlock  = "block"  // refuse it
+	ModeIgnore = "ignore" // skip the check
+)
+
+// DefaultThreshold is the similarity from which two questions are reported
+// as likely duplicates.
+const DefaultThreshold = 0.8
+
+const (
+	shingleSize = 4
+	// the question text outweighs the answers, which are often short
+	// numbers that many questions share
+	textWeight = 0.8
+
+	bands       = 16
+	rowsPerBand = 4
+)
+
+// Item is the part of a question that duplicates are judged on.
+type Item struct {
+	ID       int
+	LessonID int
+	Question string
+	Answers  []string
+}
+
+// ItemOf takes the text and every answer option of q.
+func ItemOf(q models.Question) Item {
+	return Item{
+		ID:       q.ID,
+		LessonID: q.LessonID,
+		Question: q.Question,
+		Answers:  append([]string{q.CorrectAnswer}, q.OtherAnswers...),
+	}
+}
+
+// Match is an item found similar, by its index in the slice searched.
+type Match struct {
+	Index int
+	Score float64
+}
+
+type prepared struct {
+	shingles map[uint64]struct{}
+	answers  map[string]struct{}
+}
+
+func prepare(it Item) prepared {
+	p := prepared{shingles: map[uint64]struct{}{}, answers: map[string]struct{}{}}
+	text := []rune(normalize(it.Question))
+	if len(text) < shingleSize {
+		p.shingles[hash(string(text))] = struct{}{}
+	}
+	for i := 0; i+shingleSize <= len(text); i++ {
+		p.shingles[hash(string(text[i:i+shingleSize]))] = struct{}{}
+	}
+	for _, a := range it.Answers {
+		if a = normalize(a); a != "" {
+			p.answers[a] = struct{}{}
+		}
+	}
+	return p
+}
+
+// normalize lower-cases text, drops punctuation that does not change the
+// meaning and collapses whitespace. Operators and digits are kept, so
+// 2 + 3 and 2 - 3 stay apart.
+func normalize(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case strings.ContainsRune("?!,;:\"'“”‘’()[]{}", r):
+			continue
+		case unicode.IsSpace(r):
+			space = b.Len() > 0
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+func jaccard[K comparable](a, b map[K]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := 0
+	for k := range a {
+		if _, ok := b[k]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func similarity(a, b prepared) float64 {
+	text := jaccard(a.shingles, b.shingles)
+	if len(a.answers) == 0 || len(b.answers) == 0 {
+		return text
+	}
+	return textWeight*text + (1-textWeight)*jaccard(a.answers, b.answers)
+}
+
+// Similarity scores two items from 0 (nothing in common) to 1 (same text
+// and answers).
+func Similarity(a, b Item) float64 {
+	return similarity(prepare(a), prepare(b))
+}
+
+// Find returns the items of pool at least threshold similar to it, most
+// similar first. Items with its ID are skipped, so an existing question
+// can be checked against its own lesson.
+func Find(it Item, pool []Item, threshold float64) []Match {
+	p := prepare(it)
+	var matches []Match
+	for i, other := range pool {
+		if it.ID != 0 && other.ID == it.ID {
+			continue
+		}
+		if s := similarity(p, prepare(other)); s >= threshold {
+			matches = append(matches, Match{Index: i, Score: s})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches
+}
+
+// Cluster is a group of items linked by likely duplicate pairs. Each
+// member's Score is its highest similarity to another member.
+type Cluster struct {
+	Members []Match
+}
+
+// Clusters groups items into likely duplicates. Candidate pairs come from
+// MinHash locality sensitive hashing, which finds pairs at the default
+// threshold with near certainty without comparing every pair; candidates
+// are then scored exactly. Clusters are ordered largest first.
+func Clusters(items []Item, threshold float64) []Cluster {
+	prep := make([]prepared, len(items))
+	buckets := map[[2]uint64][]int{}
+	for i, it := range items {
+		prep[i] = prepare(it)
+		sig := signature(prep[i].shingles)
+		for band := 0; band < bands; band++ {
+			h := fnv.New64a()
+			for _, v := range sig[band*rowsPerBand : (band+1)*rowsPerBand] {
+				var buf [8]byte
+				for k := range buf {
+					buf[k] = byte(v >> (8 * k))
+				}
+				h.Write(buf[:])
+			}
+			key := [2]uint64{uint64(band), h.Sum64()}
+			buckets[key] = append(buckets[key], i)
+		}
+	}
+
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	best := make([]float64, len(items))
+	seen := map[[2]int]bool{}
+	for _, members := range buckets {
+		for x := 0; x < len(members); x++ {
+			for y := x + 1; y < len(members); y++ {
+				i, j := members[x], members[y]
+				if seen[[2]int{i, j}] {
+					continue
+				}
+				seen[[2]int{i, j}] = true
+				s := similarity(prep[i], prep[j])
+				if s < threshold {
+					continue
+				}
+				best[i] = max(best[i], s)
+				best[j] = max(best[j], s)
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	groups := map[int][]Match{}
+	for i := range items {
+		if best[i] > 0 {
+			root := find(i)
+			groups[root] = append(groups[root], Match{Index: i, Score: best[i]})
+		}
+	}
+	clusters := make([]Cluster, 0, len(groups))
+	for _, members := range groups {
+		clusters = append(clusters, Cluster{Members: members})
+	}
+	sort.Slice(clusters, func(a, b int) bool {
+		if len(clusters[a].Members) != len(clusters[b].Members) {
+			return len(clusters[a].Members) > len(clusters[b].Members)
+		}
+		return clusters[a].Members[0].Index < clusters[b].Members[0].Index
+	})
+	return clusters
+}
+
+// signature is the MinHash signature of a shingle set: for each of
+// bands*rowsPerBand hash functions, the smallest hash of any shingle.
+func signature(shingles map[uint64]struct{}) []uint64 {
+	sig := make([]uint64, bands*rowsPerBand)
+	for k := range sig {
+		sig[k] = ^uint64(0)
+	}
+	for s := range shingles {
+		for k := range sig {
+			if h := mix(s ^ seeds[k]); h < sig[k] {
+				sig[k] = h
+			}
+		}
+	}
+	return sig
+}
+
+// seeds derive the hash functions; they are fixed so signatures are stable.
+var seeds = func() []uint64 {
+	out := make([]uint64, bands*rowsPerBand)
+	x := uint64(0x9E3779B97F4A7C15)
+	for i := range out {
+		x = mix(x + uint64(i))
+		out[i] = x
+	}
+	return out
+}()
+
+// mix is the splitmix64 finaliser.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xBF58476D1CE4E5B9
+	x ^= x >> 27
+	x *= 0x94D049BB133111EB
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,62 @@
+package dedupe
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimilarity(t *testing.T) {
+	a := Item{Question: "What is the capital city of Sri Lanka?", Answers: []string{"Sri Jayawardenepura Kotte", "Colombo", "Kandy"}}
+	reworded := Item{Question: "what is the capital city of Sri Lanka", Answers: []string{"Kandy", "Colombo", "Sri Jayawardenepura Kotte"}}
+	assert.InDelta(t, 1, Similarity(a, reworded), 1e-9)
+
+	other := Item{Question: "Which river is the longest in Sri Lanka?", Answers: []string{"Mahaweli", "Kelani"}}
+	assert.Less(t, Similarity(a, other), 0.5)
+
+	plus := Item{Question: "2 + 3 = ?", Answers: []string{"5"}}
+	minus := Item{Question: "2 - 3 = ?", Answers: []string{"-1"}}
+	assert.Less(t, Similarity(plus, minus), DefaultThreshold)
+}
+
+func TestFindSortsAndSkipsSelf(t *testing.T) {
+	it := Item{ID: 1, Question: "Name the largest planet in the solar system.", Answers: []string{"Jupiter"}}
+	pool := []Item{
+		it,
+		{ID: 2, Question: "Name the smallest planet in the solar system.", Answers: []string{"Mercury"}},
+		{ID: 3, Question: "Name the largest planet of the solar system", Answers: []string{"Jupiter"}},
+		{ID: 4, Question: "Name the largest planet in the solar system", Answers: []string{"Jupiter", "Saturn"}},
+	}
+	matches := Find(it, pool, 0.7)
+	require.NotEmpty(t, matches)
+	assert.Equal(t, 3, matches[0].Index)
+	for i, m := range matches {
+		assert.NotEqual(t, 0, m.Index)
+		if i > 0 {
+			assert.GreaterOrEqual(t, matches[i-1].Score, m.Score)
+		}
+	}
+}
+
+func TestClusters(t *testing.T) {
+	var items []Item
+	for i := 0; i < 50; i++ {
+		items = append(items, Item{ID: i + 1, Question: fmt.Sprintf("Question %d about topic number %d in the syllabus", i, i*7919), Answers: []string{fmt.Sprint(i)}})
+	}
+	items = append(items,
+		Item{ID: 100, Question: "Explain why the sky appears blue during the day.", Answers: []string{"Rayleigh scattering"}},
+		Item{ID: 101, Question: "Explain why the sky appears blue during the day", Answers: []string{"Rayleigh scattering"}},
+		Item{ID: 102, Question: "explain why the sky appears blue during daytime.", Answers: []string{"Rayleigh scattering"}},
+	)
+
+	clusters := Clusters(items, DefaultThreshold)
+	require.Len(t, clusters, 1)
+	var ids []int
+	for _, m := range clusters[0].Members {
+		ids = append(ids, items[m.Index].ID)
+		assert.GreaterOrEqual(t, m.Score, DefaultThreshold)
+	}
+	assert.Equal(t, []int{100, 101, 102}, ids)
+}
@@ -0,0 +1,71 @@
+// Package editorial holds the review lifecycle shared by questions and smart
+// notes: which statuses exist, which moves between them are allowed and who
+// may make them. Storage and HTTP live elsewhere.
+package editorial
+
+import (
+	"errors"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+const (
+	StatusDraft     = "draft"
+	StatusInReview  = "in_review"
+	StatusApproved  = "approved"
+	StatusPublished = "published"
+	StatusRetired   = "retired"
+)
+
+// Kinds of content that go through review.
+const (
+	ContentQuestion  = "question"
+	ContentSmartNote = "smartnote"
+)
+
+var (
+	ErrUnknownStatus     = errors.New("editorial: unknown status")
+	ErrInvalidTransition = errors.New("editorial: status change not allowed")
+	ErrNotPermitted      = errors.New("editorial: role may not make this status change")
+)
+
+// transitions lists where content may go from each status. Sending content
+// back to draft is how a reviewer asks for changes.
+var transitions = map[string][]string{
+	StatusDraft:     {StatusInReview},
+	StatusInReview:  {StatusApproved, StatusDraft},
+	StatusApproved:  {StatusPublished, StatusDraft},
+	StatusPublished: {StatusRetired},
+	StatusRetired:   {StatusDraft},
+}
+
+// ValidStatus reports whether s is one of the lifecycle statuses.
+func ValidStatus(s string) bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// Check reports whether role may move content from one status to another.
+// Tutors may submit their drafts for review and withdraw them again; every
+// other move is for content editors and admins.
+func Check(from, to, role string) error {
+	if !ValidStatus(to) {
+		return ErrUnknownStatus
+	}
+	allowed := false
+	for _, next := range transitions[from] {
+		allowed = allowed || next == to
+	}
+	if !allowed {
+		return ErrInvalidTransition
+	}
+	switch role {
+	case models.RoleAdmin, models.RoleContentEditor:
+		return nil
+	case models.RoleTutor:
+		if (from == StatusDraft && to == StatusInReview) || (from == StatusInReview && to == StatusDraft) {
+			return nil
+		}
+	}
+	return ErrNotPermitted
+}
@@ -0,0 +1,30 @@
+package editorial
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+func TestCheck(t *testing.T) {
+	cases := []struct {
+		from, to, role string
+		want           error
+	}{
+		{StatusDraft, StatusInReview, models.RoleTutor, nil},
+		{StatusInReview, StatusDraft, models.RoleTutor, nil},
+		{StatusInReview, StatusApproved, models.RoleTutor, ErrNotPermitted},
+		{StatusInReview, StatusApproved, models.RoleContentEditor, nil},
+		{StatusApproved, StatusPublished, models.RoleAdmin, nil},
+		{StatusPublished, StatusRetired, models.RoleContentEditor, nil},
+		{StatusRetired, StatusDraft, models.RoleContentEditor, nil},
+		{StatusDraft, StatusPublished, models.RoleAdmin, ErrInvalidTransition},
+		{StatusPublished, StatusDraft, models.RoleAdmin, ErrInvalidTransition},
+		{StatusDraft, "live", models.RoleAdmin, ErrUnknownStatus},
+		{StatusDraft, StatusInReview, models.RoleStudent, ErrNotPermitted},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, Check(tc.from, tc.to, tc.role), "%s -> %s as %s", tc.from, tc.to, tc.role)
+	}
+}
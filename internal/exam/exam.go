@@ -0,0 +1,218 @@
+// Package exam validates mock exam blueprints and draws their questions.
+// Drawing is seeded so the same exam can be regenerated later.
+package exam
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/irt"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+const (
+	MaxQuestions   = 200
+	MaxDuration    = 600
+	maxCandidates  = 1000
+	maxNameLength  = 160
+	percentOfWhole = 100
+)
+
+// Source is where questions are drawn from; QuestionRepository satisfies it.
+type Source interface {
+	GetList(filters map[string]interface{}, page, pageSize int) ([]models.Question, error)
+}
+
+// ShortageError reports a section whose part of the syllabus does not have
+// enough questions left to fill its quota.
+type ShortageError struct {
+	Section string
+	Want    int
+	Have    int
+}
+
+func (e *ShortageError) Error() string {
+	return fmt.Sprintf("exam: section %q needs %d questions but only %d are available", e.Section, e.Want, e.Have)
+}
+
+// Validate checks that a blueprint is complete and that its section quotas
+// add up to TotalQuestions.
+func Validate(bp models.ExamBlueprint) error {
+	name := strings.TrimSpace(bp.Name)
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if len(name) > maxNameLength {
+		return fmt.Errorf("name must be at most %d characters", maxNameLength)
+	}
+	if bp.TotalQuestions < 1 || bp.TotalQuestions > MaxQuestions {
+		return fmt.Errorf("totalQuestions must be between 1 and %d", MaxQuestions)
+	}
+	if bp.DurationMinutes < 1 || bp.DurationMinutes > MaxDuration {
+		return fmt.Errorf("durationMinutes must be between 1 and %d", MaxDuration)
+	}
+	if bp.LatePolicy != models.LatePolicyMark && bp.LatePolicy != models.LatePolicyReject {
+		return errors.New("latePolicy must be mark or reject")
+	}
+	if len(bp.Sections) == 0 {
+		return errors.New("at least one section is required")
+	}
+	for i, s := range bp.Sections {
+		if s.LessonID == nil && s.TopicID == nil && s.SubtopicID == nil {
+			return fmt.Errorf("sections[%d]: one of lessonId, topicId or subtopicId is required", i)
+		}
+		switch s.Difficulty {
+		case "", irt.BandEasy, irt.BandMedium, irt.BandHard:
+		default:
+			return fmt.Errorf("sections[%d]: difficulty must be easy, medium or hard", i)
+		}
+		if (s.Count == nil) == (s.Percent == nil) {
+			return fmt.Errorf("sections[%d]: exactly one of count or percent is required", i)
+		}
+		if s.Count != nil && *s.Count < 1 {
+			return fmt.Errorf("sections[%d]: count must be at least 1", i)
+		}
+		if s.Percent != nil && (*s.Percent < 1 || *s.Percent > percentOfWhole) {
+			return fmt.Errorf("sections[%d]: percent must be between 1 and 100", i)
+		}
+	}
+
+	total := 0
+	for _, n := range Quotas(bp) {
+		total += n
+	}
+	if total != bp.TotalQuestions {
+		return fmt.Errorf("sections add up to %d questions, expected %d", total, bp.TotalQuestions)
+	}
+	return nil
+}
+
+// Quotas returns how many questions each section gets. Percentages of
+// TotalQuestions are rounded by largest remainder, so quotas of 30%, 30% and
+// 40% of 10 questions come to exactly 3, 3 and 4.
+func Quotas(bp models.ExamBlueprint) []int {
+	quotas := make([]int, len(bp.Sections))
+	type remainder struct{ index, value int }
+	var remainders []remainder
+	percentTotal, floorTotal := 0, 0
+	for i, s := range bp.Sections {
+		switch {
+		case s.Count != nil:
+			quotas[i] = *s.Count
+		case s.Percent != nil:
+			exact := *s.Percent * bp.TotalQuestions
+			quotas[i] = exact / percentOfWhole
+			percentTotal += exact
+			floorTotal += quotas[i]
+			remainders = append(remainders, remainder{i, exact % percentOfWhole})
+		}
+	}
+
+	// hand the questions lost to rounding down to the largest remainders
+	missing := percentTotal/percentOfWhole - floorTotal
+	sort.SliceStable(remainders, func(a, b int) bool { return remainders[a].value > remainders[b].value })
+	for _, r := range remainders {
+		if missing <= 0 {
+			break
+		}
+		if r.value > 0 {
+			quotas[r.index]++
+			missing--
+		}
+	}
+	return quotas
+}
+
+// Generate draws the blueprint's questions with the given seed. No question
+// is used twice. Narrower sections, and at the same scope those limited to a
+// difficulty band, draw first so a broad section cannot use up the questions
+// a narrower one needs. The questions are returned section
+// by section in blueprint order, shuffled within each section.
+func Generate(bp models.ExamBlueprint, seed int64, src Source) ([]models.Question, error) {
+	quotas := Quotas(bp)
+
+	order := make([]int, len(bp.Sections))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return specificity(bp.Sections[order[a]]) > specificity(bp.Sections[order[b]])
+	})
+
+	rng := rand.New(rand.NewSource(seed))
+	used := make(map[int]bool)
+	drawn := make([][]models.Question, len(bp.Sections))
+	for _, i := range order {
+		s := bp.Sections[i]
+		candidates, err := src.GetList(sectionFilters(bp, s), 1, maxCandidates)
+		if err != nil {
+			return nil, err
+		}
+		// GetList orders by id, which keeps the draw reproducible
+		available := make([]models.Question, 0, len(candidates))
+		for _, q := range candidates {
+			if !used[q.ID] {
+				available = append(available, q)
+			}
+		}
+		if len(available) < quotas[i] {
+			return nil, &ShortageError{Section: sectionName(s, i), Want: quotas[i], Have: len(available)}
+		}
+
+		rng.Shuffle(len(available), func(a, b int) { available[a], available[b] = available[b], available[a] })
+		drawn[i] = available[:quotas[i]]
+		for _, q := range drawn[i] {
+			used[q.ID] = true
+		}
+	}
+
+	out := make([]models.Question, 0, bp.TotalQuestions)
+	for _, qs := range drawn {
+		out = append(out, qs...)
+	}
+	return out, nil
+}
+
+func specificity(s models.ExamSection) int {
+	level := 1
+	switch {
+	case s.SubtopicID != nil:
+		level = 3
+	case s.TopicID != nil:
+		level = 2
+	}
+	if s.Difficulty != "" {
+		return level*2 + 1
+	}
+	return level * 2
+}
+
+func sectionFilters(bp models.ExamBlueprint, s models.ExamSection) map[string]interface{} {
+	filters := map[string]interface{}{}
+	if bp.GradeID != nil {
+		filters["gradeId"] = *bp.GradeID
+	}
+	if s.LessonID != nil {
+		filters["lessonId"] = *s.LessonID
+	}
+	if s.TopicID != nil {
+		filters["topicId"] = *s.TopicID
+	}
+	if s.SubtopicID != nil {
+		filters["subtopicId"] = *s.SubtopicID
+	}
+	if s.Difficulty != "" {
+		filters["difficulty"] = s.Difficulty
+	}
+	return filters
+}
+
+func sectionName(s models.ExamSection, i int) string {
+	if name := strings.TrimSpace(s.Name); name != "" {
+		return name
+	}
+	return fmt.Sprintf("section %d", i+1)
+}
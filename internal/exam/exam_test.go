@@ -0,0 +1,156 @@
+package exam
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/irt"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+func intPtr(i int) *int { return &i }
+
+// fakeSource serves a bank of questions spread over lessons 1 and 2, with
+// lesson 1 split into topics 10 and 11. The questions of lesson 2 are
+// calibrated, cycling through hard, easy and medium.
+type fakeSource struct{ bank []models.Question }
+
+func newFakeSource() *fakeSource {
+	src := &fakeSource{}
+	for id := 1; id <= 40; id++ {
+		q := models.Question{ID: id, LessonID: 1}
+		switch {
+		case id <= 10:
+			q.TopicID = intPtr(10)
+		case id <= 20:
+			q.TopicID = intPtr(11)
+		default:
+			q.LessonID = 2
+			b := []float64{2, -2, 0}[id%3]
+			q.Difficulty = &b
+		}
+		src.bank = append(src.bank, q)
+	}
+	return src
+}
+
+func (s *fakeSource) GetList(filters map[string]interface{}, page, pageSize int) ([]models.Question, error) {
+	var out []models.Question
+	for _, q := range s.bank {
+		if v, ok := filters["lessonId"]; ok && q.LessonID != v {
+			continue
+		}
+		if v, ok := filters["topicId"]; ok && (q.TopicID == nil || *q.TopicID != v) {
+			continue
+		}
+		if v, ok := filters["difficulty"]; ok && (q.Difficulty == nil || irt.Band(*q.Difficulty) != v) {
+			continue
+		}
+		out = append(out, q)
+	}
+	return out, nil
+}
+
+func blueprint(total int, sections ...models.ExamSection) models.ExamBlueprint {
+	return models.ExamBlueprint{
+		Name:            "Term test",
+		TotalQuestions:  total,
+		DurationMinutes: 60,
+		LatePolicy:      models.LatePolicyMark,
+		Sections:        sections,
+	}
+}
+
+func TestQuotasRoundByLargestRemainder(t *testing.T) {
+	bp := blueprint(10,
+		models.ExamSection{LessonID: intPtr(1), Percent: intPtr(33)},
+		models.ExamSection{LessonID: intPtr(2), Percent: intPtr(33)},
+		models.ExamSection{LessonID: intPtr(3), Percent: intPtr(34)},
+	)
+	assert.Equal(t, []int{3, 3, 4}, Quotas(bp))
+	assert.NoError(t, Validate(bp))
+
+	bp = blueprint(40,
+		models.ExamSection{LessonID: intPtr(1), Percent: intPtr(30)},
+		models.ExamSection{LessonID: intPtr(2), Percent: intPtr(20)},
+		models.ExamSection{LessonID: intPtr(3), Count: intPtr(20)},
+	)
+	assert.Equal(t, []int{12, 8, 20}, Quotas(bp))
+	assert.NoError(t, Validate(bp))
+}
+
+func TestValidateRejectsBadBlueprints(t *testing.T) {
+	short := blueprint(10, models.ExamSection{LessonID: intPtr(1), Count: intPtr(5)})
+	assert.EqualError(t, Validate(short), "sections add up to 5 questions, expected 10")
+
+	both := blueprint(5, models.ExamSection{LessonID: intPtr(1), Count: intPtr(5), Percent: intPtr(100)})
+	assert.Error(t, Validate(both))
+
+	unscoped := blueprint(5, models.ExamSection{Count: intPtr(5)})
+	assert.Error(t, Validate(unscoped))
+
+	band := blueprint(5, models.ExamSection{LessonID: intPtr(2), Difficulty: "tricky", Count: intPtr(5)})
+	assert.EqualError(t, Validate(band), "sections[0]: difficulty must be easy, medium or hard")
+
+	policy := blueprint(5, models.ExamSection{LessonID: intPtr(1), Count: intPtr(5)})
+	policy.LatePolicy = "ignore"
+	assert.Error(t, Validate(policy))
+}
+
+func TestGenerateIsReproducibleAndHasNoDuplicates(t *testing.T) {
+	bp := blueprint(15,
+		models.ExamSection{Name: "Lesson 1", LessonID: intPtr(1), Count: intPtr(8)},
+		models.ExamSection{Name: "Topic 10", LessonID: intPtr(1), TopicID: intPtr(10), Count: intPtr(7)},
+	)
+	src := newFakeSource()
+
+	first, err := Generate(bp, 42, src)
+	require.NoError(t, err)
+	again, err := Generate(bp, 42, src)
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+
+	other, err := Generate(bp, 43, src)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, other)
+
+	seen := map[int]bool{}
+	for i, q := range first {
+		assert.False(t, seen[q.ID], "question %d drawn twice", q.ID)
+		seen[q.ID] = true
+		assert.Equal(t, 1, q.LessonID)
+		if i >= 8 {
+			assert.Equal(t, 10, *q.TopicID, "the topic section keeps blueprint order")
+		}
+	}
+	assert.Len(t, first, 15)
+}
+
+func TestGenerateReportsShortage(t *testing.T) {
+	bp := blueprint(25, models.ExamSection{Name: "Lesson 2", LessonID: intPtr(2), Count: intPtr(25)})
+	_, err := Generate(bp, 1, newFakeSource())
+
+	var shortage *ShortageError
+	require.ErrorAs(t, err, &shortage)
+	assert.Equal(t, "Lesson 2", shortage.Section)
+	assert.Equal(t, 20, shortage.Have)
+}
+
+func TestGenerateMixesDifficulties(t *testing.T) {
+	bp := blueprint(10,
+		models.ExamSection{Name: "Warm up", LessonID: intPtr(2), Difficulty: irt.BandEasy, Percent: intPtr(30)},
+		models.ExamSection{Name: "Core", LessonID: intPtr(2), Difficulty: irt.BandMedium, Percent: intPtr(40)},
+		models.ExamSection{Name: "Stretch", LessonID: intPtr(2), Difficulty: irt.BandHard, Percent: intPtr(30)},
+	)
+	require.NoError(t, Validate(bp))
+
+	questions, err := Generate(bp, 7, newFakeSource())
+	require.NoError(t, err)
+	require.Len(t, questions, 10)
+	bands := make([]string, 0, len(questions))
+	for _, q := range questions {
+		bands = append(bands, irt.Band(*q.Difficulty))
+	}
+	assert.Equal(t, []string{"easy", "easy", "easy", "medium", "medium", "medium", "medium", "hard", "hard", "hard"}, bands)
+}
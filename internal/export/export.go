@@ -0,0 +1,212 @@
+// Package export writes the MCQ bank in formats LMSes can import: Moodle
+// XML, GIFT and IMS QTI 2.1 content packages.
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/quiz"
+)
+
+const (
+	FormatMoodle = "moodle"
+	FormatGIFT   = "gift"
+	FormatQTI    = "qti"
+)
+
+// batchSize is how many questions are read from the database at a time, so
+// exports of the whole bank never hold it in memory.
+const batchSize = 200
+
+// Source is the subset of repository.QuestionRepository used for exports.
+type Source interface {
+	GetList(filters map[string]interface{}, page, pageSize int) ([]models.Question, error)
+}
+
+type encoder interface {
+	begin() error
+	// supports reports whether the format can carry q's question type.
+	supports(q models.Question) bool
+	question(q models.Question) error
+	// end finishes the file, listing the questions that were left out.
+	end(skipped []int) error
+}
+
+// blankMarker is how a fill in the blank question marks each gap, as the
+// validator accepts it.
+var blankMarker = regexp.MustCompile(`_{3,}`)
+
+// ContentType returns the MIME type and download file name for a format, or
+// ok=false when the format is unknown.
+func ContentType(format string) (contentType, filename string, ok bool) {
+	switch format {
+	case FormatMoodle:
+		return "application/xml; charset=utf-8", "questions-moodle.xml", true
+	case FormatGIFT:
+		return "text/plain; charset=utf-8", "questions.gift.txt", true
+	case FormatQTI:
+		return "application/zip", "questions-qti21.zip", true
+	}
+	return "", "", false
+}
+
+// Write streams every question matching filters to w in the given format.
+// The first batch is read before anything is written, so a failing query is
+// reported before the response has started. Questions of a type the format
+// cannot carry are left out and listed in a note at the end of the file.
+func Write(ctx context.Context, w io.Writer, format string, src Source, filters map[string]interface{}) error {
+	var enc encoder
+	switch format {
+	case FormatMoodle:
+		enc = newMoodleEncoder(w)
+	case FormatGIFT:
+		enc = newGIFTEncoder(w)
+	case FormatQTI:
+		enc = newQTIEncoder(w)
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+
+	page := 1
+	batch, err := src.GetList(filters, page, batchSize)
+	if err != nil {
+		return err
+	}
+	if err := enc.begin(); err != nil {
+		return err
+	}
+	var skipped []int
+	for {
+		for _, q := range batch {
+			if !enc.supports(q) {
+				skipped = append(skipped, q.ID)
+				continue
+			}
+			if err := enc.question(q); err != nil {
+				return err
+			}
+		}
+		if len(batch) < batchSize {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page++
+		if batch, err = src.GetList(filters, page, batchSize); err != nil {
+			return err
+		}
+	}
+	return enc.end(skipped)
+}
+
+// keyed reports whether q has the answer spec its type is marked by. Single
+// choice and true/false questions are keyed by CorrectAnswer instead.
+func keyed(q models.Question) bool {
+	a := q.Answer
+	switch quiz.Type(q) {
+	case models.QuestionTypeSingleChoice, models.QuestionTypeTrueFalse:
+		return true
+	case models.QuestionTypeMultiSelect:
+		return a != nil && len(a.Correct) > 0
+	case models.QuestionTypeNumeric:
+		return a != nil && a.Number != nil
+	case models.QuestionTypeFillBlank:
+		return a != nil && len(a.Blanks) > 0 && len(a.Blanks) == len(blankMarker.FindAllString(q.Question, -1))
+	case models.QuestionTypeMatching:
+		return a != nil && len(a.Pairs) > 0
+	}
+	return false
+}
+
+// skippedNote lists the questions a format could not carry.
+func skippedNote(skipped []int) string {
+	names := make([]string, len(skipped))
+	for i, id := range skipped {
+		names[i] = questionName(models.Question{ID: id})
+	}
+	return "Not exported, this format has no encoding for their question type: " + strings.Join(names, ", ")
+}
+
+// options returns the correct answer followed by the distinct distractors.
+// Multi select questions use multiOptions.
+func options(q models.Question) (correct string, wrong []string) {
+	if quiz.Type(q) == models.QuestionTypeTrueFalse {
+		if correct = quiz.AnswerText(q); correct == quiz.True {
+			return correct, []string{quiz.False}
+		}
+		return correct, []string{quiz.True}
+	}
+	correct = strings.TrimSpace(q.CorrectAnswer)
+	seen := map[string]bool{strings.ToLower(correct): true}
+	for _, a := range q.OtherAnswers {
+		a = strings.TrimSpace(a)
+		key := strings.ToLower(a)
+		if a == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		wrong = append(wrong, a)
+	}
+	return correct, wrong
+}
+
+// multiOptions returns the distinct correct options of a multi select
+// question and the distinct distractors that are not among them.
+func multiOptions(q models.Question) (correct, wrong []string) {
+	seen := map[string]bool{}
+	add := func(to []string, values []string) []string {
+		for _, a := range values {
+			a = strings.TrimSpace(a)
+			key := strings.ToLower(a)
+			if a == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			to = append(to, a)
+		}
+		return to
+	}
+	correct = add(nil, q.Answer.Correct)
+	return correct, add(nil, q.OtherAnswers)
+}
+
+// percent formats the share of the marks 100/n for the formats that weigh
+// each correct option, to the five decimals Moodle lists its grades with.
+func percent(n int) string {
+	return strconv.FormatFloat(math.Round(1e7/float64(n))/1e5, 'f', -1, 64)
+}
+
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// feedback joins the solution and theory into the general feedback text.
+func feedback(q models.Question) string {
+	parts := make([]string, 0, 2)
+	if q.Solution != nil && strings.TrimSpace(*q.Solution) != "" {
+		parts = append(parts, strings.TrimSpace(*q.Solution))
+	}
+	if q.Theory != nil && strings.TrimSpace(*q.Theory) != "" {
+		parts = append(parts, strings.TrimSpace(*q.Theory))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func imageURL(q models.Question) string {
+	if q.QuestionImg == nil {
+		return ""
+	}
+	return strings.TrimSpace(*q.QuestionImg)
+}
+
+func questionName(q models.Question) string {
+	return fmt.Sprintf("Q%d", q.ID)
+}
@@ -0,0 +1,174 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+type fakeSource struct {
+	questions []models.Question
+}
+
+func (f fakeSource) GetList(_ map[string]interface{}, page, pageSize int) ([]models.Question, error) {
+	start := (page - 1) * pageSize
+	if start >= len(f.questions) {
+		return nil, nil
+	}
+	end := start + pageSize
+	if end > len(f.questions) {
+		end = len(f.questions)
+	}
+	return f.questions[start:end], nil
+}
+
+func sampleQuestions() []models.Question {
+	img := "https://example.com/square.svg"
+	solution := "P = 4 × 5 = 20 cm"
+	return []models.Question{{
+		ID:            7,
+		Question:      "Perimeter of a square with side 5 cm?",
+		QuestionImg:   &img,
+		CorrectAnswer: "20 cm",
+		Solution:      &solution,
+		OtherAnswers:  models.StringArray{"10 cm", "20 cm", "25 cm"},
+	}}
+}
+
+func TestWriteMoodleXML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(context.Background(), &buf, FormatMoodle, fakeSource{sampleQuestions()}, nil))
+
+	out := buf.String()
+	assert.Contains(t, out, `<question type="multichoice">`)
+	assert.Contains(t, out, `<answer fraction="100" format="html">`)
+	assert.Equal(t, 2, strings.Count(out, `fraction="0"`))
+	assert.Contains(t, out, `<img src="https://example.com/square.svg"`)
+	assert.Contains(t, out, "P = 4 × 5 = 20 cm")
+}
+
+func TestWriteGIFTEscapesSpecialCharacters(t *testing.T) {
+	questions := sampleQuestions()
+	questions[0].Question = "What is x if x = 2 {approx}?"
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(context.Background(), &buf, FormatGIFT, fakeSource{questions}, nil))
+
+	out := buf.String()
+	assert.Contains(t, out, "::Q7::[html]")
+	assert.Contains(t, out, `x \= 2 \{approx\}`)
+	assert.Contains(t, out, "\t=20 cm\n")
+	assert.Contains(t, out, "\t~10 cm\n")
+	assert.Contains(t, out, "\t####P \\= 4 × 5 \\= 20 cm\n")
+}
+
+func TestWriteQTIPackage(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(context.Background(), &buf, FormatQTI, fakeSource{sampleQuestions()}, nil))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	require.Contains(t, files, "imsmanifest.xml")
+	require.Contains(t, files, "items/q7.xml")
+	assert.Contains(t, files["imsmanifest.xml"], `href="items/q7.xml"`)
+
+	item := files["items/q7.xml"]
+	assert.Contains(t, item, `<correctResponse>`)
+	assert.Contains(t, item, `<simpleChoice identifier="A">20 cm</simpleChoice>`)
+	assert.Contains(t, item, `<img src="https://example.com/square.svg"`)
+	assert.Contains(t, item, `<modalFeedback outcomeIdentifier="FEEDBACK"`)
+}
+
+// typedQuestions has one question of each type keyed by an answer spec.
+func typedQuestions() []models.Question {
+	g := 9.8
+	return []models.Question{
+		{ID: 8, Type: models.QuestionTypeTrueFalse, Question: "A square has four sides.", CorrectAnswer: "True"},
+		{ID: 9, Type: models.QuestionTypeNumeric, Question: "g in m/s²?", CorrectAnswer: "9.8",
+			Answer: &models.AnswerSpec{Number: &g, Tolerance: 0.1, Unit: "m/s²"}},
+		{ID: 10, Type: models.QuestionTypeMultiSelect, Question: "Which are prime?",
+			OtherAnswers: models.StringArray{"4", "9"}, Answer: &models.AnswerSpec{Correct: []string{"2", "3", "5"}}},
+		{ID: 11, Type: models.QuestionTypeFillBlank, Question: "2 + 2 = ___",
+			Answer: &models.AnswerSpec{Blanks: [][]string{{"4", "four"}}}},
+		{ID: 12, Type: models.QuestionTypeFillBlank, Question: "___ + ___ = 4",
+			Answer: &models.AnswerSpec{Blanks: [][]string{{"2"}, {"2"}}}},
+		{ID: 13, Type: models.QuestionTypeMatching, Question: "Match the shapes.",
+			Answer: &models.AnswerSpec{Pairs: []models.MatchPair{{Left: "Triangle", Right: "3"}, {Left: "Square", Right: "4"}}}},
+	}
+}
+
+func TestWriteGIFTQuestionTypes(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(context.Background(), &buf, FormatGIFT, fakeSource{typedQuestions()}, nil))
+
+	out := buf.String()
+	assert.Contains(t, out, "::Q8::")
+	assert.Contains(t, out, "=True")
+	assert.Contains(t, out, "~False")
+	assert.Contains(t, out, "{#\n\t=9.8:0.1\n")
+	assert.Contains(t, out, "\t~%33.33333%2\n")
+	assert.Contains(t, out, "\t~%-100%9\n")
+	assert.Contains(t, out, "\t=4\n\t=four\n")
+	assert.Contains(t, out, "\t=Triangle -> 3\n")
+	assert.NotContains(t, out, "::Q12::")
+	assert.Contains(t, out, "// Not exported, this format has no encoding for their question type: Q12\n")
+}
+
+func TestWriteMoodleQuestionTypes(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(context.Background(), &buf, FormatMoodle, fakeSource{typedQuestions()}, nil))
+
+	out := buf.String()
+	assert.Contains(t, out, `<question type="numerical">`)
+	assert.Contains(t, out, "<tolerance>0.1</tolerance>")
+	assert.Contains(t, out, "<unit_name>m/s²</unit_name>")
+	assert.Contains(t, out, "<single>false</single>")
+	assert.Equal(t, 3, strings.Count(out, `fraction="33.33333"`))
+	assert.Equal(t, 2, strings.Count(out, `fraction="-100"`))
+	assert.Equal(t, 2, strings.Count(out, `<question type="cloze">`))
+	assert.Contains(t, out, "2 + 2 = {1:SHORTANSWER:=4~=four}")
+	assert.Contains(t, out, `<question type="matching">`)
+	assert.Contains(t, out, "<answer>\n        <text>3</text>")
+	assert.NotContains(t, out, "Not exported")
+}
+
+func TestWriteQTIListsSkippedQuestions(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(context.Background(), &buf, FormatQTI, fakeSource{typedQuestions()}, nil))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	item := files["items/q10.xml"]
+	assert.Contains(t, item, `cardinality="multiple"`)
+	assert.Contains(t, item, "<correctResponse>\n      <value>A</value>\n      <value>B</value>\n      <value>C</value>\n    </correctResponse>")
+	assert.Contains(t, item, `maxChoices="0"`)
+	assert.Contains(t, files, "items/q8.xml")
+	assert.NotContains(t, files, "items/q9.xml")
+	assert.Equal(t, "Not exported, this format has no encoding for their question type: Q9, Q11, Q12, Q13\n", files["skipped-questions.txt"])
+}
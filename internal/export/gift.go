@@ -0,0 +1,104 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/quiz"
+)
+
+// giftEscaper escapes the characters GIFT gives a meaning to.
+var giftEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`~`, `\~`,
+	`=`, `\=`,
+	`#`, `\#`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`:`, `\:`,
+)
+
+// giftEncoder writes GIFT text. Questions use the [html] markup so the image
+// URL can be carried as an <img> tag.
+type giftEncoder struct {
+	w io.Writer
+}
+
+func newGIFTEncoder(w io.Writer) *giftEncoder {
+	return &giftEncoder{w: w}
+}
+
+func (g *giftEncoder) begin() error {
+	_, err := io.WriteString(g.w, "// Exported from sl-edu-service\n\n")
+	return err
+}
+
+// supports takes every question type except fill in the blank with more
+// than one blank, since a GIFT question holds a single answer field.
+func (g *giftEncoder) supports(q models.Question) bool {
+	if quiz.Type(q) == models.QuestionTypeFillBlank && keyed(q) {
+		return len(q.Answer.Blanks) == 1
+	}
+	return keyed(q)
+}
+
+func (g *giftEncoder) question(q models.Question) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "::%s::[html]%s {", giftEscaper.Replace(questionName(q)), giftEscaper.Replace(questionHTML(q)))
+
+	switch quiz.Type(q) {
+	case models.QuestionTypeMultiSelect:
+		// each correct option earns its share; any wrong one costs them all
+		correct, wrong := multiOptions(q)
+		share := percent(len(correct))
+		b.WriteString("\n")
+		for _, a := range correct {
+			fmt.Fprintf(&b, "\t~%%%s%%%s\n", share, giftEscaper.Replace(htmlText(a)))
+		}
+		for _, a := range wrong {
+			fmt.Fprintf(&b, "\t~%%-100%%%s\n", giftEscaper.Replace(htmlText(a)))
+		}
+
+	case models.QuestionTypeNumeric:
+		spec := q.Answer
+		fmt.Fprintf(&b, "#\n\t=%s:%s\n", number(*spec.Number), number(spec.Tolerance))
+
+	case models.QuestionTypeFillBlank:
+		b.WriteString("\n")
+		for _, a := range q.Answer.Blanks[0] {
+			if a = strings.TrimSpace(a); a != "" {
+				fmt.Fprintf(&b, "\t=%s\n", giftEscaper.Replace(a))
+			}
+		}
+
+	case models.QuestionTypeMatching:
+		b.WriteString("\n")
+		for _, p := range q.Answer.Pairs {
+			fmt.Fprintf(&b, "\t=%s -> %s\n", giftEscaper.Replace(htmlText(strings.TrimSpace(p.Left))), giftEscaper.Replace(strings.TrimSpace(p.Right)))
+		}
+
+	default:
+		correct, wrong := options(q)
+		fmt.Fprintf(&b, "\n\t=%s\n", giftEscaper.Replace(htmlText(correct)))
+		for _, a := range wrong {
+			fmt.Fprintf(&b, "\t~%s\n", giftEscaper.Replace(htmlText(a)))
+		}
+	}
+	if fb := feedback(q); fb != "" {
+		fmt.Fprintf(&b, "\t####%s\n", giftEscaper.Replace(htmlText(fb)))
+	}
+	b.WriteString("}\n\n")
+
+	_, err := io.WriteString(g.w, b.String())
+	return err
+}
+
+func (g *giftEncoder) end(skipped []int) error {
+	if len(skipped) == 0 {
+		return nil
+	}
+	_, err := io.WriteString(g.w, "// "+skippedNote(skipped)+"\n")
+	return err
+}
@@ -0,0 +1,211 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/quiz"
+)
+
+type cdataText struct {
+	Value string `xml:",cdata"`
+}
+
+type moodleText struct {
+	Format string    `xml:"format,attr,omitempty"`
+	Text   cdataText `xml:"text"`
+}
+
+type moodleAnswer struct {
+	Fraction  string     `xml:"fraction,attr"`
+	Format    string     `xml:"format,attr"`
+	Text      cdataText  `xml:"text"`
+	Feedback  moodleText `xml:"feedback"`
+	Tolerance string     `xml:"tolerance,omitempty"`
+}
+
+type moodleSubquestion struct {
+	Format string    `xml:"format,attr"`
+	Text   cdataText `xml:"text"`
+	Answer struct {
+		Text string `xml:"text"`
+	} `xml:"answer"`
+}
+
+type moodleUnits struct {
+	Unit struct {
+		Multiplier string `xml:"multiplier"`
+		Name       string `xml:"unit_name"`
+	} `xml:"unit"`
+}
+
+// moodleQuestion holds the elements of every question type written; those a
+// type does not use are left empty and omitted.
+type moodleQuestion struct {
+	XMLName         xml.Name            `xml:"question"`
+	Type            string              `xml:"type,attr"`
+	Name            moodleText          `xml:"name"`
+	QuestionText    moodleText          `xml:"questiontext"`
+	GeneralFeedback moodleText          `xml:"generalfeedback"`
+	DefaultGrade    string              `xml:"defaultgrade"`
+	Penalty         string              `xml:"penalty"`
+	Hidden          int                 `xml:"hidden"`
+	Single          string              `xml:"single,omitempty"`
+	ShuffleAnswers  int                 `xml:"shuffleanswers,omitempty"`
+	AnswerNumbering string              `xml:"answernumbering,omitempty"`
+	Answers         []moodleAnswer      `xml:"answer"`
+	Subquestions    []moodleSubquestion `xml:"subquestion"`
+	Units           *moodleUnits        `xml:"units,omitempty"`
+}
+
+// moodleEncoder writes Moodle XML question bank files. Images are referenced
+// by URL from the question HTML.
+type moodleEncoder struct {
+	w   io.Writer
+	enc *xml.Encoder
+}
+
+func newMoodleEncoder(w io.Writer) *moodleEncoder {
+	enc := xml.NewEncoder(w)
+	enc.Indent("  ", "  ")
+	return &moodleEncoder{w: w, enc: enc}
+}
+
+func (m *moodleEncoder) begin() error {
+	_, err := io.WriteString(m.w, xml.Header+"<quiz>\n")
+	return err
+}
+
+// supports takes every question type: multi select as a multichoice
+// question with several answers, numeric as numerical, fill in the blank as
+// cloze and matching as matching.
+func (m *moodleEncoder) supports(q models.Question) bool {
+	return keyed(q)
+}
+
+func (m *moodleEncoder) question(q models.Question) error {
+	mq := moodleQuestion{
+		Type:            "multichoice",
+		Name:            moodleText{Text: cdataText{questionName(q)}},
+		QuestionText:    moodleText{Format: "html", Text: cdataText{questionHTML(q)}},
+		GeneralFeedback: moodleText{Format: "html", Text: cdataText{htmlText(feedback(q))}},
+		DefaultGrade:    "1.0000000",
+		Penalty:         "0.3333333",
+	}
+
+	switch quiz.Type(q) {
+	case models.QuestionTypeMultiSelect:
+		// each correct option earns its share; any wrong one costs them all
+		correct, wrong := multiOptions(q)
+		share := percent(len(correct))
+		for _, a := range correct {
+			mq.Answers = append(mq.Answers, moodleAnswer{Fraction: share, Format: "html", Text: cdataText{htmlText(a)}})
+		}
+		for _, a := range wrong {
+			mq.Answers = append(mq.Answers, moodleAnswer{Fraction: "-100", Format: "html", Text: cdataText{htmlText(a)}})
+		}
+		mq.Single, mq.ShuffleAnswers, mq.AnswerNumbering = "false", 1, "abc"
+
+	case models.QuestionTypeNumeric:
+		spec := q.Answer
+		mq.Type = "numerical"
+		mq.Answers = []moodleAnswer{{
+			Fraction:  "100",
+			Format:    "moodle_auto_format",
+			Text:      cdataText{number(*spec.Number)},
+			Tolerance: number(spec.Tolerance),
+		}}
+		if unit := strings.TrimSpace(spec.Unit); unit != "" {
+			mq.Units = &moodleUnits{}
+			mq.Units.Unit.Multiplier, mq.Units.Unit.Name = "1", unit
+		}
+
+	case models.QuestionTypeFillBlank:
+		mq.Type = "cloze"
+		mq.QuestionText.Text.Value = clozeHTML(q)
+
+	case models.QuestionTypeMatching:
+		mq.Type = "matching"
+		mq.ShuffleAnswers = 1
+		for _, p := range q.Answer.Pairs {
+			sub := moodleSubquestion{Format: "html", Text: cdataText{htmlText(strings.TrimSpace(p.Left))}}
+			sub.Answer.Text = strings.TrimSpace(p.Right)
+			mq.Subquestions = append(mq.Subquestions, sub)
+		}
+
+	default:
+		correct, wrong := options(q)
+		mq.Answers = append(mq.Answers, moodleAnswer{Fraction: "100", Format: "html", Text: cdataText{htmlText(correct)}})
+		for _, a := range wrong {
+			mq.Answers = append(mq.Answers, moodleAnswer{Fraction: "0", Format: "html", Text: cdataText{htmlText(a)}})
+		}
+		mq.Single, mq.ShuffleAnswers, mq.AnswerNumbering = "true", 1, "abc"
+	}
+	return m.enc.Encode(mq)
+}
+
+func (m *moodleEncoder) end(skipped []int) error {
+	if err := m.enc.Flush(); err != nil {
+		return err
+	}
+	tail := "\n</quiz>\n"
+	if len(skipped) > 0 {
+		tail = "\n  <!-- " + skippedNote(skipped) + " -->" + tail
+	}
+	_, err := io.WriteString(m.w, tail)
+	return err
+}
+
+// clozeEscaper escapes the characters the embedded answers syntax gives a
+// meaning to.
+var clozeEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`}`, `\}`,
+	`#`, `\#`,
+	`~`, `\~`,
+	`/`, `\/`,
+	`"`, `\"`,
+)
+
+// clozeHTML renders a fill in the blank question with each ___ replaced by
+// a short answer field accepting that blank's answers.
+func clozeHTML(q models.Question) string {
+	parts := blankMarker.Split(q.Question, -1)
+	var b strings.Builder
+	b.WriteString("<p>")
+	for i, part := range parts {
+		b.WriteString(htmlText(part))
+		if i == len(parts)-1 {
+			break
+		}
+		accepted := make([]string, 0, len(q.Answer.Blanks[i]))
+		for _, a := range q.Answer.Blanks[i] {
+			if a = strings.TrimSpace(a); a != "" {
+				accepted = append(accepted, "="+clozeEscaper.Replace(a))
+			}
+		}
+		fmt.Fprintf(&b, "{1:SHORTANSWER:%s}", strings.Join(accepted, "~"))
+	}
+	b.WriteString("</p>")
+	if img := imageURL(q); img != "" {
+		b.WriteString(`<p><img src="` + html.EscapeString(img) + `" alt=""></p>`)
+	}
+	return b.String()
+}
+
+// questionHTML renders the question text and image as HTML.
+func questionHTML(q models.Question) string {
+	out := "<p>" + htmlText(q.Question) + "</p>"
+	if img := imageURL(q); img != "" {
+		out += `<p><img src="` + html.EscapeString(img) + `" alt=""></p>`
+	}
+	return out
+}
+
+func htmlText(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "\n", "<br>")
+}
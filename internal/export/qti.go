@@ -0,0 +1,293 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/quiz"
+)
+
+const (
+	qtiNamespace = "http://www.imsglobal.org/xsd/imsqti_v2p1"
+	qtiSchema    = "http://www.imsglobal.org/xsd/imsqti_v2p1 http://www.imsglobal.org/xsd/qti/qtiv2p1/imsqti_v2p1.xsd"
+	cpNamespace  = "http://www.imsglobal.org/xsd/imscp_v1p1"
+	cpSchema     = "http://www.imsglobal.org/xsd/imscp_v1p1 http://www.imsglobal.org/xsd/qti/qtiv2p1/qtiv2p1_imscpv1p2_v1p0.xsd"
+	xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+type qtiValue struct {
+	BaseType string `xml:"baseType,attr,omitempty"`
+	Value    string `xml:",chardata"`
+}
+
+type qtiDeclaration struct {
+	XMLName      xml.Name
+	Identifier   string      `xml:"identifier,attr"`
+	Cardinality  string      `xml:"cardinality,attr"`
+	BaseType     string      `xml:"baseType,attr"`
+	Correct      *qtiCorrect `xml:"correctResponse,omitempty"`
+	DefaultValue *qtiValue   `xml:"defaultValue>value,omitempty"`
+}
+
+type qtiCorrect struct {
+	Values []qtiValue `xml:"value"`
+}
+
+type qtiImg struct {
+	Src string `xml:"src,attr"`
+	Alt string `xml:"alt,attr"`
+}
+
+type qtiP struct {
+	Text string  `xml:",chardata"`
+	Img  *qtiImg `xml:"img,omitempty"`
+}
+
+type qtiChoice struct {
+	Identifier string `xml:"identifier,attr"`
+	Text       string `xml:",chardata"`
+}
+
+type qtiItemBody struct {
+	Paragraphs  []qtiP `xml:"p"`
+	Interaction struct {
+		ResponseIdentifier string      `xml:"responseIdentifier,attr"`
+		Shuffle            bool        `xml:"shuffle,attr"`
+		MaxChoices         int         `xml:"maxChoices,attr"`
+		Choices            []qtiChoice `xml:"simpleChoice"`
+	} `xml:"choiceInteraction"`
+}
+
+type qtiSetOutcome struct {
+	Identifier string   `xml:"identifier,attr"`
+	Value      qtiValue `xml:"baseValue"`
+}
+
+type qtiResponseProcessing struct {
+	Condition struct {
+		If struct {
+			Match struct {
+				Variable struct {
+					Identifier string `xml:"identifier,attr"`
+				} `xml:"variable"`
+				Correct struct {
+					Identifier string `xml:"identifier,attr"`
+				} `xml:"correct"`
+			} `xml:"match"`
+			SetOutcome qtiSetOutcome `xml:"setOutcomeValue"`
+		} `xml:"responseIf"`
+		Else struct {
+			SetOutcome qtiSetOutcome `xml:"setOutcomeValue"`
+		} `xml:"responseElse"`
+	} `xml:"responseCondition"`
+	Feedback *qtiSetOutcome `xml:"setOutcomeValue,omitempty"`
+}
+
+type qtiModalFeedback struct {
+	OutcomeIdentifier string `xml:"outcomeIdentifier,attr"`
+	ShowHide          string `xml:"showHide,attr"`
+	Identifier        string `xml:"identifier,attr"`
+	Text              string `xml:",chardata"`
+}
+
+type qtiAssessmentItem struct {
+	XMLName            xml.Name              `xml:"assessmentItem"`
+	Xmlns              string                `xml:"xmlns,attr"`
+	XmlnsXsi           string                `xml:"xmlns:xsi,attr"`
+	SchemaLocation     string                `xml:"xsi:schemaLocation,attr"`
+	Identifier         string                `xml:"identifier,attr"`
+	Title              string                `xml:"title,attr"`
+	Adaptive           bool                  `xml:"adaptive,attr"`
+	TimeDependent      bool                  `xml:"timeDependent,attr"`
+	Declarations       []qtiDeclaration      `xml:",any"`
+	ItemBody           qtiItemBody           `xml:"itemBody"`
+	ResponseProcessing qtiResponseProcessing `xml:"responseProcessing"`
+	ModalFeedback      *qtiModalFeedback     `xml:"modalFeedback,omitempty"`
+}
+
+type qtiResource struct {
+	Identifier string `xml:"identifier,attr"`
+	Type       string `xml:"type,attr"`
+	Href       string `xml:"href,attr"`
+	File       struct {
+		Href string `xml:"href,attr"`
+	} `xml:"file"`
+}
+
+type qtiManifest struct {
+	XMLName        xml.Name      `xml:"manifest"`
+	Xmlns          string        `xml:"xmlns,attr"`
+	XmlnsXsi       string        `xml:"xmlns:xsi,attr"`
+	SchemaLocation string        `xml:"xsi:schemaLocation,attr"`
+	Identifier     string        `xml:"identifier,attr"`
+	Schema         string        `xml:"metadata>schema"`
+	SchemaVersion  string        `xml:"metadata>schemaversion"`
+	Organizations  struct{}      `xml:"organizations"`
+	Resources      []qtiResource `xml:"resources>resource"`
+}
+
+// qtiEncoder writes an IMS content package: one assessmentItem file per
+// question plus an imsmanifest.xml listing them. Images stay as remote URLs
+// referenced from the item body.
+type qtiEncoder struct {
+	zw        *zip.Writer
+	resources []qtiResource
+}
+
+func newQTIEncoder(w io.Writer) *qtiEncoder {
+	return &qtiEncoder{zw: zip.NewWriter(w)}
+}
+
+func (e *qtiEncoder) begin() error {
+	return nil
+}
+
+// supports takes the choice types: single choice, true/false and multi
+// select.
+func (e *qtiEncoder) supports(q models.Question) bool {
+	switch quiz.Type(q) {
+	case models.QuestionTypeSingleChoice, models.QuestionTypeTrueFalse, models.QuestionTypeMultiSelect:
+		return keyed(q)
+	}
+	return false
+}
+
+func (e *qtiEncoder) question(q models.Question) error {
+	cardinality, maxChoices := "single", 1
+	var correct, wrong []string
+	if quiz.Type(q) == models.QuestionTypeMultiSelect {
+		cardinality, maxChoices = "multiple", 0
+		correct, wrong = multiOptions(q)
+	} else {
+		answer, distractors := options(q)
+		correct, wrong = []string{answer}, distractors
+	}
+	keys := make([]qtiValue, len(correct))
+	for i := range correct {
+		keys[i] = qtiValue{Value: choiceID(i)}
+	}
+	identifier := fmt.Sprintf("q%d", q.ID)
+	href := "items/" + identifier + ".xml"
+
+	item := qtiAssessmentItem{
+		Xmlns:          qtiNamespace,
+		XmlnsXsi:       xsiNamespace,
+		SchemaLocation: qtiSchema,
+		Identifier:     identifier,
+		Title:          questionName(q),
+		Declarations: []qtiDeclaration{
+			{
+				XMLName:     xml.Name{Local: "responseDeclaration"},
+				Identifier:  "RESPONSE",
+				Cardinality: cardinality,
+				BaseType:    "identifier",
+				Correct:     &qtiCorrect{Values: keys},
+			},
+			{
+				XMLName:      xml.Name{Local: "outcomeDeclaration"},
+				Identifier:   "SCORE",
+				Cardinality:  "single",
+				BaseType:     "float",
+				DefaultValue: &qtiValue{Value: "0"},
+			},
+			{
+				XMLName:     xml.Name{Local: "outcomeDeclaration"},
+				Identifier:  "FEEDBACK",
+				Cardinality: "single",
+				BaseType:    "identifier",
+			},
+		},
+	}
+
+	item.ItemBody.Paragraphs = []qtiP{{Text: q.Question}}
+	if img := imageURL(q); img != "" {
+		item.ItemBody.Paragraphs = append(item.ItemBody.Paragraphs, qtiP{Img: &qtiImg{Src: img}})
+	}
+	interaction := &item.ItemBody.Interaction
+	interaction.ResponseIdentifier = "RESPONSE"
+	interaction.Shuffle = true
+	interaction.MaxChoices = maxChoices
+	for i, a := range append(correct, wrong...) {
+		interaction.Choices = append(interaction.Choices, qtiChoice{Identifier: choiceID(i), Text: a})
+	}
+
+	rp := &item.ResponseProcessing
+	rp.Condition.If.Match.Variable.Identifier = "RESPONSE"
+	rp.Condition.If.Match.Correct.Identifier = "RESPONSE"
+	rp.Condition.If.SetOutcome = qtiSetOutcome{Identifier: "SCORE", Value: qtiValue{BaseType: "float", Value: "1"}}
+	rp.Condition.Else.SetOutcome = qtiSetOutcome{Identifier: "SCORE", Value: qtiValue{BaseType: "float", Value: "0"}}
+	if fb := feedback(q); fb != "" {
+		rp.Feedback = &qtiSetOutcome{Identifier: "FEEDBACK", Value: qtiValue{BaseType: "identifier", Value: "SOLUTION"}}
+		item.ModalFeedback = &qtiModalFeedback{
+			OutcomeIdentifier: "FEEDBACK",
+			ShowHide:          "show",
+			Identifier:        "SOLUTION",
+			Text:              fb,
+		}
+	}
+
+	if err := e.writeXML(href, item); err != nil {
+		return err
+	}
+
+	res := qtiResource{Identifier: identifier, Type: "imsqti_item_xmlv2p1", Href: href}
+	res.File.Href = href
+	e.resources = append(e.resources, res)
+	return nil
+}
+
+// end writes the manifest, and skipped-questions.txt next to it when some
+// questions were left out.
+func (e *qtiEncoder) end(skipped []int) error {
+	if len(skipped) > 0 {
+		f, err := e.zw.Create("skipped-questions.txt")
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, skippedNote(skipped)+"\n"); err != nil {
+			return err
+		}
+	}
+	manifest := qtiManifest{
+		Xmlns:          cpNamespace,
+		XmlnsXsi:       xsiNamespace,
+		SchemaLocation: cpSchema,
+		Identifier:     "MANIFEST-sl-edu-questions",
+		Schema:         "QTIv2.1 Package",
+		SchemaVersion:  "1.0.0",
+		Resources:      e.resources,
+	}
+	if err := e.writeXML("imsmanifest.xml", manifest); err != nil {
+		return err
+	}
+	return e.zw.Close()
+}
+
+func (e *qtiEncoder) writeXML(name string, v interface{}) error {
+	f, err := e.zw.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(f)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+// choiceID returns A, B, ... Z, AA, AB for the i-th choice (0-based).
+func choiceID(i int) string {
+	id := ""
+	for i >= 0 {
+		id = string(rune('A'+i%26)) + id
+		i = i/26 - 1
+	}
+	return id
+}
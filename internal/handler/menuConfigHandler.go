@@ -10,6 +10,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
 	menuconfigmodels "github.com/tharindulakmal/sl-edu-service/internal/models/menuconfig"
 	"github.com/tharindulakmal/sl-edu-service/internal/repository"
 	"github.com/tharindulakmal/sl-edu-service/internal/validator"
@@ -37,64 +39,81 @@ type (
 type PagedResponse[T any] = menuconfigmodels.PagedResponse[T]
 
 type Handler struct {
-	repo *repository.MenuConfigRepository
+	repo         *repository.MenuConfigRepository
+	translations i18n.Store
 }
 
-func NewHandler(repo *repository.MenuConfigRepository) *Handler {
-	return &Handler{repo: repo}
+func NewHandler(repo *repository.MenuConfigRepository, translations i18n.Store) *Handler {
+	return &Handler{repo: repo, translations: translations}
 }
 
 func RegisterAdminMenuConfigRoutes(group *gin.RouterGroup, db *sql.DB) {
 	repo := repository.NewMenuConfigRepository(db)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, repository.NewTranslationRepository(db))
 
 	group.GET("/grades", handler.listGrades)
 	group.POST("/grades", handler.createGrade)
 	group.GET("/grades/:id", handler.getGrade)
 	group.PUT("/grades/:id", handler.updateGrade)
 	group.DELETE("/grades/:id", handler.deleteGrade)
+	group.POST("/grades/:id/restore", handler.restoreGrade)
 
 	group.GET("/subjects", handler.listSubjects)
 	group.POST("/subjects", handler.createSubject)
 	group.GET("/subjects/:id", handler.getSubject)
 	group.PUT("/subjects/:id", handler.updateSubject)
 	group.DELETE("/subjects/:id", handler.deleteSubject)
+	group.POST("/subjects/:id/restore", handler.restoreSubject)
 
 	group.GET("/lessons", handler.listLessons)
 	group.POST("/lessons", handler.createLesson)
 	group.GET("/lessons/:id", handler.getLesson)
 	group.PUT("/lessons/:id", handler.updateLesson)
 	group.DELETE("/lessons/:id", handler.deleteLesson)
+	group.POST("/lessons/:id/restore", handler.restoreLesson)
 
 	group.GET("/topics", handler.listTopics)
 	group.POST("/topics", handler.createTopic)
 	group.GET("/topics/:id", handler.getTopic)
 	group.PUT("/topics/:id", handler.updateTopic)
 	group.DELETE("/topics/:id", handler.deleteTopic)
+	group.POST("/topics/:id/restore", handler.restoreTopic)
 
 	group.GET("/subtopics", handler.listSubtopics)
 	group.POST("/subtopics", handler.createSubtopic)
 	group.GET("/subtopics/:id", handler.getSubtopic)
 	group.PUT("/subtopics/:id", handler.updateSubtopic)
 	group.DELETE("/subtopics/:id", handler.deleteSubtopic)
+	group.POST("/subtopics/:id/restore", handler.restoreSubtopic)
 
 	group.GET("/tutors", handler.listTutors)
 	group.POST("/tutors", handler.createTutor)
 	group.GET("/tutors/:id", handler.getTutor)
 	group.PUT("/tutors/:id", handler.updateTutor)
 	group.DELETE("/tutors/:id", handler.deleteTutor)
+	group.POST("/tutors/:id/restore", handler.restoreTutor)
 
 	group.GET("/years", handler.listYears)
 	group.POST("/years", handler.createYear)
 	group.GET("/years/:id", handler.getYear)
 	group.PUT("/years/:id", handler.updateYear)
 	group.DELETE("/years/:id", handler.deleteYear)
+	group.POST("/years/:id/restore", handler.restoreYear)
+
+	group.GET("/past-papers", handler.listPastPapers)
+	group.POST("/past-papers", handler.createPastPaper)
+	group.GET("/past-papers/:id", handler.getPastPaper)
+	group.PUT("/past-papers/:id", handler.updatePastPaper)
+	group.DELETE("/past-papers/:id", handler.deletePastPaper)
 
 	group.GET("/tutorials", handler.listTutorials)
 	group.POST("/tutorials", handler.createTutorial)
 	group.GET("/tutorials/:id", handler.getTutorial)
 	group.PUT("/tutorials/:id", handler.updateTutorial)
 	group.DELETE("/tutorials/:id", handler.deleteTutorial)
+	group.POST("/tutorials/:id/restore", handler.restoreTutorial)
+
+	group.GET("/trash", handler.listTrash)
 }
 
 func (h *Handler) listGrades(c *gin.Context) {
@@ -107,7 +126,12 @@ func (h *Handler) listGrades(c *gin.Context) {
 	search := strings.TrimSpace(c.Query("search"))
 	grades, total, err := h.repo.ListGrades(c.Request.Context(), search, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.InternalError(c, err)
+		return
+	}
+
+	if err := localizeGrades(c, h.translations, grades); err != nil {
+		logging.InternalError(c, err)
 		return
 	}
 
@@ -137,13 +161,13 @@ func (h *Handler) createGrade(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateGradeUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		handleValidationError(c, err)
 		return
 	}
 
 	grade, err := h.repo.CreateGrade(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleRepoError(c, err)
 		return
 	}
 
@@ -163,7 +187,7 @@ func (h *Handler) updateGrade(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateGradeUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		handleValidationError(c, err)
 		return
 	}
 
@@ -183,7 +207,28 @@ func (h *Handler) deleteGrade(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.DeleteGrade(c.Request.Context(), id); err != nil {
+	cascade, err := parseCascade(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.DeleteGrade(c.Request.Context(), id, cascade); err != nil {
+		handleRepoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *Handler) restoreGrade(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.RestoreGrade(c.Request.Context(), id); err != nil {
 		handleRepoError(c, err)
 		return
 	}
@@ -212,7 +257,12 @@ func (h *Handler) listSubjects(c *gin.Context) {
 
 	subjects, total, err := h.repo.ListSubjects(c.Request.Context(), gradeID, search, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.InternalError(c, err)
+		return
+	}
+
+	if err := localizeSubjects(c, h.translations, subjects); err != nil {
+		logging.InternalError(c, err)
 		return
 	}
 
@@ -242,20 +292,13 @@ func (h *Handler) createSubject(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateSubjectUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	if ok, err := h.repo.CheckParentExists(c.Request.Context(), "grades", input.GradeID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	} else if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "grade not found"})
+		handleValidationError(c, err)
 		return
 	}
 
 	subject, err := h.repo.CreateSubject(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleRepoError(c, err)
 		return
 	}
 
@@ -275,14 +318,7 @@ func (h *Handler) updateSubject(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateSubjectUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	if ok, err := h.repo.CheckParentExists(c.Request.Context(), "grades", input.GradeID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	} else if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "grade not found"})
+		handleValidationError(c, err)
 		return
 	}
 
@@ -302,7 +338,28 @@ func (h *Handler) deleteSubject(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.DeleteSubject(c.Request.Context(), id); err != nil {
+	cascade, err := parseCascade(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.DeleteSubject(c.Request.Context(), id, cascade); err != nil {
+		handleRepoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *Handler) restoreSubject(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.RestoreSubject(c.Request.Context(), id); err != nil {
 		handleRepoError(c, err)
 		return
 	}
@@ -331,7 +388,12 @@ func (h *Handler) listLessons(c *gin.Context) {
 
 	lessons, total, err := h.repo.ListLessons(c.Request.Context(), subjectID, search, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.InternalError(c, err)
+		return
+	}
+
+	if err := localizeLessons(c, h.translations, lessons); err != nil {
+		logging.InternalError(c, err)
 		return
 	}
 
@@ -361,20 +423,13 @@ func (h *Handler) createLesson(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateLessonUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	if ok, err := h.repo.CheckParentExists(c.Request.Context(), "subjects", input.SubjectID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	} else if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "subject not found"})
+		handleValidationError(c, err)
 		return
 	}
 
 	lesson, err := h.repo.CreateLesson(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleRepoError(c, err)
 		return
 	}
 
@@ -394,14 +449,7 @@ func (h *Handler) updateLesson(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateLessonUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	if ok, err := h.repo.CheckParentExists(c.Request.Context(), "subjects", input.SubjectID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	} else if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "subject not found"})
+		handleValidationError(c, err)
 		return
 	}
 
@@ -421,7 +469,28 @@ func (h *Handler) deleteLesson(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.DeleteLesson(c.Request.Context(), id); err != nil {
+	cascade, err := parseCascade(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.DeleteLesson(c.Request.Context(), id, cascade); err != nil {
+		handleRepoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *Handler) restoreLesson(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.RestoreLesson(c.Request.Context(), id); err != nil {
 		handleRepoError(c, err)
 		return
 	}
@@ -450,7 +519,12 @@ func (h *Handler) listTopics(c *gin.Context) {
 
 	topics, total, err := h.repo.ListTopics(c.Request.Context(), lessonID, search, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.InternalError(c, err)
+		return
+	}
+
+	if err := localizeTopics(c, h.translations, topics); err != nil {
+		logging.InternalError(c, err)
 		return
 	}
 
@@ -480,20 +554,13 @@ func (h *Handler) createTopic(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateTopicUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	if ok, err := h.repo.CheckParentExists(c.Request.Context(), "lessons", input.LessonID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	} else if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "lesson not found"})
+		handleValidationError(c, err)
 		return
 	}
 
 	topic, err := h.repo.CreateTopic(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleRepoError(c, err)
 		return
 	}
 
@@ -513,14 +580,7 @@ func (h *Handler) updateTopic(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateTopicUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	if ok, err := h.repo.CheckParentExists(c.Request.Context(), "lessons", input.LessonID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	} else if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "lesson not found"})
+		handleValidationError(c, err)
 		return
 	}
 
@@ -540,7 +600,28 @@ func (h *Handler) deleteTopic(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.DeleteTopic(c.Request.Context(), id); err != nil {
+	cascade, err := parseCascade(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.DeleteTopic(c.Request.Context(), id, cascade); err != nil {
+		handleRepoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *Handler) restoreTopic(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.RestoreTopic(c.Request.Context(), id); err != nil {
 		handleRepoError(c, err)
 		return
 	}
@@ -569,7 +650,12 @@ func (h *Handler) listSubtopics(c *gin.Context) {
 
 	subtopics, total, err := h.repo.ListSubtopics(c.Request.Context(), topicID, search, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.InternalError(c, err)
+		return
+	}
+
+	if err := localizeSubtopics(c, h.translations, subtopics); err != nil {
+		logging.InternalError(c, err)
 		return
 	}
 
@@ -599,20 +685,13 @@ func (h *Handler) createSubtopic(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateSubtopicUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	if ok, err := h.repo.CheckParentExists(c.Request.Context(), "topics", input.TopicID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	} else if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "topic not found"})
+		handleValidationError(c, err)
 		return
 	}
 
 	subtopic, err := h.repo.CreateSubtopic(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleRepoError(c, err)
 		return
 	}
 
@@ -632,14 +711,7 @@ func (h *Handler) updateSubtopic(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateSubtopicUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	if ok, err := h.repo.CheckParentExists(c.Request.Context(), "topics", input.TopicID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	} else if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "topic not found"})
+		handleValidationError(c, err)
 		return
 	}
 
@@ -659,7 +731,28 @@ func (h *Handler) deleteSubtopic(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.DeleteSubtopic(c.Request.Context(), id); err != nil {
+	cascade, err := parseCascade(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.DeleteSubtopic(c.Request.Context(), id, cascade); err != nil {
+		handleRepoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *Handler) restoreSubtopic(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.RestoreSubtopic(c.Request.Context(), id); err != nil {
 		handleRepoError(c, err)
 		return
 	}
@@ -677,7 +770,7 @@ func (h *Handler) listTutors(c *gin.Context) {
 	search := strings.TrimSpace(c.Query("search"))
 	tutors, total, err := h.repo.ListTutors(c.Request.Context(), search, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.InternalError(c, err)
 		return
 	}
 
@@ -707,13 +800,13 @@ func (h *Handler) createTutor(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateTutorUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		handleValidationError(c, err)
 		return
 	}
 
 	tutor, err := h.repo.CreateTutor(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleRepoError(c, err)
 		return
 	}
 
@@ -733,7 +826,7 @@ func (h *Handler) updateTutor(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateTutorUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		handleValidationError(c, err)
 		return
 	}
 
@@ -753,7 +846,28 @@ func (h *Handler) deleteTutor(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.DeleteTutor(c.Request.Context(), id); err != nil {
+	cascade, err := parseCascade(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.DeleteTutor(c.Request.Context(), id, cascade); err != nil {
+		handleRepoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *Handler) restoreTutor(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.RestoreTutor(c.Request.Context(), id); err != nil {
 		handleRepoError(c, err)
 		return
 	}
@@ -771,7 +885,7 @@ func (h *Handler) listYears(c *gin.Context) {
 	search := strings.TrimSpace(c.Query("search"))
 	years, total, err := h.repo.ListYears(c.Request.Context(), search, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.InternalError(c, err)
 		return
 	}
 
@@ -801,13 +915,13 @@ func (h *Handler) createYear(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateYearUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		handleValidationError(c, err)
 		return
 	}
 
 	year, err := h.repo.CreateYear(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleRepoError(c, err)
 		return
 	}
 
@@ -827,7 +941,7 @@ func (h *Handler) updateYear(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateYearUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		handleValidationError(c, err)
 		return
 	}
 
@@ -847,7 +961,28 @@ func (h *Handler) deleteYear(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.DeleteYear(c.Request.Context(), id); err != nil {
+	cascade, err := parseCascade(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.DeleteYear(c.Request.Context(), id, cascade); err != nil {
+		handleRepoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *Handler) restoreYear(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.RestoreYear(c.Request.Context(), id); err != nil {
 		handleRepoError(c, err)
 		return
 	}
@@ -865,7 +1000,7 @@ func (h *Handler) listTutorials(c *gin.Context) {
 	search := strings.TrimSpace(c.Query("search"))
 	tutorials, total, err := h.repo.ListTutorials(c.Request.Context(), search, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.InternalError(c, err)
 		return
 	}
 
@@ -895,13 +1030,13 @@ func (h *Handler) createTutorial(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateTutorialUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		handleValidationError(c, err)
 		return
 	}
 
 	tutorial, err := h.repo.CreateTutorial(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleRepoError(c, err)
 		return
 	}
 
@@ -921,7 +1056,7 @@ func (h *Handler) updateTutorial(c *gin.Context) {
 		return
 	}
 	if err := validator.ValidateTutorialUpsert(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		handleValidationError(c, err)
 		return
 	}
 
@@ -941,7 +1076,28 @@ func (h *Handler) deleteTutorial(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.DeleteTutorial(c.Request.Context(), id); err != nil {
+	cascade, err := parseCascade(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.DeleteTutorial(c.Request.Context(), id, cascade); err != nil {
+		handleRepoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *Handler) restoreTutorial(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.RestoreTutorial(c.Request.Context(), id); err != nil {
 		handleRepoError(c, err)
 		return
 	}
@@ -949,6 +1105,27 @@ func (h *Handler) deleteTutorial(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
 
+func (h *Handler) listTrash(c *gin.Context) {
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entity := strings.TrimSpace(c.Query("entity"))
+	items, total, err := h.repo.ListTrash(c.Request.Context(), entity, page, pageSize)
+	if err != nil {
+		if errors.Is(err, repository.ErrMenuConfigUnknownEntity) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid entity parameter"})
+			return
+		}
+		logging.InternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PagedResponse[menuconfigmodels.TrashItem]{Data: items, TotalCount: total})
+}
+
 func parsePagination(c *gin.Context) (int, int, error) {
 	pageStr := strings.TrimSpace(c.DefaultQuery("page", "1"))
 	pageSizeStr := strings.TrimSpace(c.DefaultQuery("pageSize", "10"))
@@ -973,6 +1150,20 @@ func parsePagination(c *gin.Context) (int, int, error) {
 	return page, pageSize, nil
 }
 
+// parseCascade reads the cascade flag of a delete, which trashes the row
+// even though other rows still point at it.
+func parseCascade(c *gin.Context) (bool, error) {
+	raw := strings.TrimSpace(c.Query("cascade"))
+	if raw == "" {
+		return false, nil
+	}
+	cascade, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid cascade parameter")
+	}
+	return cascade, nil
+}
+
 func parseIDParam(c *gin.Context) (int64, error) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -990,5 +1181,49 @@ func handleRepoError(c *gin.Context, err error) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
 		return
 	}
-	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	var dependents *repository.DependentsError
+	if errors.As(err, &dependents) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "resource is still in use; pass cascade=true to delete it anyway",
+			"dependents": dependents.Dependents,
+		})
+		return
+	}
+	if errors.Is(err, repository.ErrMenuConfigParentDeleted) {
+		c.JSON(http.StatusConflict, gin.H{"error": "parent is in the trash; restore it first"})
+		return
+	}
+	var missing *repository.MissingParentError
+	if errors.As(err, &missing) {
+		handleValidationError(c, validator.FieldErrors{{Field: missing.Field, Message: "does not match an existing " + missing.Entity}})
+		return
+	}
+	var duplicate *repository.DuplicateError
+	if errors.As(err, &duplicate) {
+		fields := validator.FieldErrors{{Field: duplicate.Field, Message: "is already taken"}}
+		c.JSON(http.StatusConflict, fieldErrorBody(fields))
+		return
+	}
+	logging.InternalError(c, err)
+}
+
+// handleValidationError writes a 400 for a request body that failed
+// validation, with one message per field when the validator gave them.
+func handleValidationError(c *gin.Context, err error) {
+	var fields validator.FieldErrors
+	if errors.As(err, &fields) {
+		c.JSON(http.StatusBadRequest, fieldErrorBody(fields))
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// fieldErrorBody keeps the single error string older clients read next to
+// the per-field messages, keyed by JSON field name.
+func fieldErrorBody(fields validator.FieldErrors) gin.H {
+	byField := make(map[string]string, len(fields))
+	for _, f := range fields {
+		byField[f.Field] = f.Message
+	}
+	return gin.H{"error": fields.Error(), "fields": byField}
 }
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	menuconfigmodels "github.com/tharindulakmal/sl-edu-service/internal/models/menuconfig"
+	"github.com/tharindulakmal/sl-edu-service/internal/validator"
+)
+
+type (
+	PastPaper       = menuconfigmodels.PastPaper
+	PastPaperUpsert = menuconfigmodels.PastPaperUpsert
+)
+
+func (h *Handler) listPastPapers(c *gin.Context) {
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter, err := parsePastPaperFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	papers, total, err := h.repo.ListPastPapers(c.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PagedResponse[PastPaper]{Data: papers, TotalCount: total})
+}
+
+func (h *Handler) getPastPaper(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	paper, err := h.repo.GetPastPaper(c.Request.Context(), id)
+	if err != nil {
+		handleRepoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paper)
+}
+
+func (h *Handler) createPastPaper(c *gin.Context) {
+	var input PastPaperUpsert
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validator.ValidatePastPaperUpsert(input); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	paper, err := h.repo.CreatePastPaper(c.Request.Context(), input)
+	if err != nil {
+		handleRepoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, paper)
+}
+
+func (h *Handler) updatePastPaper(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var input PastPaperUpsert
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validator.ValidatePastPaperUpsert(input); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	paper, err := h.repo.UpdatePastPaper(c.Request.Context(), id, input)
+	if err != nil {
+		handleRepoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, paper)
+}
+
+func (h *Handler) deletePastPaper(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.DeletePastPaper(c.Request.Context(), id); err != nil {
+		handleRepoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// parsePastPaperFilter reads the optional examType, yearId and subjectId
+// query parameters.
+func parsePastPaperFilter(c *gin.Context) (menuconfigmodels.PastPaperFilter, error) {
+	filter := menuconfigmodels.PastPaperFilter{ExamType: strings.TrimSpace(c.Query("examType"))}
+	for _, p := range []struct {
+		key  string
+		dest *int64
+	}{{"yearId", &filter.YearID}, {"subjectId", &filter.SubjectID}} {
+		raw := strings.TrimSpace(c.Query(p.key))
+		if raw == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s parameter", p.key)
+		}
+		*p.dest = id
+	}
+	return filter, nil
+}
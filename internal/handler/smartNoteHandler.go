@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/auth"
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+	"github.com/tharindulakmal/sl-edu-service/internal/validator"
+)
+
+type SmartNoteHandler struct {
+	repo         *repository.SmartNoteAdminRepository
+	editorial    repository.EditorialRepository
+	translations i18n.Store
+}
+
+func NewSmartNoteHandler(repo *repository.SmartNoteAdminRepository, editorial repository.EditorialRepository, translations i18n.Store) *SmartNoteHandler {
+	return &SmartNoteHandler{repo: repo, editorial: editorial, translations: translations}
+}
+
+func RegisterAdminSmartNoteRoutes(group *gin.RouterGroup, db *sql.DB) {
+	handler := NewSmartNoteHandler(repository.NewSmartNoteAdminRepository(db), repository.NewEditorialRepository(db), repository.NewTranslationRepository(db))
+
+	group.GET("/smartnotes", handler.listSmartNotes)
+	group.POST("/smartnotes", handler.createSmartNote)
+	group.GET("/smartnotes/:id", handler.getSmartNote)
+	group.PUT("/smartnotes/:id", handler.updateSmartNote)
+	group.DELETE("/smartnotes/:id", handler.deleteSmartNote)
+	group.GET("/smartnotes/:id/revisions", handler.listSmartNoteRevisions)
+	group.POST("/smartnotes/:id/revisions/:revision/rollback", handler.rollbackSmartNote)
+	group.POST("/smartnotes/:id/status", handler.changeSmartNoteStatus)
+	group.POST("/smartnotes/:id/comments", handler.commentOnSmartNote)
+	group.GET("/smartnotes/:id/history", handler.smartNoteHistory)
+}
+
+func (h *SmartNoteHandler) listSmartNotes(c *gin.Context) {
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var scope [3]*int64
+	for i, name := range []string{"lessonId", "topicId", "subtopicId"} {
+		param := strings.TrimSpace(c.Query(name))
+		if param == "" {
+			continue
+		}
+		id, convErr := strconv.ParseInt(param, 10, 64)
+		if convErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+			return
+		}
+		scope[i] = &id
+	}
+
+	status := strings.TrimSpace(c.Query("status"))
+	if status != "" && !editorial.ValidStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+
+	notes, total, err := h.repo.List(c.Request.Context(), scope[0], scope[1], scope[2], status, page, pageSize)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+
+	if err := localizeSmartNotes(c, h.translations, notes); err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PagedResponse[models.SmartNoteRecord]{Data: notes, TotalCount: total})
+}
+
+func (h *SmartNoteHandler) getSmartNote(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	note, err := h.repo.Get(c.Request.Context(), id)
+	if err != nil {
+		handleSmartNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+func (h *SmartNoteHandler) createSmartNote(c *gin.Context) {
+	var input models.SmartNoteUpsert
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validator.ValidateSmartNoteUpsert(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	note, err := h.repo.Create(c.Request.Context(), input, editorID(c))
+	if err != nil {
+		handleSmartNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+func (h *SmartNoteHandler) updateSmartNote(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var input models.SmartNoteUpsert
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validator.ValidateSmartNoteUpsert(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	note, err := h.repo.Update(c.Request.Context(), id, input, editorID(c))
+	if err != nil {
+		handleSmartNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+func (h *SmartNoteHandler) deleteSmartNote(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.Delete(c.Request.Context(), id); err != nil {
+		handleSmartNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *SmartNoteHandler) listSmartNoteRevisions(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	revisions, err := h.repo.ListRevisions(c.Request.Context(), id)
+	if err != nil {
+		handleSmartNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": revisions})
+}
+
+func (h *SmartNoteHandler) rollbackSmartNote(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision"})
+		return
+	}
+
+	note, err := h.repo.Rollback(c.Request.Context(), id, revision, editorID(c))
+	if err != nil {
+		handleSmartNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+// changeSmartNoteStatus moves a note through review. Only published notes
+// are served by the public endpoints.
+func (h *SmartNoteHandler) changeSmartNoteStatus(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req models.StatusChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	role := ""
+	if claims := auth.ClaimsFrom(c); claims != nil {
+		role = claims.Role
+	}
+	event, err := h.editorial.Transition(c.Request.Context(), editorial.ContentSmartNote, int(id), req.Status, req.Comment, userID(c),
+		func(from string) error { return editorial.Check(from, req.Status, role) })
+	if err != nil {
+		handleSmartNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+func (h *SmartNoteHandler) commentOnSmartNote(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req models.EditorialCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if comment == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "comment is required"})
+		return
+	}
+
+	event, err := h.editorial.Comment(c.Request.Context(), editorial.ContentSmartNote, int(id), comment, userID(c))
+	if err != nil {
+		handleSmartNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, event)
+}
+
+func (h *SmartNoteHandler) smartNoteHistory(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := h.editorial.History(c.Request.Context(), editorial.ContentSmartNote, int(id))
+	if err != nil {
+		handleSmartNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": events})
+}
+
+// userID is editorID in the int form the review history uses.
+func userID(c *gin.Context) *int {
+	claims := auth.ClaimsFrom(c)
+	if claims == nil {
+		return nil
+	}
+	return &claims.UserID
+}
+
+// editorID returns the id of the signed-in user making the change.
+func editorID(c *gin.Context) *int64 {
+	claims := auth.ClaimsFrom(c)
+	if claims == nil {
+		return nil
+	}
+	id := int64(claims.UserID)
+	return &id
+}
+
+func handleSmartNoteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrSmartNoteNotFound), errors.Is(err, repository.ErrEditorialContentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "smart note not found"})
+	case errors.Is(err, repository.ErrSmartNoteRevisionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "revision not found"})
+	case errors.Is(err, repository.ErrSmartNoteInvalidScope):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lesson, topic and subtopic do not match"})
+	case errors.Is(err, editorial.ErrUnknownStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be draft, in_review, approved, published or retired"})
+	case errors.Is(err, editorial.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": "smart note cannot move to that status from its current one"})
+	case errors.Is(err, editorial.ErrNotPermitted):
+		c.JSON(http.StatusForbidden, gin.H{"error": "your role cannot make this status change"})
+	default:
+		logging.InternalError(c, err)
+	}
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+	"github.com/tharindulakmal/sl-edu-service/internal/validator"
+)
+
+type TranslationHandler struct {
+	repo *repository.TranslationRepository
+}
+
+func NewTranslationHandler(repo *repository.TranslationRepository) *TranslationHandler {
+	return &TranslationHandler{repo: repo}
+}
+
+func RegisterAdminTranslationRoutes(group *gin.RouterGroup, db *sql.DB) {
+	handler := NewTranslationHandler(repository.NewTranslationRepository(db))
+
+	group.GET("/translations/:entity/missing", handler.listMissingTranslations)
+	group.GET("/translations/:entity/:id", handler.listTranslations)
+	group.PUT("/translations/:entity/:id/:lang", handler.upsertTranslation)
+	group.DELETE("/translations/:entity/:id/:lang", handler.deleteTranslation)
+}
+
+func (h *TranslationHandler) listMissingTranslations(c *gin.Context) {
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lang := strings.TrimSpace(c.Query("lang"))
+	if !i18n.Valid(lang) || lang == i18n.Base {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lang must be " + i18n.Sinhala + " or " + i18n.Tamil})
+		return
+	}
+
+	missing, total, err := h.repo.ListMissing(c.Request.Context(), c.Param("entity"), lang, page, pageSize)
+	if err != nil {
+		handleTranslationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PagedResponse[models.MissingTranslation]{Data: missing, TotalCount: total})
+}
+
+func (h *TranslationHandler) listTranslations(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	translations, err := h.repo.List(c.Request.Context(), c.Param("entity"), id)
+	if err != nil {
+		handleTranslationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+func (h *TranslationHandler) upsertTranslation(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var fields models.TranslationFields
+	if err := c.ShouldBindJSON(&fields); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	input := models.Translation{
+		Entity:            c.Param("entity"),
+		EntityID:          id,
+		Lang:              c.Param("lang"),
+		TranslationFields: fields,
+		UpdatedBy:         editorID(c),
+	}
+	if err := validator.ValidateTranslation(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	translation, err := h.repo.Upsert(c.Request.Context(), input)
+	if err != nil {
+		handleTranslationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+func (h *TranslationHandler) deleteTranslation(c *gin.Context) {
+	id, err := parseIDParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.Delete(c.Request.Context(), c.Param("entity"), id, c.Param("lang")); err != nil {
+		handleTranslationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func handleTranslationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrTranslationEntityUnknown):
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown entity"})
+	case errors.Is(err, repository.ErrTranslationEntityNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
+	case errors.Is(err, repository.ErrTranslationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "translation not found"})
+	default:
+		logging.InternalError(c, err)
+	}
+}
+
+// The admin lists are shown in the editor's negotiated language. Single
+// records are always returned in the base language because they feed the
+// edit forms; translations are edited through /translations.
+
+func localizeGrades(c *gin.Context, store i18n.Store, grades []Grade) error {
+	return i18n.Localize(c.Request.Context(), store, models.EntityGrade, i18n.FromContext(c), grades,
+		func(g *Grade) int64 { return g.ID },
+		func(g *Grade, f models.TranslationFields) { i18n.Text(&g.Name, f.Name) })
+}
+
+func localizeSubjects(c *gin.Context, store i18n.Store, subjects []Subject) error {
+	return i18n.Localize(c.Request.Context(), store, models.EntitySubject, i18n.FromContext(c), subjects,
+		func(s *Subject) int64 { return s.ID },
+		func(s *Subject, f models.TranslationFields) { i18n.Text(&s.Name, f.Name) })
+}
+
+func localizeLessons(c *gin.Context, store i18n.Store, lessons []Lesson) error {
+	return i18n.Localize(c.Request.Context(), store, models.EntityLesson, i18n.FromContext(c), lessons,
+		func(l *Lesson) int64 { return l.ID },
+		func(l *Lesson, f models.TranslationFields) { i18n.Text(&l.Name, f.Name) })
+}
+
+func localizeTopics(c *gin.Context, store i18n.Store, topics []Topic) error {
+	return i18n.Localize(c.Request.Context(), store, models.EntityTopic, i18n.FromContext(c), topics,
+		func(t *Topic) int64 { return t.ID },
+		func(t *Topic, f models.TranslationFields) { i18n.Text(&t.Name, f.Name) })
+}
+
+func localizeSubtopics(c *gin.Context, store i18n.Store, subtopics []Subtopic) error {
+	return i18n.Localize(c.Request.Context(), store, models.EntitySubtopic, i18n.FromContext(c), subtopics,
+		func(s *Subtopic) int64 { return s.ID },
+		func(s *Subtopic, f models.TranslationFields) { i18n.Text(&s.Name, f.Name) })
+}
+
+func localizeSmartNotes(c *gin.Context, store i18n.Store, notes []models.SmartNoteRecord) error {
+	return i18n.Localize(c.Request.Context(), store, models.EntitySmartNote, i18n.FromContext(c), notes,
+		func(n *models.SmartNoteRecord) int64 { return n.ID },
+		func(n *models.SmartNoteRecord, f models.TranslationFields) {
+			i18n.Text(&n.SubTopicName, f.SubTopicName)
+			i18n.OptionalText(&n.Definition, f.Definition)
+			i18n.OptionalText(&n.Theory, f.Theory)
+			i18n.OptionalText(&n.Example, f.Example)
+		})
+}
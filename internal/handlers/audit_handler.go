@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+type AuditHandler struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditHandler(repo repository.AuditRepository) *AuditHandler {
+	return &AuditHandler{repo: repo}
+}
+
+// GET /api/v1/admin/audit?entityType=question&entityId=12&actorId=3&action=update&from=2025-01-01&to=2025-01-31&page=1&pageSize=20
+// Browses the record of changes to curriculum data and questions, newest
+// first.
+func (h *AuditHandler) List(c *gin.Context) {
+	filter := models.AuditFilter{
+		Action:     c.Query("action"),
+		EntityType: c.Query("entityType"),
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+	}
+	switch filter.Action {
+	case "", models.AuditCreate, models.AuditUpdate, models.AuditDelete, models.AuditRestore:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "action must be create, update or delete"})
+		return
+	}
+	if v := c.Query("actorId"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid actorId"})
+			return
+		}
+		filter.ActorID = &id
+	}
+	if v := c.Query("entityId"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid entityId"})
+			return
+		}
+		filter.EntityID = &id
+	}
+	for name, v := range map[string]string{"from": filter.From, "to": filter.To} {
+		if _, err := time.Parse(time.DateOnly, v); v != "" && err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be a date like 2025-01-31"})
+			return
+		}
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	entries, total, err := h.repo.List(c.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":       entries,
+		"page":       page,
+		"pageSize":   pageSize,
+		"totalCount": total,
+	})
+}
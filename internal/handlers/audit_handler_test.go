@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+type fakeAuditRepo struct {
+	filter models.AuditFilter
+}
+
+func (r *fakeAuditRepo) List(_ context.Context, filter models.AuditFilter, page, pageSize int) ([]models.AuditEntry, int, error) {
+	r.filter = filter
+	return []models.AuditEntry{}, 0, nil
+}
+
+func TestAuditList(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	router := gin.New()
+	router.GET("/audit", NewAuditHandler(repo).List)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/audit?entityType=question&entityId=12&actorId=3&action=update&from=2025-01-01", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "question", repo.filter.EntityType)
+	assert.Equal(t, int64(12), *repo.filter.EntityID)
+	assert.Equal(t, 3, *repo.filter.ActorID)
+	assert.Equal(t, models.AuditUpdate, repo.filter.Action)
+	assert.Equal(t, "2025-01-01", repo.filter.From)
+
+	for _, query := range []string{"action=rename", "entityId=x", "to=yesterday"} {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/audit?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/auth"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+type AuthHandler struct {
+	users  repository.UserRepository
+	tokens *auth.TokenManager
+}
+
+func NewAuthHandler(users repository.UserRepository, tokens *auth.TokenManager) *AuthHandler {
+	return &AuthHandler{users: users, tokens: tokens}
+}
+
+// POST /api/v1/auth/register
+// Self-registration always creates a student account.
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req models.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	user := models.User{Email: req.Email, Name: req.Name, Role: models.RoleStudent}
+	if err := validateUser(&user); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		handleAuthError(c, err)
+		return
+	}
+	user.PasswordHash = hash
+
+	id, err := h.users.Create(c.Request.Context(), &user)
+	if err != nil {
+		handleAuthError(c, err)
+		return
+	}
+	user.ID = int(id)
+	h.respondWithToken(c, http.StatusCreated, &user)
+}
+
+// POST /api/v1/auth/login
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.users.GetByEmail(c.Request.Context(), req.Email)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		logging.InternalError(c, err)
+		return
+	}
+	hash := auth.DummyPasswordHash
+	if user != nil {
+		hash = user.PasswordHash
+	}
+	if !auth.CheckPassword(hash, req.Password) || user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid email or password"})
+		return
+	}
+	h.respondWithToken(c, http.StatusOK, user)
+}
+
+// GET /api/v1/auth/me
+func (h *AuthHandler) Me(c *gin.Context) {
+	claims := auth.ClaimsFrom(c)
+	user, err := h.users.GetByID(c.Request.Context(), claims.UserID)
+	if err != nil {
+		handleAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// GET /api/v1/admin/users
+func (h *AuthHandler) ListUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	users, total, err := h.users.List(c.Request.Context(), page, pageSize)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": users, "totalCount": total})
+}
+
+// POST /api/v1/admin/users
+func (h *AuthHandler) CreateUser(c *gin.Context) {
+	var req models.UserUpsert
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	user := models.User{Email: req.Email, Name: req.Name, Role: req.Role, TutorID: req.TutorID}
+	if err := validateUser(&user); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		handleAuthError(c, err)
+		return
+	}
+	user.PasswordHash = hash
+
+	id, err := h.users.Create(c.Request.Context(), &user)
+	if err != nil {
+		handleAuthError(c, err)
+		return
+	}
+	created, err := h.users.GetByID(c.Request.Context(), int(id))
+	if err != nil {
+		handleAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// PUT /api/v1/admin/users/:id
+func (h *AuthHandler) UpdateUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	var req models.UserUpsert
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.users.GetByID(c.Request.Context(), id)
+	if err != nil {
+		handleAuthError(c, err)
+		return
+	}
+	user.Email, user.Name, user.Role, user.TutorID = req.Email, req.Name, req.Role, req.TutorID
+	if err := validateUser(user); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Password != "" {
+		hash, err := auth.HashPassword(req.Password)
+		if err != nil {
+			handleAuthError(c, err)
+			return
+		}
+		user.PasswordHash = hash
+	}
+
+	if err := h.users.Update(c.Request.Context(), user); err != nil {
+		handleAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AuthHandler) respondWithToken(c *gin.Context, status int, user *models.User) {
+	token, expires, err := h.tokens.Issue(auth.Claims{UserID: user.ID, Role: user.Role, TutorID: user.TutorID})
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	c.JSON(status, models.LoginResponse{
+		Token:     token,
+		ExpiresAt: expires.UTC().Format(time.RFC3339),
+		User:      *user,
+	})
+}
+
+func validateUser(u *models.User) error {
+	u.Email = strings.TrimSpace(u.Email)
+	u.Name = strings.TrimSpace(u.Name)
+	if u.Email == "" || !strings.Contains(u.Email, "@") {
+		return errors.New("a valid email is required")
+	}
+	if u.Name == "" {
+		return errors.New("name is required")
+	}
+	if !models.ValidRole(u.Role) {
+		return errors.New("role must be one of admin, content_editor, tutor, student")
+	}
+	if u.Role == models.RoleTutor && u.TutorID == nil {
+		return errors.New("tutorId is required for tutor accounts")
+	}
+	return nil
+}
+
+func handleAuthError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, auth.ErrWeakPassword):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	case errors.Is(err, repository.ErrUserEmailExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "email already registered"})
+	default:
+		logging.InternalError(c, err)
+	}
+}
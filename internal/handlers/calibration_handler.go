@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/irt"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+// defaultMinResponses is how many answers a question needs before its
+// statistics are trusted enough to flag it.
+const defaultMinResponses = 30
+
+type CalibrationHandler struct {
+	repo repository.CalibrationRepository
+}
+
+func NewCalibrationHandler(repo repository.CalibrationRepository) *CalibrationHandler {
+	return &CalibrationHandler{repo: repo}
+}
+
+// GET /api/v1/admin/questions/calibration?flag=negative_discrimination&lessonId=1&minResponses=30&page=1&pageSize=20
+// Lists questions that are too easy, too hard or negatively discriminating,
+// as of the last run of cmd/calibrate-questions.
+func (h *CalibrationHandler) Report(c *gin.Context) {
+	filter := models.CalibrationFilter{Flag: c.Query("flag"), MinResponses: defaultMinResponses}
+	switch filter.Flag {
+	case "", irt.FlagTooEasy, irt.FlagTooHard, irt.FlagNegativeDiscrimination:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "flag must be too_easy, too_hard or negative_discrimination"})
+		return
+	}
+	filter.LessonID, _ = strconv.Atoi(c.Query("lessonId"))
+	if v := c.Query("minResponses"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "minResponses must be a positive integer"})
+			return
+		}
+		filter.MinResponses = n
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	items, total, err := h.repo.Report(c.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":       items,
+		"page":       page,
+		"pageSize":   pageSize,
+		"totalCount": total,
+	})
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/config"
+)
+
+type ConfigHandler struct {
+	cfg config.Config
+}
+
+func NewConfigHandler(cfg config.Config) *ConfigHandler {
+	return &ConfigHandler{cfg: cfg.Redacted()}
+}
+
+// GET /api/v1/admin/config
+// Shows the configuration the server started with, secrets masked.
+func (h *ConfigHandler) Get(c *gin.Context) {
+	c.JSON(http.StatusOK, h.cfg)
+}
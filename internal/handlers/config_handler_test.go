@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/config"
+)
+
+func TestConfigGetRedactsSecrets(t *testing.T) {
+	cfg := config.Default()
+	cfg.DB.Password = "db-secret"
+	cfg.Auth.JWTSecret = "jwt-secret-jwt-secret-jwt-secret!"
+
+	router := gin.New()
+	router.GET("/config", NewConfigHandler(cfg).Get)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/config", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "db-secret")
+	assert.NotContains(t, w.Body.String(), "jwt-secret")
+	assert.Contains(t, w.Body.String(), `"connMaxLifetime":"5m0s"`)
+	assert.Contains(t, w.Body.String(), `"password":"[redacted]"`)
+}
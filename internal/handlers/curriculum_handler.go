@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/curriculum"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+type CurriculumHandler struct {
+	Repo         repository.CurriculumRepositoryInterface
+	Translations i18n.Store
+}
+
+func NewCurriculumHandler(repo repository.CurriculumRepositoryInterface, translations i18n.Store) *CurriculumHandler {
+	return &CurriculumHandler{Repo: repo, Translations: translations}
+}
+
+// GET /api/v1/curriculum/tree?gradeId=1&counts=true
+// GET /api/v1/curriculum/tree?subjectId=3
+func (h *CurriculumHandler) GetTree(c *gin.Context) {
+	var gradeID, subjectID *int64
+	for name, dest := range map[string]**int64{"gradeId": &gradeID, "subjectId": &subjectID} {
+		param := c.Query(name)
+		if param == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(param, 10, 64)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+			return
+		}
+		*dest = &id
+	}
+	if (gradeID == nil) == (subjectID == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of gradeId or subjectId is required"})
+		return
+	}
+	withCounts, _ := strconv.ParseBool(c.DefaultQuery("counts", "false"))
+
+	flat, err := h.Repo.LoadTree(c.Request.Context(), gradeID, subjectID, withCounts)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	if len(flat.Grades) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "grade or subject not found"})
+		return
+	}
+	if err := h.localize(c, flat); err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+
+	respondWithETag(c, curriculum.Build(flat))
+}
+
+// localize translates every level before the tree is assembled, one query
+// per level.
+func (h *CurriculumHandler) localize(c *gin.Context, flat *curriculum.Flat) error {
+	lang := i18n.FromContext(c)
+	for _, level := range []struct {
+		entity string
+		rows   []curriculum.Row
+	}{
+		{models.EntityGrade, flat.Grades},
+		{models.EntitySubject, flat.Subjects},
+		{models.EntityLesson, flat.Lessons},
+		{models.EntityTopic, flat.Topics},
+		{models.EntitySubtopic, flat.Subtopics},
+	} {
+		err := i18n.Localize(c.Request.Context(), h.Translations, level.entity, lang, level.rows,
+			func(r *curriculum.Row) int64 { return r.ID },
+			func(r *curriculum.Row, f models.TranslationFields) { i18n.Text(&r.Name, f.Name) })
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/curriculum"
+)
+
+type fakeCurriculumRepo struct {
+	flat curriculum.Flat
+}
+
+func (f fakeCurriculumRepo) LoadTree(context.Context, *int64, *int64, bool) (*curriculum.Flat, error) {
+	flat := f.flat
+	return &flat, nil
+}
+
+func TestGetTreeETag(t *testing.T) {
+	repo := fakeCurriculumRepo{flat: curriculum.Flat{
+		Grades:   []curriculum.Row{{ID: 1, Name: "Grade 6"}},
+		Subjects: []curriculum.Row{{ID: 2, ParentID: 1, Name: "Maths"}},
+	}}
+	router := gin.New()
+	router.GET("/tree", NewCurriculumHandler(repo, nil).GetTree)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tree?gradeId=1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"Maths"`)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req := httptest.NewRequest(http.MethodGet, "/tree?gradeId=1", nil)
+	req.Header.Set("If-None-Match", `"other", W/`+etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tree?gradeId=1&subjectId=2", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
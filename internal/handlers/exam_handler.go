@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/auth"
+	"github.com/tharindulakmal/sl-edu-service/internal/exam"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+type ExamHandler struct {
+	exams        repository.ExamRepository
+	questions    repository.QuestionRepository
+	translations i18n.Store
+}
+
+func NewExamHandler(exams repository.ExamRepository, questions repository.QuestionRepository, translations i18n.Store) *ExamHandler {
+	return &ExamHandler{exams: exams, questions: questions, translations: translations}
+}
+
+// GET /api/v1/exams/blueprints?page=1&pageSize=10
+func (h *ExamHandler) ListBlueprints(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	blueprints, total, err := h.exams.ListBlueprints(c.Request.Context(), page, pageSize)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":       blueprints,
+		"page":       page,
+		"pageSize":   pageSize,
+		"totalCount": total,
+	})
+}
+
+// GET /api/v1/exams/blueprints/:id
+func (h *ExamHandler) GetBlueprint(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blueprint id"})
+		return
+	}
+	bp, err := h.exams.GetBlueprint(c.Request.Context(), id)
+	if err != nil {
+		handleExamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, bp)
+}
+
+// POST /api/v1/exams/blueprints
+func (h *ExamHandler) CreateBlueprint(c *gin.Context) {
+	bp, ok := bindBlueprint(c)
+	if !ok {
+		return
+	}
+	if claims := auth.ClaimsFrom(c); claims != nil {
+		bp.CreatedBy = &claims.UserID
+	}
+
+	id, err := h.exams.CreateBlueprint(c.Request.Context(), bp)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	created, err := h.exams.GetBlueprint(c.Request.Context(), int(id))
+	if err != nil {
+		handleExamError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// PUT /api/v1/exams/blueprints/:id
+func (h *ExamHandler) UpdateBlueprint(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blueprint id"})
+		return
+	}
+	bp, ok := bindBlueprint(c)
+	if !ok || !h.authorizeOwner(c, id) {
+		return
+	}
+	bp.ID = id
+
+	if err := h.exams.UpdateBlueprint(c.Request.Context(), bp); err != nil {
+		handleExamError(c, err)
+		return
+	}
+	updated, err := h.exams.GetBlueprint(c.Request.Context(), id)
+	if err != nil {
+		handleExamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DELETE /api/v1/exams/blueprints/:id
+func (h *ExamHandler) DeleteBlueprint(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blueprint id"})
+		return
+	}
+	if !h.authorizeOwner(c, id) {
+		return
+	}
+	if err := h.exams.DeleteBlueprint(c.Request.Context(), id); err != nil {
+		handleExamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// GET /api/v1/exams/blueprints/:id/generate?seed=42
+//
+// Draws the exam with its answer key so teachers can review or print it.
+// Without a seed a new one is picked; it is returned so the same exam can
+// be generated again or started by students with the same seed.
+func (h *ExamHandler) Generate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blueprint id"})
+		return
+	}
+	seed := time.Now().UnixNano()
+	if v := c.Query("seed"); v != "" {
+		if seed, err = strconv.ParseInt(v, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid seed"})
+			return
+		}
+	}
+
+	bp, err := h.exams.GetBlueprint(c.Request.Context(), id)
+	if err != nil {
+		handleExamError(c, err)
+		return
+	}
+	questions, err := exam.Generate(*bp, seed, h.questions)
+	if err != nil {
+		handleExamError(c, err)
+		return
+	}
+	if err := localizeQuestions(c.Request.Context(), h.translations, i18n.FromContext(c), questions); err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.GeneratedExam{
+		BlueprintID:     bp.ID,
+		Seed:            seed,
+		DurationMinutes: bp.DurationMinutes,
+		Questions:       questions,
+	})
+}
+
+// authorizeOwner lets tutors change only the blueprints they created.
+func (h *ExamHandler) authorizeOwner(c *gin.Context, id int) bool {
+	claims := auth.ClaimsFrom(c)
+	if claims == nil || claims.Role != models.RoleTutor {
+		return true
+	}
+	existing, err := h.exams.GetBlueprint(c.Request.Context(), id)
+	if err != nil {
+		handleExamError(c, err)
+		return false
+	}
+	if existing.CreatedBy == nil || *existing.CreatedBy != claims.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only edit your own blueprints"})
+		return false
+	}
+	return true
+}
+
+func bindBlueprint(c *gin.Context) (*models.ExamBlueprint, bool) {
+	var bp models.ExamBlueprint
+	if err := c.ShouldBindJSON(&bp); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	bp.Name = strings.TrimSpace(bp.Name)
+	if bp.LatePolicy == "" {
+		bp.LatePolicy = models.LatePolicyMark
+	}
+	if err := exam.Validate(bp); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return &bp, true
+}
+
+func handleExamError(c *gin.Context, err error) {
+	var shortage *exam.ShortageError
+	switch {
+	case errors.Is(err, repository.ErrExamBlueprintNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "blueprint not found"})
+	case errors.As(err, &shortage):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		logging.InternalError(c, err)
+	}
+}
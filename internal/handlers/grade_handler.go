@@ -3,23 +3,34 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
 	"github.com/tharindulakmal/sl-edu-service/internal/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
 type GradeHandler struct {
-	Repo repository.GradeRepositoryInterface
+	Repo         repository.GradeRepositoryInterface
+	Translations i18n.Store
 }
 
-func NewGradeHandler(repo repository.GradeRepositoryInterface) *GradeHandler {
-	return &GradeHandler{Repo: repo}
+func NewGradeHandler(repo repository.GradeRepositoryInterface, translations i18n.Store) *GradeHandler {
+	return &GradeHandler{Repo: repo, Translations: translations}
 }
 
 func (h *GradeHandler) GetGrades(c *gin.Context) {
 	grades, err := h.Repo.GetAllGrades()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.InternalError(c, err)
+		return
+	}
+	err = i18n.Localize(c.Request.Context(), h.Translations, models.EntityGrade, i18n.FromContext(c), grades,
+		func(g *models.Grade) int64 { return int64(g.ID) },
+		func(g *models.Grade, f models.TranslationFields) { i18n.Text(&g.Grade, f.Name) })
+	if err != nil {
+		logging.InternalError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, grades)
@@ -21,7 +21,7 @@ func TestGetAllGrades(t *testing.T) {
 		Err: nil,
 	}
 
-	handler := NewGradeHandler(mockRepo)
+	handler := NewGradeHandler(mockRepo, nil)
 
 	router := gin.Default()
 	router.GET("/grades", handler.GetGrades)
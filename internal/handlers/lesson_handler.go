@@ -5,15 +5,19 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
 	"github.com/tharindulakmal/sl-edu-service/internal/repository"
 )
 
 type LessonHandler struct {
-	Repo repository.LessonRepositoryInterface
+	Repo         repository.LessonRepositoryInterface
+	Translations i18n.Store
 }
 
-func NewLessonHandler(repo repository.LessonRepositoryInterface) *LessonHandler {
-	return &LessonHandler{Repo: repo}
+func NewLessonHandler(repo repository.LessonRepositoryInterface, translations i18n.Store) *LessonHandler {
+	return &LessonHandler{Repo: repo, Translations: translations}
 }
 
 func (h *LessonHandler) GetLessons(c *gin.Context) {
@@ -26,7 +30,14 @@ func (h *LessonHandler) GetLessons(c *gin.Context) {
 
 	lessons, err := h.Repo.GetLessonsBySubject(subjectId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.InternalError(c, err)
+		return
+	}
+	err = i18n.Localize(c.Request.Context(), h.Translations, models.EntityLesson, i18n.FromContext(c), lessons,
+		func(l *repository.Lesson) int64 { return int64(l.ID) },
+		func(l *repository.Lesson, f models.TranslationFields) { i18n.Text(&l.Name, f.Name) })
+	if err != nil {
+		logging.InternalError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, lessons)
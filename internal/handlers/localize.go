@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/quiz"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+// localizeQuestions translates question text and answers: the answers of
+// single choice questions, and the answer spec of multi select, fill in the
+// blank and matching questions. True/false and numeric keys are language
+// independent.
+func localizeQuestions(ctx context.Context, store i18n.Store, lang string, questions []models.Question) error {
+	return i18n.Localize(ctx, store, models.EntityQuestion, lang, questions,
+		func(q *models.Question) int64 { return int64(q.ID) },
+		func(q *models.Question, f models.TranslationFields) {
+			i18n.Text(&q.Question, f.Question)
+			switch quiz.Type(*q) {
+			case models.QuestionTypeSingleChoice:
+				i18n.Text(&q.CorrectAnswer, f.CorrectAnswer)
+				if len(f.OtherAnswers) > 0 {
+					q.OtherAnswers = f.OtherAnswers
+				}
+			case models.QuestionTypeMultiSelect, models.QuestionTypeFillBlank, models.QuestionTypeMatching:
+				if spec, ok := translatedSpec(q.Type, q.Answer, f.Answer); ok {
+					q.Answer = spec
+					q.CorrectAnswer = quiz.AnswerText(*q)
+					if q.Type == models.QuestionTypeMultiSelect && len(f.OtherAnswers) > 0 {
+						q.OtherAnswers = f.OtherAnswers
+					}
+				}
+			}
+			i18n.OptionalText(&q.Theory, f.Theory)
+			i18n.OptionalText(&q.Solution, f.Solution)
+		})
+}
+
+// localizeQuizQuestions only touches the text and answer key. The options
+// were stored in the attempt's language when it was started, from the same
+// translation.
+func localizeQuizQuestions(ctx context.Context, store i18n.Store, lang string, questions []models.QuizQuestion) error {
+	return i18n.Localize(ctx, store, models.EntityQuestion, lang, questions,
+		func(q *models.QuizQuestion) int64 { return int64(q.QuestionID) },
+		func(q *models.QuizQuestion, f models.TranslationFields) {
+			i18n.Text(&q.Question, f.Question)
+			switch q.Type {
+			case "", models.QuestionTypeSingleChoice:
+				i18n.OptionalText(&q.CorrectAnswer, f.CorrectAnswer)
+			case models.QuestionTypeMultiSelect, models.QuestionTypeFillBlank, models.QuestionTypeMatching:
+				if spec, ok := translatedSpec(q.Type, q.Answer, f.Answer); ok {
+					q.Answer = spec
+					q.Prompts = spec.Prompts()
+					if q.CorrectAnswer != nil {
+						text := quiz.AnswerText(models.Question{Type: q.Type, Answer: spec})
+						q.CorrectAnswer = &text
+					}
+				}
+			}
+			i18n.OptionalText(&q.Theory, f.Theory)
+			i18n.OptionalText(&q.Solution, f.Solution)
+		})
+}
+
+// translatedSpec returns base with the translated part of the key of a
+// question of type typ taken from tr. It reports false, keeping the base
+// key, when there is no translation or it no longer fits the question: a
+// different number of blanks or pairs than the question has.
+func translatedSpec(typ string, base, tr *models.AnswerSpec) (*models.AnswerSpec, bool) {
+	if base == nil || tr == nil {
+		return nil, false
+	}
+	spec := *base
+	switch typ {
+	case models.QuestionTypeMultiSelect:
+		if len(tr.Correct) == 0 {
+			return nil, false
+		}
+		spec.Correct = tr.Correct
+	case models.QuestionTypeFillBlank:
+		if len(tr.Blanks) != len(base.Blanks) {
+			return nil, false
+		}
+		spec.Blanks = tr.Blanks
+	case models.QuestionTypeMatching:
+		if len(tr.Pairs) != len(base.Pairs) {
+			return nil, false
+		}
+		spec.Pairs = tr.Pairs
+	default:
+		return nil, false
+	}
+	return &spec, true
+}
+
+// localizedSource reads questions for an export in the requested language.
+type localizedSource struct {
+	ctx   context.Context
+	src   repository.QuestionRepository
+	store i18n.Store
+	lang  string
+}
+
+func (s localizedSource) GetList(filters map[string]interface{}, page, pageSize int) ([]models.Question, error) {
+	questions, err := s.src.GetList(filters, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return questions, localizeQuestions(s.ctx, s.store, s.lang, questions)
+}
+
+func localizeSmartNote(ctx context.Context, store i18n.Store, lang string, note *models.SmartNote) error {
+	if note.ID == 0 {
+		return nil
+	}
+	notes := []models.SmartNote{*note}
+	err := i18n.Localize(ctx, store, models.EntitySmartNote, lang, notes,
+		func(n *models.SmartNote) int64 { return int64(n.ID) },
+		func(n *models.SmartNote, f models.TranslationFields) {
+			i18n.Text(&n.SubTopicName, f.SubTopicName)
+			i18n.Text(&n.Definition, f.Definition)
+			i18n.Text(&n.Theory, f.Theory)
+			i18n.Text(&n.Example, f.Example)
+		})
+	*note = notes[0]
+	return err
+}
+
+// localizeReviewCards translates both sides of each card according to the
+// kind of item behind it.
+func localizeReviewCards(ctx context.Context, store i18n.Store, lang string, cards []models.ReviewCard) error {
+	for _, kind := range []struct {
+		cardType, entity string
+		apply            func(*models.ReviewCard, models.TranslationFields)
+	}{
+		{models.ReviewCardQuestion, models.EntityQuestion, func(c *models.ReviewCard, f models.TranslationFields) {
+			i18n.Text(&c.Prompt, f.Question)
+			i18n.OptionalText(&c.Answer, f.CorrectAnswer)
+			i18n.OptionalText(&c.Explanation, f.Solution)
+		}},
+		{models.ReviewCardSmartNote, models.EntitySmartNote, func(c *models.ReviewCard, f models.TranslationFields) {
+			i18n.Text(&c.Prompt, f.SubTopicName)
+			i18n.OptionalText(&c.Answer, f.Definition)
+			i18n.OptionalText(&c.Explanation, f.Theory)
+		}},
+	} {
+		var picked []*models.ReviewCard
+		for i := range cards {
+			if cards[i].CardType == kind.cardType {
+				picked = append(picked, &cards[i])
+			}
+		}
+		err := i18n.Localize(ctx, store, kind.entity, lang, picked,
+			func(c **models.ReviewCard) int64 { return int64((*c).ItemID) },
+			func(c **models.ReviewCard, f models.TranslationFields) { kind.apply(*c, f) })
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+// fakeTranslations serves the same translations for every language.
+type fakeTranslations map[int64]models.TranslationFields
+
+func (f fakeTranslations) Translations(_ context.Context, _, _ string, ids []int64) (map[int64]models.TranslationFields, error) {
+	out := map[int64]models.TranslationFields{}
+	for _, id := range ids {
+		if tr, ok := f[id]; ok {
+			out[id] = tr
+		}
+	}
+	return out, nil
+}
+
+func TestLocalizeTranslatesAnswerSpecs(t *testing.T) {
+	text := func(s string) *string { return &s }
+	shapes := &models.AnswerSpec{Pairs: []models.MatchPair{{Left: "Triangle", Right: "3"}, {Left: "Square", Right: "4"}}}
+	store := fakeTranslations{
+		1: {Question: text("හැඩතල ගළපන්න."), Answer: &models.AnswerSpec{Pairs: []models.MatchPair{{Left: "ත්‍රිකෝණය", Right: "3"}, {Left: "සමචතුරස්‍රය", Right: "4"}}}},
+		2: {Question: text("ප්‍රථමක මොනවාද?"), Answer: &models.AnswerSpec{Correct: []string{"දෙක", "තුන"}}, OtherAnswers: models.StringArray{"හතර"}},
+		// a translation written for an older version of the question
+		3: {Question: text("හැඩතල ගළපන්න."), Answer: &models.AnswerSpec{Pairs: []models.MatchPair{{Left: "ත්‍රිකෝණය", Right: "3"}}}},
+	}
+
+	questions := []models.Question{
+		{ID: 1, Type: models.QuestionTypeMatching, Question: "Match the shapes.", Answer: shapes},
+		{ID: 2, Type: models.QuestionTypeMultiSelect, Question: "Which are prime?", Answer: &models.AnswerSpec{Correct: []string{"two", "three"}},
+			OtherAnswers: models.StringArray{"four"}},
+		{ID: 3, Type: models.QuestionTypeMatching, Question: "Match the shapes.", Answer: shapes},
+	}
+	require.NoError(t, localizeQuestions(context.Background(), store, i18n.Sinhala, questions))
+	assert.Equal(t, "ත්‍රිකෝණය = 3; සමචතුරස්‍රය = 4", questions[0].CorrectAnswer)
+	assert.Equal(t, "Triangle", shapes.Pairs[0].Left, "the base key is not modified")
+	assert.Equal(t, []string{"දෙක", "තුන"}, questions[1].Answer.Correct)
+	assert.Equal(t, models.StringArray{"හතර"}, questions[1].OtherAnswers)
+	assert.Equal(t, shapes, questions[2].Answer)
+
+	revealed := "Triangle = 3; Square = 4"
+	quiz := []models.QuizQuestion{{QuestionID: 1, Type: models.QuestionTypeMatching, Answer: shapes, CorrectAnswer: &revealed}}
+	require.NoError(t, localizeQuizQuestions(context.Background(), store, i18n.Sinhala, quiz))
+	assert.Equal(t, models.StringArray{"ත්‍රිකෝණය", "සමචතුරස්‍රය"}, quiz[0].Prompts)
+	assert.Equal(t, "ත්‍රිකෝණය = 3; සමචතුරස්‍රය = 4", *quiz[0].CorrectAnswer)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	menuconfigmodels "github.com/tharindulakmal/sl-edu-service/internal/models/menuconfig"
+	"github.com/tharindulakmal/sl-edu-service/internal/quiz"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+type PaperHandler struct {
+	papers       repository.PastPaperRepository
+	questions    repository.QuestionRepository
+	translations i18n.Store
+}
+
+func NewPaperHandler(papers repository.PastPaperRepository, questions repository.QuestionRepository, translations i18n.Store) *PaperHandler {
+	return &PaperHandler{papers: papers, questions: questions, translations: translations}
+}
+
+// GET /api/v1/papers?examType=ol&yearId=3&subjectId=2&page=1&pageSize=10
+func (h *PaperHandler) GetPapers(c *gin.Context) {
+	filter := menuconfigmodels.PastPaperFilter{ExamType: strings.TrimSpace(c.Query("examType"))}
+	filter.YearID, _ = strconv.ParseInt(c.Query("yearId"), 10, 64)
+	filter.SubjectID, _ = strconv.ParseInt(c.Query("subjectId"), 10, 64)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	papers, total, err := h.papers.ListPastPapers(c.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":       papers,
+		"page":       page,
+		"pageSize":   pageSize,
+		"totalCount": total,
+	})
+}
+
+// GET /api/v1/papers/:id
+//
+// Returns the whole paper in its printed question order, without the answer
+// key. Start an attempt with paperId to have it timed and graded.
+func (h *PaperHandler) GetPaper(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid paper id"})
+		return
+	}
+
+	paper, err := h.papers.GetPastPaper(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrMenuConfigNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "paper not found"})
+			return
+		}
+		logging.InternalError(c, err)
+		return
+	}
+
+	questions, err := h.questions.GetList(map[string]interface{}{"paperId": int(id)}, 1, maxPaperQuestions)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	if err := localizeQuestions(c.Request.Context(), h.translations, i18n.FromContext(c), questions); err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	items := make([]models.PaperQuestion, 0, len(questions))
+	for _, q := range questions {
+		items = append(items, models.PaperQuestion{
+			QuestionID:     q.ID,
+			QuestionNumber: q.QuestionNumber,
+			Type:           quiz.Type(q),
+			Question:       q.Question,
+			QuestionImg:    q.QuestionImg,
+			Prompts:        q.Answer.Prompts(),
+			Options:        quiz.BuildOptions(q, rng),
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"paper":     paper,
+		"questions": items,
+	})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+type fakePapers struct {
+	papers []repository.PastPaper
+	filter repository.PastPaperFilter
+}
+
+func (f *fakePapers) ListPastPapers(ctx context.Context, filter repository.PastPaperFilter, page, pageSize int) ([]repository.PastPaper, int, error) {
+	f.filter = filter
+	return f.papers, len(f.papers), nil
+}
+
+func (f *fakePapers) GetPastPaper(ctx context.Context, id int64) (*repository.PastPaper, error) {
+	for _, p := range f.papers {
+		if p.ID == id {
+			return &p, nil
+		}
+	}
+	return nil, repository.ErrMenuConfigNotFound
+}
+
+// paperQuestions records the filters the paper is read with.
+type paperQuestions struct {
+	statusQuestionRepo
+	filters map[string]interface{}
+}
+
+func (r *paperQuestions) GetList(filters map[string]interface{}, page, pageSize int) ([]models.Question, error) {
+	r.filters = filters
+	return r.statusQuestionRepo.GetList(filters, page, pageSize)
+}
+
+func TestGetPaperHidesTheAnswerKey(t *testing.T) {
+	first, second := 1, 2
+	papers := &fakePapers{papers: []repository.PastPaper{{ID: 4, ExamType: "ol", PaperNumber: 1}}}
+	questions := &paperQuestions{statusQuestionRepo: statusQuestionRepo{questions: []models.Question{
+		{ID: 1, Status: editorial.StatusPublished, QuestionNumber: &first, Question: "2 + 2 = ?", CorrectAnswer: "4", OtherAnswers: models.StringArray{"3"}},
+		{ID: 2, Status: editorial.StatusPublished, QuestionNumber: &second, Question: "3 + 3 = ?", CorrectAnswer: "6", OtherAnswers: models.StringArray{"5"}},
+	}}}
+	router := gin.New()
+	router.Use(i18n.Middleware())
+	router.GET("/papers/:id", NewPaperHandler(papers, questions, nil).GetPaper)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/papers/4", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, map[string]interface{}{"paperId": 4}, questions.filters)
+
+	var resp struct {
+		Questions []map[string]interface{} `json:"questions"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Questions, 2)
+	// printed order is kept as the repository returns it
+	assert.Equal(t, float64(1), resp.Questions[0]["questionNumber"])
+	assert.Equal(t, float64(2), resp.Questions[1]["questionNumber"])
+	assert.NotContains(t, w.Body.String(), "correctAnswer")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/papers/9", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetPapersFilters(t *testing.T) {
+	papers := &fakePapers{}
+	router := gin.New()
+	router.GET("/papers", NewPaperHandler(papers, nil, nil).GetPapers)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/papers?examType=ol&yearId=3&subjectId=2", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, repository.PastPaperFilter{ExamType: "ol", YearID: 3, SubjectID: 2}, papers.filter)
+}
+
+func TestQuestionFiltersTakeYearAndPaperNumber(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/questions?yearId=3&paper=2&paperId=x", nil)
+	assert.Equal(t, map[string]interface{}{"yearId": 3, "paper": 2}, questionFilters(c))
+}
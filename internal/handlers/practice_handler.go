@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/auth"
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/mastery"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/quiz"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+// recentWindow is how many of a student's last answers practice avoids
+// repeating.
+const recentWindow = 20
+
+type PracticeHandler struct {
+	questions    repository.QuestionRepository
+	mastery      repository.MasteryRepository
+	reviews      repository.ReviewRepository
+	translations i18n.Store
+	model        mastery.Model
+}
+
+func NewPracticeHandler(questions repository.QuestionRepository, mastery repository.MasteryRepository, reviews repository.ReviewRepository, translations i18n.Store, model mastery.Model) *PracticeHandler {
+	return &PracticeHandler{questions: questions, mastery: mastery, reviews: reviews, translations: translations, model: model}
+}
+
+// GET /api/v1/mcq/practice/next?lessonId=
+func (h *PracticeHandler) Next(c *gin.Context) {
+	lessonID, ok := lessonIDQuery(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	studentID := auth.ClaimsFrom(c).UserID
+
+	candidates, err := h.mastery.PracticeCandidates(ctx, lessonID)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	levels, err := h.mastery.LessonMastery(ctx, studentID, lessonID)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	recentIDs, err := h.mastery.RecentQuestions(ctx, studentID, recentWindow)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+
+	known := make(map[int]float64, len(levels))
+	for _, m := range levels {
+		known[m.SubtopicID] = m.PKnown
+	}
+	recent := make(map[int]bool, len(recentIDs))
+	for _, id := range recentIDs {
+		recent[id] = true
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	next, ok := mastery.Picker{Model: h.model, Rand: rng}.Next(candidates, known, recent)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no questions in this lesson"})
+		return
+	}
+
+	question, err := h.questions.GetByID(next.QuestionID)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	localized := []models.Question{*question}
+	if err := localizeQuestions(ctx, h.translations, i18n.FromContext(c), localized); err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	q := localized[0]
+	c.JSON(http.StatusOK, models.PracticeQuestion{
+		QuestionID:  q.ID,
+		LessonID:    q.LessonID,
+		TopicID:     q.TopicID,
+		SubtopicID:  q.SubtopicID,
+		Type:        quiz.Type(q),
+		Question:    q.Question,
+		QuestionImg: q.QuestionImg,
+		Prompts:     q.Answer.Prompts(),
+		Options:     quiz.BuildOptions(q, rng),
+	})
+}
+
+// POST /api/v1/mcq/practice/answer
+//
+// The answer is marked against the key in the request language, which must
+// match the language the question was served in.
+func (h *PracticeHandler) Answer(c *gin.Context) {
+	var req models.PracticeAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+
+	question, err := h.questions.GetByID(req.QuestionID)
+	if err != nil && !errors.Is(err, repository.ErrQuestionNotFound) {
+		logging.InternalError(c, err)
+		return
+	}
+	if err != nil || question.Status != editorial.StatusPublished {
+		c.JSON(http.StatusNotFound, gin.H{"error": "question not found"})
+		return
+	}
+	localized := []models.Question{*question}
+	if err := localizeQuestions(ctx, h.translations, i18n.FromContext(c), localized); err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	q := localized[0]
+
+	result := models.PracticeAnswerResult{
+		QuestionID:    q.ID,
+		IsCorrect:     quiz.Check(quiz.Type(q), q.CorrectAnswer, q.Answer, req.Answer),
+		CorrectAnswer: q.CorrectAnswer,
+		Theory:        q.Theory,
+		Solution:      q.Solution,
+	}
+	studentID := auth.ClaimsFrom(c).UserID
+	updated, err := h.mastery.RecordAnswers(ctx, studentID,
+		[]models.GradedAnswer{{QuestionID: q.ID, Correct: result.IsCorrect}})
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	if !result.IsCorrect {
+		if err := h.reviews.EnrollMissed(ctx, studentID, []int{q.ID}); err != nil {
+			logging.InternalError(c, err)
+			return
+		}
+	}
+	if len(updated) > 0 {
+		result.Mastery = &updated[0]
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GET /api/v1/mcq/practice/mastery?lessonId=
+func (h *PracticeHandler) Mastery(c *gin.Context) {
+	lessonID, ok := lessonIDQuery(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	levels, err := h.mastery.LessonMastery(ctx, auth.ClaimsFrom(c).UserID, lessonID)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	err = i18n.Localize(ctx, h.translations, models.EntitySubtopic, i18n.FromContext(c), levels,
+		func(m *models.SubtopicMastery) int64 { return int64(m.SubtopicID) },
+		func(m *models.SubtopicMastery, f models.TranslationFields) { i18n.Text(&m.SubtopicName, f.Name) })
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, levels)
+}
+
+func lessonIDQuery(c *gin.Context) (int, bool) {
+	lessonID, err := strconv.Atoi(c.Query("lessonId"))
+	if err != nil || lessonID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lessonId is required"})
+		return 0, false
+	}
+	return lessonID, true
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tharindulakmal/sl-edu-service/internal/mastery"
+)
+
+func TestPracticeRefusesUnpublishedQuestions(t *testing.T) {
+	router := gin.New()
+	router.POST("/practice/answer", NewPracticeHandler(draftBank(), nil, nil, nil, mastery.Model{}).Answer)
+
+	for _, body := range []string{`{"questionId":5,"answer":"4"}`, `{"questionId":99,"answer":"4"}`} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/practice/answer", strings.NewReader(body)))
+		assert.Equal(t, http.StatusNotFound, w.Code, body)
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/auth"
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+// statusQuestionRepo filters by review status the way the MySQL repository
+// does: without a status filter only published questions are listed. It
+// also honours the tutorId filter.
+type statusQuestionRepo struct {
+	repository.QuestionRepository
+	questions []models.Question
+}
+
+func (r statusQuestionRepo) GetList(filters map[string]interface{}, page, pageSize int) ([]models.Question, error) {
+	status, ok := filters["status"]
+	if !ok {
+		status = editorial.StatusPublished
+	}
+	var out []models.Question
+	for _, q := range r.questions {
+		if tutor, ok := filters["tutorId"]; ok && (q.TutorID == nil || *q.TutorID != tutor) {
+			continue
+		}
+		if status == repository.AnyStatus || q.Status == status {
+			out = append(out, q)
+		}
+	}
+	if page > 1 {
+		return nil, nil
+	}
+	return out, nil
+}
+
+func (r statusQuestionRepo) GetByID(id int) (*models.Question, error) {
+	for _, q := range r.questions {
+		if q.ID == id {
+			return &q, nil
+		}
+	}
+	return nil, repository.ErrQuestionNotFound
+}
+
+func (r statusQuestionRepo) Count(filters map[string]interface{}) (int, error) {
+	questions, err := r.GetList(filters, 1, len(r.questions))
+	return len(questions), err
+}
+
+func draftBank() statusQuestionRepo {
+	return statusQuestionRepo{questions: []models.Question{{
+		ID: 5, Status: editorial.StatusDraft, Question: "2 + 2 = ?", CorrectAnswer: "4",
+		OtherAnswers: models.StringArray{"3", "5"},
+	}}}
+}
+
+func TestExportIncludesDraftQuestions(t *testing.T) {
+	router := gin.New()
+	router.Use(i18n.Middleware())
+	router.GET("/export", NewQuestionHandler(draftBank(), nil, nil).ExportQuestions)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export?format=gift", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "::Q5::")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export?format=gift&status=published", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "::Q5::")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export?format=gift&status=lost", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWorksheetIncludesDraftQuestions(t *testing.T) {
+	handler := NewWorksheetHandler(draftBank(), nil)
+	router := gin.New()
+	router.GET("/worksheet", handler.GetWorksheet)
+	router.GET("/worksheet/answer-key", handler.GetAnswerKey)
+
+	for _, path := range []string{"/worksheet", "/worksheet/answer-key"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"), path)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"?status=published", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+}
+
+func TestPublicQuestionsHideTheAnswerKey(t *testing.T) {
+	solution := "Add the two numbers."
+	repo := statusQuestionRepo{questions: []models.Question{{
+		ID: 6, Status: editorial.StatusPublished, Question: "2 + 2 = ?", CorrectAnswer: "4",
+		OtherAnswers: models.StringArray{"3", "5"}, Solution: &solution,
+	}}}
+	handler := NewQuestionHandler(repo, nil, nil)
+	router := gin.New()
+	router.Use(i18n.Middleware())
+	router.GET("/questions", handler.GetQuestions)
+	router.GET("/admin/questions", handler.GetAllQuestions)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/questions", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.NotContains(t, resp.Data[0], "correctAnswer")
+	assert.NotContains(t, resp.Data[0], "solution")
+	assert.ElementsMatch(t, []interface{}{"3", "4", "5"}, resp.Data[0]["options"])
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/questions", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"correctAnswer":"4"`)
+}
+
+func TestTutorsOnlyExportTheirOwnQuestions(t *testing.T) {
+	own, other := 7, 8
+	repo := statusQuestionRepo{questions: []models.Question{
+		{ID: 5, TutorID: &own, Status: editorial.StatusDraft, Question: "2 + 2 = ?", CorrectAnswer: "4"},
+		{ID: 6, TutorID: &other, Status: editorial.StatusDraft, Question: "3 + 3 = ?", CorrectAnswer: "6"},
+	}}
+	tokens := auth.NewTokenManager([]byte("secret"), time.Hour)
+	router := gin.New()
+	router.Use(i18n.Middleware(), auth.Authenticate(tokens))
+	router.GET("/export", NewQuestionHandler(repo, nil, nil).ExportQuestions)
+	router.GET("/worksheet/answer-key", NewWorksheetHandler(repo, nil).GetAnswerKey)
+
+	get := func(path string, claims auth.Claims) *httptest.ResponseRecorder {
+		token, _, err := tokens.Issue(claims)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/export?format=gift", auth.Claims{UserID: 1, Role: models.RoleTutor, TutorID: &own})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "::Q5::")
+	assert.NotContains(t, w.Body.String(), "::Q6::")
+
+	w = get("/export?format=gift", auth.Claims{UserID: 2, Role: models.RoleContentEditor})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "::Q6::")
+
+	for _, path := range []string{"/export?format=gift", "/worksheet/answer-key"} {
+		w = get(path, auth.Claims{UserID: 3, Role: models.RoleTutor})
+		assert.Equal(t, http.StatusForbidden, w.Code, path)
+	}
+}
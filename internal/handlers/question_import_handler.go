@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/auth"
+	"github.com/tharindulakmal/sl-edu-service/internal/dedupe"
+	"github.com/tharindulakmal/sl-edu-service/internal/importer"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+// maxImportBytes bounds the whole request body, multipart framing included.
+const maxImportBytes = 10 << 20
+
+type QuestionImportHandler struct {
+	importer *importer.Importer
+}
+
+func NewQuestionImportHandler(im *importer.Importer) *QuestionImportHandler {
+	return &QuestionImportHandler{importer: im}
+}
+
+// POST /api/v1/mcq/questions/import?format=csv&dryRun=true&duplicates=warn|block|ignore
+// Accepts either a multipart upload in the "file" field or the raw file as
+// the request body. The format defaults to the file extension or content type.
+func (h *QuestionImportHandler) ImportQuestions(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+	format := c.Query("format")
+	duplicates := c.DefaultQuery("duplicates", dedupe.ModeWarn)
+	if duplicates != dedupe.ModeWarn && duplicates != dedupe.ModeBlock && duplicates != dedupe.ModeIgnore {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "duplicates must be warn, block or ignore"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	var body io.Reader
+	if file, header, err := c.Request.FormFile("file"); tooLarge(c, err) {
+		return
+	} else if err == nil {
+		defer file.Close()
+		body = file
+		if format == "" {
+			format = importer.FormatFromName(header.Filename)
+		}
+	} else {
+		body = c.Request.Body
+		if format == "" {
+			format = importer.FormatFromName(c.ContentType())
+		}
+	}
+	if format != importer.FormatCSV && format != importer.FormatJSON {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		return
+	}
+
+	rows, err := importer.Parse(body, format)
+	if tooLarge(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(rows) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file contains no rows"})
+		return
+	}
+
+	opts := importer.Options{DryRun: dryRun, Duplicates: duplicates}
+	if claims := auth.ClaimsFrom(c); claims != nil && claims.Role == models.RoleTutor {
+		opts.TutorID = claims.TutorID
+	}
+
+	report, err := h.importer.Run(c.Request.Context(), rows, opts)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if report.Created > 0 {
+		status = http.StatusCreated
+	}
+	c.JSON(status, report)
+}
+
+// tooLarge answers 413 when err comes from a body over maxImportBytes, which
+// is refused whole rather than imported up to the cut.
+func tooLarge(c *gin.Context, err error) bool {
+	var maxErr *http.MaxBytesError
+	if !errors.As(err, &maxErr) {
+		return false
+	}
+	c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is larger than " + strconv.Itoa(maxImportBytes>>20) + " MB"})
+	return true
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportRefusesOversizedFiles(t *testing.T) {
+	router := gin.New()
+	router.POST("/import", NewQuestionImportHandler(nil).ImportQuestions)
+
+	// one row long enough that a cut at the limit would still parse
+	csv := "question,correctAnswer\n\"" + strings.Repeat("x", maxImportBytes) + "\",4\n"
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/import?format=csv", strings.NewReader(csv))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "questions.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(csv))
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/import", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
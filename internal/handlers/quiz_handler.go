@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/auth"
+	"github.com/tharindulakmal/sl-edu-service/internal/exam"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/quiz"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+const (
+	defaultQuizQuestions = 10
+	maxQuizQuestions     = 50
+	// maxPaperQuestions bounds a past paper run; real papers have at most 100
+	maxPaperQuestions = 200
+)
+
+type QuizHandler struct {
+	questions    repository.QuestionRepository
+	attempts     repository.QuizRepository
+	exams        repository.ExamRepository
+	mastery      repository.MasteryRepository
+	reviews      repository.ReviewRepository
+	translations i18n.Store
+}
+
+// NewQuizHandler builds the quiz handler. mastery and reviews may be nil, in
+// which case submitted attempts do not update student mastery or add missed
+// questions to the review deck.
+func NewQuizHandler(questions repository.QuestionRepository, attempts repository.QuizRepository, exams repository.ExamRepository, mastery repository.MasteryRepository, reviews repository.ReviewRepository, translations i18n.Store) *QuizHandler {
+	return &QuizHandler{questions: questions, attempts: attempts, exams: exams, mastery: mastery, reviews: reviews, translations: translations}
+}
+
+// POST /api/v1/mcq/attempts
+func (h *QuizHandler) StartAttempt(c *gin.Context) {
+	var req models.StartQuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.LessonID == nil && req.TopicID == nil && req.SubtopicID == nil && req.PaperID == nil && req.BlueprintID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "one of lessonId, topicId, subtopicId, paperId or blueprintId is required"})
+		return
+	}
+
+	attempt := models.QuizAttempt{
+		GradeID:    req.GradeID,
+		LessonID:   req.LessonID,
+		TopicID:    req.TopicID,
+		SubtopicID: req.SubtopicID,
+		PaperID:    req.PaperID,
+		Lang:       i18n.FromContext(c),
+		Status:     models.QuizStatusInProgress,
+	}
+	var questions []models.Question
+	var rng *rand.Rand
+	if req.BlueprintID != nil {
+		bp, err := h.exams.GetBlueprint(c.Request.Context(), *req.BlueprintID)
+		if err != nil {
+			handleExamError(c, err)
+			return
+		}
+		seed := time.Now().UnixNano()
+		if req.Seed != nil {
+			seed = *req.Seed
+		}
+		if questions, err = exam.Generate(*bp, seed, h.questions); err != nil {
+			handleExamError(c, err)
+			return
+		}
+		// the seed also fixes the option order, so a regenerated exam matches
+		rng = rand.New(rand.NewSource(seed))
+		attempt.BlueprintID = &bp.ID
+		attempt.Seed = &seed
+		attempt.TimeLimit = &bp.DurationMinutes
+		attempt.LatePolicy = &bp.LatePolicy
+	} else {
+		filters, count := quizFilters(req)
+		var err error
+		if questions, err = h.questions.GetList(filters, 1, count); err != nil {
+			logging.InternalError(c, err)
+			return
+		}
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	if len(questions) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no questions match the given filters"})
+		return
+	}
+	// options are built from the translated answers and stored as served
+	if err := localizeQuestions(c.Request.Context(), h.translations, attempt.Lang, questions); err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+
+	attempt.Questions = make([]models.QuizQuestion, 0, len(questions))
+	for i, q := range questions {
+		attempt.Questions = append(attempt.Questions, models.QuizQuestion{
+			QuestionID:  q.ID,
+			Position:    i + 1,
+			Type:        quiz.Type(q),
+			Question:    q.Question,
+			QuestionImg: q.QuestionImg,
+			Options:     quiz.BuildOptions(q, rng),
+		})
+	}
+	if claims := auth.ClaimsFrom(c); claims != nil {
+		attempt.StudentID = &claims.UserID
+	}
+
+	id, err := h.attempts.CreateAttempt(c.Request.Context(), &attempt)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+
+	created, err := h.loadAttempt(c.Request.Context(), int(id))
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	quiz.Redact(created)
+	c.JSON(http.StatusCreated, created)
+}
+
+// GET /api/v1/mcq/attempts/:id
+func (h *QuizHandler) GetAttempt(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attempt id"})
+		return
+	}
+
+	attempt, err := h.loadAttempt(c.Request.Context(), id)
+	if err != nil {
+		handleQuizError(c, err)
+		return
+	}
+	if !canAccessAttempt(c, attempt) {
+		return
+	}
+	quiz.Redact(attempt)
+	c.JSON(http.StatusOK, attempt)
+}
+
+// POST /api/v1/mcq/attempts/:id/submit
+func (h *QuizHandler) SubmitAttempt(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attempt id"})
+		return
+	}
+
+	var req models.SubmitQuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attempt, err := h.loadAttempt(c.Request.Context(), id)
+	if err != nil {
+		handleQuizError(c, err)
+		return
+	}
+	if !canAccessAttempt(c, attempt) {
+		return
+	}
+	if attempt.Status == models.QuizStatusSubmitted {
+		handleQuizError(c, repository.ErrQuizAttemptSubmitted)
+		return
+	}
+	if attempt.Overdue {
+		if attempt.LatePolicy != nil && *attempt.LatePolicy == models.LatePolicyReject {
+			handleQuizError(c, repository.ErrQuizAttemptExpired)
+			return
+		}
+		attempt.Late = true
+	}
+
+	attempt.Score = quiz.Grade(attempt.Questions, req.Answers)
+	if err := h.attempts.SubmitAttempt(c.Request.Context(), attempt); err != nil {
+		handleQuizError(c, err)
+		return
+	}
+	h.recordAnswers(c, attempt)
+
+	submitted, err := h.loadAttempt(c.Request.Context(), id)
+	if err != nil {
+		handleQuizError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, submitted)
+}
+
+// quizFilters turns a start request into question filters and the number of
+// questions to draw. A past paper is always taken whole.
+func quizFilters(req models.StartQuizRequest) (map[string]interface{}, int) {
+	if req.PaperID != nil {
+		return map[string]interface{}{"paperId": *req.PaperID}, maxPaperQuestions
+	}
+
+	count := req.QuestionCount
+	if count <= 0 {
+		count = defaultQuizQuestions
+	}
+	if count > maxQuizQuestions {
+		count = maxQuizQuestions
+	}
+
+	// each attempt draws its own questions from everything that matches
+	filters := map[string]interface{}{"sort": "random"}
+	for key, v := range map[string]*int{
+		"gradeId":    req.GradeID,
+		"lessonId":   req.LessonID,
+		"topicId":    req.TopicID,
+		"subtopicId": req.SubtopicID,
+		"tutorId":    req.TutorID,
+		"tuteId":     req.TuteID,
+	} {
+		if v != nil {
+			filters[key] = *v
+		}
+	}
+	return filters, count
+}
+
+// loadAttempt reads an attempt with its question text and answer key in the
+// language it was started in, so grading matches the options shown.
+func (h *QuizHandler) loadAttempt(ctx context.Context, id int) (*models.QuizAttempt, error) {
+	attempt, err := h.attempts.GetAttempt(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeQuizQuestions(ctx, h.translations, attempt.Lang, attempt.Questions); err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// recordAnswers feeds the answered questions of a signed-in student's
+// attempt into their mastery estimates and review deck. The attempt is
+// already submitted, so failures are reported on the context instead of
+// failing the request.
+func (h *QuizHandler) recordAnswers(c *gin.Context, attempt *models.QuizAttempt) {
+	if attempt.StudentID == nil {
+		return
+	}
+	answers := make([]models.GradedAnswer, 0, len(attempt.Questions))
+	var missed []int
+	for _, q := range attempt.Questions {
+		if q.SelectedAnswer == nil || q.IsCorrect == nil {
+			continue
+		}
+		answers = append(answers, models.GradedAnswer{QuestionID: q.QuestionID, Correct: *q.IsCorrect})
+		if !*q.IsCorrect {
+			missed = append(missed, q.QuestionID)
+		}
+	}
+	if h.mastery != nil && len(answers) > 0 {
+		if _, err := h.mastery.RecordAnswers(c.Request.Context(), *attempt.StudentID, answers); err != nil {
+			_ = c.Error(err)
+		}
+	}
+	if h.reviews != nil && len(missed) > 0 {
+		if err := h.reviews.EnrollMissed(c.Request.Context(), *attempt.StudentID, missed); err != nil {
+			_ = c.Error(err)
+		}
+	}
+}
+
+// canAccessAttempt keeps attempts started by a signed-in student private to
+// that student. Anonymous attempts stay reachable by id.
+func canAccessAttempt(c *gin.Context, attempt *models.QuizAttempt) bool {
+	if attempt.StudentID == nil {
+		return true
+	}
+	claims := auth.ClaimsFrom(c)
+	if claims != nil && (claims.UserID == *attempt.StudentID || claims.Role == models.RoleAdmin) {
+		return true
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "attempt not found"})
+	return false
+}
+
+func handleQuizError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrQuizAttemptNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "attempt not found"})
+	case errors.Is(err, repository.ErrQuizAttemptSubmitted):
+		c.JSON(http.StatusConflict, gin.H{"error": "attempt already submitted"})
+	case errors.Is(err, repository.ErrQuizAttemptExpired):
+		c.JSON(http.StatusConflict, gin.H{"error": "time limit exceeded"})
+	default:
+		logging.InternalError(c, err)
+	}
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+func TestQuizFiltersSampleTheWholePool(t *testing.T) {
+	lesson := 4
+	filters, count := quizFilters(models.StartQuizRequest{LessonID: &lesson, QuestionCount: 5})
+	assert.Equal(t, map[string]interface{}{"sort": "random", "lessonId": 4}, filters)
+	assert.Equal(t, 5, count)
+
+	// a past paper is asked whole, in its printed order
+	paper := 9
+	filters, _ = quizFilters(models.StartQuizRequest{PaperID: &paper})
+	assert.Equal(t, map[string]interface{}{"paperId": 9}, filters)
+}
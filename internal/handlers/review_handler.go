@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/auth"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+	"github.com/tharindulakmal/sl-edu-service/internal/review"
+)
+
+const (
+	defaultDueCards = 20
+	maxDueCards     = 100
+)
+
+type ReviewHandler struct {
+	reviews      repository.ReviewRepository
+	translations i18n.Store
+}
+
+func NewReviewHandler(reviews repository.ReviewRepository, translations i18n.Store) *ReviewHandler {
+	return &ReviewHandler{reviews: reviews, translations: translations}
+}
+
+// GET /api/v1/review/due?limit=
+func (h *ReviewHandler) GetDue(c *gin.Context) {
+	limit := defaultDueCards
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = min(n, maxDueCards)
+	}
+
+	cards, err := h.reviews.Due(c.Request.Context(), auth.ClaimsFrom(c).UserID, limit)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	if err := localizeReviewCards(c.Request.Context(), h.translations, i18n.FromContext(c), cards); err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cards)
+}
+
+// POST /api/v1/review/cards
+//
+// Adds a question or smart note to the deck by hand. Adding an item that is
+// already in the deck returns its card unchanged.
+func (h *ReviewHandler) AddCard(c *gin.Context) {
+	var req models.AddReviewCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	card, err := h.reviews.Add(c.Request.Context(), auth.ClaimsFrom(c).UserID, req.Type, req.ItemID)
+	if err != nil {
+		handleReviewError(c, err)
+		return
+	}
+	h.respond(c, card)
+}
+
+// POST /api/v1/review/cards/:id/grade
+func (h *ReviewHandler) GradeCard(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid card id"})
+		return
+	}
+	var req models.GradeReviewCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	card, err := h.reviews.Grade(c.Request.Context(), auth.ClaimsFrom(c).UserID, id, review.Grade(*req.Grade))
+	if err != nil {
+		handleReviewError(c, err)
+		return
+	}
+	h.respond(c, card)
+}
+
+// DELETE /api/v1/review/cards/:id
+func (h *ReviewHandler) DeleteCard(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid card id"})
+		return
+	}
+	if err := h.reviews.Delete(c.Request.Context(), auth.ClaimsFrom(c).UserID, id); err != nil {
+		handleReviewError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ReviewHandler) respond(c *gin.Context, card *models.ReviewCard) {
+	cards := []models.ReviewCard{*card}
+	if err := localizeReviewCards(c.Request.Context(), h.translations, i18n.FromContext(c), cards); err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cards[0])
+}
+
+func handleReviewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrReviewCardNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "card not found"})
+	case errors.Is(err, repository.ErrReviewItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
+	case errors.Is(err, repository.ErrReviewTypeUnknown), errors.Is(err, review.ErrInvalidGrade):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		logging.InternalError(c, err)
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/search"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+)
+
+type SearchHandler struct {
+	index        search.Index
+	translations i18n.Store
+}
+
+func NewSearchHandler(index search.Index, translations i18n.Store) *SearchHandler {
+	return &SearchHandler{index: index, translations: translations}
+}
+
+// GET /api/v1/search?q=triangle&types=subtopic,question&limit=20&offset=0
+func (h *SearchHandler) Search(c *gin.Context) {
+	text := strings.TrimSpace(c.Query("q"))
+	if len(search.Terms(text)) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q must contain at least one word of two or more characters"})
+		return
+	}
+
+	q := search.Query{Text: text, Lang: i18n.FromContext(c), Limit: defaultSearchLimit}
+	if raw := strings.TrimSpace(c.Query("types")); raw != "" {
+		for _, typ := range strings.Split(raw, ",") {
+			typ = strings.TrimSpace(typ)
+			if !search.ValidType(typ) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "types must be a comma separated list of " + strings.Join(search.Types, ", ")})
+				return
+			}
+			q.Types = append(q.Types, typ)
+		}
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		q.Limit = min(limit, maxSearchLimit)
+	}
+	if raw := c.Query("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+			return
+		}
+		q.Offset = offset
+	}
+
+	hits, err := h.index.Search(c.Request.Context(), q)
+	if err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	if err := h.localizeBreadcrumbs(c, q.Lang, hits); err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SearchResponse{
+		Query:  text,
+		Lang:   q.Lang,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+		Hits:   hits,
+	})
+}
+
+// localizeBreadcrumbs shows the curriculum path in the request language,
+// loading the translations of each crumb type in one batch.
+func (h *SearchHandler) localizeBreadcrumbs(c *gin.Context, lang string, hits []models.SearchHit) error {
+	byType := map[string][]*models.SearchCrumb{}
+	for i := range hits {
+		for j := range hits[i].Breadcrumb {
+			crumb := &hits[i].Breadcrumb[j]
+			byType[crumb.Type] = append(byType[crumb.Type], crumb)
+		}
+	}
+	for typ, crumbs := range byType {
+		err := i18n.Localize(c.Request.Context(), h.translations, typ, lang, crumbs,
+			func(crumb **models.SearchCrumb) int64 { return (*crumb).ID },
+			func(crumb **models.SearchCrumb, f models.TranslationFields) { i18n.Text(&(*crumb).Name, f.Name) })
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
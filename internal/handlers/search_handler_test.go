@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/search"
+)
+
+func TestSearch(t *testing.T) {
+	index := search.NewMemoryIndex()
+	index.Add(
+		search.Document{Type: models.EntitySubtopic, ID: 3, Title: "Triangles", Breadcrumb: []models.SearchCrumb{
+			{Type: models.EntityLesson, ID: 1, Name: "Geometry"},
+			{Type: models.EntitySubtopic, ID: 3, Name: "Triangles"},
+		}},
+		search.Document{Type: models.EntityQuestion, ID: 8, Title: "How many sides does a triangle have?"},
+	)
+
+	router := gin.New()
+	router.Use(i18n.Middleware())
+	router.GET("/search", NewSearchHandler(index, nil).Search)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?q=triangles&types=subtopic", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.SearchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Hits, 1)
+	assert.Equal(t, int64(3), resp.Hits[0].ID)
+	assert.Equal(t, "<mark>Triangles</mark>", resp.Hits[0].Snippet)
+	assert.Equal(t, "Geometry", resp.Hits[0].Breadcrumb[0].Name)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?q=triangles&types=tutor", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?q=a", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
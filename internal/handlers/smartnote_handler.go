@@ -5,15 +5,18 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
 	"github.com/tharindulakmal/sl-edu-service/internal/repository"
 )
 
 type SmartNoteHandler struct {
-	Repo repository.SmartNoteRepositoryInterface
+	Repo         repository.SmartNoteRepositoryInterface
+	Translations i18n.Store
 }
 
-func NewSmartNoteHandler(repo repository.SmartNoteRepositoryInterface) *SmartNoteHandler {
-	return &SmartNoteHandler{Repo: repo}
+func NewSmartNoteHandler(repo repository.SmartNoteRepositoryInterface, translations i18n.Store) *SmartNoteHandler {
+	return &SmartNoteHandler{Repo: repo, Translations: translations}
 }
 
 func (h *SmartNoteHandler) GetSmartNote(c *gin.Context) {
@@ -70,7 +73,11 @@ func (h *SmartNoteHandler) GetSmartNote(c *gin.Context) {
 
 	sn, err := h.Repo.GetSmartNote(gradeID, subjectID, lessonID, topicIDPtr, subIDPtr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.InternalError(c, err)
+		return
+	}
+	if err := localizeSmartNote(c.Request.Context(), h.Translations, i18n.FromContext(c), &sn); err != nil {
+		logging.InternalError(c, err)
 		return
 	}
 
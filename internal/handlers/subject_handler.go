@@ -5,15 +5,19 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
 	"github.com/tharindulakmal/sl-edu-service/internal/repository"
 )
 
 type SubjectHandler struct {
-	Repo *repository.SubjectRepository
+	Repo         *repository.SubjectRepository
+	Translations i18n.Store
 }
 
-func NewSubjectHandler(repo *repository.SubjectRepository) *SubjectHandler {
-	return &SubjectHandler{Repo: repo}
+func NewSubjectHandler(repo *repository.SubjectRepository, translations i18n.Store) *SubjectHandler {
+	return &SubjectHandler{Repo: repo, Translations: translations}
 }
 
 func (h *SubjectHandler) GetSubjectsByGrade(c *gin.Context) {
@@ -26,7 +30,14 @@ func (h *SubjectHandler) GetSubjectsByGrade(c *gin.Context) {
 
 	subjects, err := h.Repo.GetSubjectsByGradeID(gradeID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.InternalError(c, err)
+		return
+	}
+	err = i18n.Localize(c.Request.Context(), h.Translations, models.EntitySubject, i18n.FromContext(c), subjects,
+		func(s *models.Subject) int64 { return int64(s.ID) },
+		func(s *models.Subject, f models.TranslationFields) { i18n.Text(&s.Name, f.Name) })
+	if err != nil {
+		logging.InternalError(c, err)
 		return
 	}
 
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
 	"github.com/tharindulakmal/sl-edu-service/internal/models"
 	"github.com/tharindulakmal/sl-edu-service/internal/repository"
 
@@ -11,11 +13,12 @@ import (
 )
 
 type TopicHandler struct {
-	Repo repository.TopicRepositoryInterface
+	Repo         repository.TopicRepositoryInterface
+	Translations i18n.Store
 }
 
-func NewTopicHandler(repo repository.TopicRepositoryInterface) *TopicHandler {
-	return &TopicHandler{Repo: repo}
+func NewTopicHandler(repo repository.TopicRepositoryInterface, translations i18n.Store) *TopicHandler {
+	return &TopicHandler{Repo: repo, Translations: translations}
 }
 
 func (h *TopicHandler) GetTopics(c *gin.Context) {
@@ -28,13 +31,18 @@ func (h *TopicHandler) GetTopics(c *gin.Context) {
 
 	topics, err := h.Repo.GetTopicsByLesson(lessonId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.InternalError(c, err)
 		return
 	}
 
 	defaultSN, err := h.Repo.GetDefaultSmartNote(lessonId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.InternalError(c, err)
+		return
+	}
+
+	if err := h.localize(c, topics, &defaultSN); err != nil {
+		logging.InternalError(c, err)
 		return
 	}
 
@@ -45,3 +53,31 @@ func (h *TopicHandler) GetTopics(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// localize translates the topics, their subtopics and the default note.
+func (h *TopicHandler) localize(c *gin.Context, topics []models.Topic, note *models.SmartNote) error {
+	ctx, lang := c.Request.Context(), i18n.FromContext(c)
+	err := i18n.Localize(ctx, h.Translations, models.EntityTopic, lang, topics,
+		func(t *models.Topic) int64 { return int64(t.TopicID) },
+		func(t *models.Topic, f models.TranslationFields) { i18n.Text(&t.TopicName, f.Name) })
+	if err != nil {
+		return err
+	}
+
+	var subs []models.SubTopic
+	for _, t := range topics {
+		subs = append(subs, t.SubTopicList...)
+	}
+	err = i18n.Localize(ctx, h.Translations, models.EntitySubtopic, lang, subs,
+		func(s *models.SubTopic) int64 { return int64(s.SubTopicID) },
+		func(s *models.SubTopic, f models.TranslationFields) { i18n.Text(&s.SubTopicName, f.Name) })
+	if err != nil {
+		return err
+	}
+	for i := range topics {
+		n := copy(topics[i].SubTopicList, subs)
+		subs = subs[n:]
+	}
+
+	return localizeSmartNote(ctx, h.Translations, lang, note)
+}
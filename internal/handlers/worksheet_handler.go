@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+	"github.com/tharindulakmal/sl-edu-service/internal/worksheet"
+)
+
+// maxWorksheetQuestions keeps printed worksheets to a sensible size.
+const maxWorksheetQuestions = 200
+
+type WorksheetHandler struct {
+	questions repository.QuestionRepository
+	images    worksheet.ImageLoader
+}
+
+func NewWorksheetHandler(questions repository.QuestionRepository, images worksheet.ImageLoader) *WorksheetHandler {
+	return &WorksheetHandler{questions: questions, images: images}
+}
+
+// GET /api/v1/mcq/questions/worksheet?lessonId=1&seed=4&title=Fractions
+// Takes the admin question list filters, so questions in any review status
+// are printed unless ?status= asks for one. The standard PDF fonts only
+// cover Latin script, so questions are printed in their base language.
+func (h *WorksheetHandler) GetWorksheet(c *gin.Context) {
+	items, title, ok := h.items(c)
+	if !ok {
+		return
+	}
+	var buf bytes.Buffer
+	if err := worksheet.Worksheet(c.Request.Context(), &buf, title, items, h.images); err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="worksheet.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// GET /api/v1/mcq/questions/worksheet/answer-key?lessonId=1&seed=4
+// Letters match the worksheet printed with the same filters and seed.
+func (h *WorksheetHandler) GetAnswerKey(c *gin.Context) {
+	items, title, ok := h.items(c)
+	if !ok {
+		return
+	}
+	var buf bytes.Buffer
+	if err := worksheet.AnswerKey(&buf, title, items); err != nil {
+		logging.InternalError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="answer-key.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+func (h *WorksheetHandler) items(c *gin.Context) ([]worksheet.Item, string, bool) {
+	seed := int64(1)
+	if v := c.Query("seed"); v != "" {
+		var err error
+		if seed, err = strconv.ParseInt(v, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "seed must be an integer"})
+			return nil, "", false
+		}
+	}
+	title := strings.TrimSpace(c.Query("title"))
+	if title == "" {
+		title = "Worksheet"
+	}
+
+	filters, ok := authoringFilters(c)
+	if !ok {
+		return nil, "", false
+	}
+	questions, err := h.questions.GetList(filters, 1, maxWorksheetQuestions+1)
+	if err != nil {
+		logging.InternalError(c, err)
+		return nil, "", false
+	}
+	if len(questions) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no questions match the filters"})
+		return nil, "", false
+	}
+	if len(questions) > maxWorksheetQuestions {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "more than " + strconv.Itoa(maxWorksheetQuestions) + " questions match; narrow the filters"})
+		return nil, "", false
+	}
+	return worksheet.Prepare(questions, seed), title, true
+}
@@ -0,0 +1,137 @@
+// Package health backs the liveness and readiness probes. Liveness only says
+// the process is serving; readiness runs a check per dependency and fails
+// while any of them fails or the server is shutting down.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Check reports on one dependency. The detail, such as a version, is shown
+// whether or not it failed.
+type Check func(ctx context.Context) (detail string, err error)
+
+// Result is one dependency in the /readyz body.
+type Result struct {
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the /readyz body.
+type Report struct {
+	Status   string            `json:"status"`
+	Draining bool              `json:"draining,omitempty"`
+	Checks   map[string]Result `json:"checks"`
+}
+
+// Checker holds the readiness checks. Background workers register a check
+// when they start, so a stalled one takes the instance out of rotation.
+type Checker struct {
+	mu       sync.RWMutex
+	checks   map[string]Check
+	draining atomic.Bool
+
+	// Timeout bounds each check.
+	Timeout time.Duration
+}
+
+func NewChecker() *Checker {
+	return &Checker{checks: make(map[string]Check), Timeout: 2 * time.Second}
+}
+
+// Register adds or replaces the check called name.
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Drain marks the server as shutting down, so readiness fails and load
+// balancers stop sending it new requests while in-flight ones finish.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Run runs every check concurrently.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = c.checks[name]
+	}
+	c.mu.RUnlock()
+
+	results := make([]Result, len(names))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = c.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Draining: c.draining.Load(), Checks: make(map[string]Result, len(names))}
+	if report.Draining {
+		report.Status = StatusUnavailable
+	}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+	detail, err := check(ctx)
+	if err != nil {
+		return Result{Status: StatusUnavailable, Detail: detail, Error: err.Error()}
+	}
+	return Result{Status: StatusOK, Detail: detail}
+}
+
+// Live answers the liveness probe: the process is up and serving HTTP.
+func Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": StatusOK})
+}
+
+// Ready answers the readiness probe with 503 unless every check passes.
+func (c *Checker) Ready(ctx *gin.Context) {
+	report := c.Run(ctx.Request.Context())
+	code := http.StatusOK
+	if report.Status != StatusOK {
+		code = http.StatusServiceUnavailable
+	}
+	ctx.JSON(code, report)
+}
+
+// Routes adds the probes to router. /health stays as an alias of /livez so
+// existing load balancer settings keep working.
+func (c *Checker) Routes(router gin.IRoutes) {
+	router.GET("/livez", Live)
+	router.GET("/health", Live)
+	router.GET("/readyz", c.Ready)
+}
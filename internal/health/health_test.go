@@ -0,0 +1,70 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func probe(t *testing.T, checker *Checker, path string) (int, Report) {
+	t.Helper()
+	router := gin.New()
+	checker.Routes(router)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	var report Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	return w.Code, report
+}
+
+func TestReadyReportsEachCheck(t *testing.T) {
+	checker := NewChecker()
+	checker.Register("db", func(context.Context) (string, error) { return "", nil })
+	checker.Register("migrations", func(context.Context) (string, error) { return "version 29", nil })
+
+	code, report := probe(t, checker, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Equal(t, Result{Status: StatusOK, Detail: "version 29"}, report.Checks["migrations"])
+
+	checker.Register("db", func(context.Context) (string, error) { return "", errors.New("connection refused") })
+	code, report = probe(t, checker, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, "connection refused", report.Checks["db"].Error)
+	assert.Equal(t, StatusOK, report.Checks["migrations"].Status)
+}
+
+func TestReadyTimesOutSlowChecks(t *testing.T) {
+	checker := NewChecker()
+	checker.Timeout = 10 * time.Millisecond
+	checker.Register("worker", func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+
+	code, report := probe(t, checker, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["worker"].Error)
+}
+
+func TestDrainFailsReadinessButNotLiveness(t *testing.T) {
+	checker := NewChecker()
+	checker.Drain()
+
+	code, report := probe(t, checker, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.True(t, report.Draining)
+
+	code, report = probe(t, checker, "/livez")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusOK, report.Status)
+}
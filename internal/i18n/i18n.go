@@ -0,0 +1,158 @@
+// Package i18n picks the medium a request is served in (Sinhala, Tamil or
+// English) and overlays stored translations onto records loaded in the base
+// language.
+//
+// Fallback rules: the text stored on an entity itself is English. A request
+// in another language gets the translation for that language where one
+// exists; entities without a translation, and fields a translation leaves
+// empty, keep the English text.
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+const (
+	Sinhala = "si"
+	Tamil   = "ta"
+	English = "en"
+
+	// Base is the language of the text stored on the entities themselves.
+	Base = English
+)
+
+// Supported lists the languages content can be served in.
+var Supported = []string{Sinhala, Tamil, English}
+
+const langKey = "i18n.lang"
+
+// Valid reports whether lang is one of the supported language codes.
+func Valid(lang string) bool {
+	for _, l := range Supported {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate picks the response language. An explicit ?lang= wins, then the
+// highest weighted supported entry of the Accept-Language header, then Base.
+// Region subtags are ignored, so si-LK selects Sinhala.
+func Negotiate(query, acceptLanguage string) string {
+	if lang := primaryTag(query); Valid(lang) {
+		return lang
+	}
+
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang := primaryTag(tag)
+		if !Valid(lang) {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return Base
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+func primaryTag(tag string) string {
+	tag, _, _ = strings.Cut(strings.TrimSpace(tag), "-")
+	return strings.ToLower(tag)
+}
+
+// Middleware negotiates the request language and stores it on the context.
+// An explicit but unsupported ?lang= is rejected rather than ignored.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if q := c.Query("lang"); q != "" && !Valid(primaryTag(q)) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "lang must be one of " + strings.Join(Supported, ", ")})
+			return
+		}
+		lang := Negotiate(c.Query("lang"), c.GetHeader("Accept-Language"))
+		c.Set(langKey, lang)
+		c.Header("Content-Language", lang)
+		c.Header("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+// FromContext returns the negotiated language, or Base when Middleware did
+// not run.
+func FromContext(c *gin.Context) string {
+	if v, ok := c.Get(langKey); ok {
+		if lang, ok := v.(string); ok {
+			return lang
+		}
+	}
+	return Base
+}
+
+// Store loads the translations of a batch of entities in one language, keyed
+// by entity id. Entities without a translation are absent from the map.
+type Store interface {
+	Translations(ctx context.Context, entity, lang string, ids []int64) (map[int64]models.TranslationFields, error)
+}
+
+// Localize overlays the lang translations of entity onto items in place. id
+// returns an item's entity id and apply copies the translated fields onto
+// it. Nothing is loaded for the base language or when store is nil.
+func Localize[T any](ctx context.Context, store Store, entity, lang string, items []T, id func(*T) int64, apply func(*T, models.TranslationFields)) error {
+	if store == nil || lang == Base || len(items) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(items))
+	for i := range items {
+		ids[i] = id(&items[i])
+	}
+	translations, err := store.Translations(ctx, entity, lang, ids)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		if f, ok := translations[ids[i]]; ok {
+			apply(&items[i], f)
+		}
+	}
+	return nil
+}
+
+// Text replaces *dst with src when the translation has a value for it.
+func Text(dst *string, src *string) {
+	if src != nil && strings.TrimSpace(*src) != "" {
+		*dst = *src
+	}
+}
+
+// OptionalText is Text for nullable base columns.
+func OptionalText(dst **string, src *string) {
+	if src != nil && strings.TrimSpace(*src) != "" {
+		v := *src
+		*dst = &v
+	}
+}
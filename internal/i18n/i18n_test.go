@@ -0,0 +1,97 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := []struct {
+		name, query, header, want string
+	}{
+		{"default", "", "", English},
+		{"query wins", "ta", "si-LK", Tamil},
+		{"region subtag", "", "si-LK,en;q=0.5", Sinhala},
+		{"weights", "", "en;q=0.4, ta;q=0.9", Tamil},
+		{"unsupported skipped", "", "fr-FR, ta;q=0.2", Tamil},
+		{"zero weight excluded", "", "si;q=0", English},
+		{"nothing supported", "", "de, fr", English},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Negotiate(tc.query, tc.header))
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/", func(c *gin.Context) { c.String(http.StatusOK, FromContext(c)) })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "ta-LK")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, Tamil, w.Body.String())
+	assert.Equal(t, Tamil, w.Header().Get("Content-Language"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?lang=fr", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+type fakeStore struct {
+	calls int
+	rows  map[int64]models.TranslationFields
+}
+
+func (f *fakeStore) Translations(_ context.Context, _, _ string, _ []int64) (map[int64]models.TranslationFields, error) {
+	f.calls++
+	return f.rows, nil
+}
+
+func ptr(s string) *string { return &s }
+
+func TestLocalizeFallsBackPerField(t *testing.T) {
+	type note struct {
+		id         int64
+		title      string
+		definition *string
+	}
+	store := &fakeStore{rows: map[int64]models.TranslationFields{
+		1: {SubTopicName: ptr("ත්‍රිකෝණ"), Definition: ptr("  ")},
+	}}
+	items := []note{{1, "Triangles", ptr("A three sided shape")}, {2, "Squares", nil}}
+
+	err := Localize(context.Background(), store, models.EntitySmartNote, Sinhala, items,
+		func(n *note) int64 { return n.id },
+		func(n *note, f models.TranslationFields) {
+			Text(&n.title, f.SubTopicName)
+			OptionalText(&n.definition, f.Definition)
+		})
+	require.NoError(t, err)
+
+	assert.Equal(t, "ත්‍රිකෝණ", items[0].title)
+	assert.Equal(t, "A three sided shape", *items[0].definition, "blank translation keeps the base text")
+	assert.Equal(t, "Squares", items[1].title, "untranslated entity keeps the base text")
+}
+
+func TestLocalizeSkipsBaseLanguage(t *testing.T) {
+	store := &fakeStore{}
+	items := []int64{1}
+	err := Localize(context.Background(), store, models.EntityGrade, Base, items,
+		func(id *int64) int64 { return *id },
+		func(*int64, models.TranslationFields) {})
+	require.NoError(t, err)
+	assert.Zero(t, store.calls)
+}
@@ -0,0 +1,402 @@
+// Package importer bulk-loads MCQs from CSV or JSON files. Each row is
+// validated and its curriculum references resolved on its own, so a single
+// bad row is reported without rejecting the rest of the file.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/dedupe"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	menuconfigmodels "github.com/tharindulakmal/sl-edu-service/internal/models/menuconfig"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+// Lookup resolves curriculum references. *repository.MenuConfigRepository
+// satisfies it.
+type Lookup interface {
+	GetGrade(ctx context.Context, id int64) (*menuconfigmodels.Grade, error)
+	FindGradeByName(ctx context.Context, name string) (*menuconfigmodels.Grade, error)
+	GetSubject(ctx context.Context, id int64) (*menuconfigmodels.Subject, error)
+	GetLesson(ctx context.Context, id int64) (*menuconfigmodels.Lesson, error)
+	FindLessonByName(ctx context.Context, gradeID int64, name string) (*menuconfigmodels.Lesson, error)
+	GetTopic(ctx context.Context, id int64) (*menuconfigmodels.Topic, error)
+	FindTopicByName(ctx context.Context, lessonID int64, name string) (*menuconfigmodels.Topic, error)
+	GetSubtopic(ctx context.Context, id int64) (*menuconfigmodels.Subtopic, error)
+	FindSubtopicByName(ctx context.Context, topicID int64, name string) (*menuconfigmodels.Subtopic, error)
+}
+
+// Writer stores the valid questions of an import in one transaction, and
+// lists the questions a lesson already has so duplicates can be caught.
+// repository.QuestionRepository satisfies it.
+type Writer interface {
+	CreateMany(ctx context.Context, qs []models.Question) ([]int64, error)
+	DuplicateCandidates(ctx context.Context, lessonID int) ([]dedupe.Item, error)
+}
+
+type Options struct {
+	// DryRun validates every row without writing anything.
+	DryRun bool
+	// TutorID, when set, overrides the tutorId of every row. It is used to
+	// keep tutor imports scoped to the tutor's own questions.
+	TutorID *int
+	// Duplicates is dedupe.ModeWarn (the default when empty), ModeBlock or
+	// ModeIgnore. Rows are checked against the questions already in their
+	// lesson and against earlier rows of the file.
+	Duplicates string
+}
+
+type RowResult struct {
+	Line       int                     `json:"line"`
+	ID         *int64                  `json:"id,omitempty"`
+	Errors     []string                `json:"errors,omitempty"`
+	Duplicates []models.DuplicateMatch `json:"duplicates,omitempty"`
+}
+
+type Report struct {
+	DryRun  bool        `json:"dryRun"`
+	Total   int         `json:"total"`
+	Valid   int         `json:"valid"`
+	Created int         `json:"created"`
+	Failed  int         `json:"failed"`
+	Rows    []RowResult `json:"rows"`
+}
+
+type Importer struct {
+	lookup Lookup
+	writer Writer
+}
+
+func New(lookup Lookup, writer Writer) *Importer {
+	return &Importer{lookup: lookup, writer: writer}
+}
+
+// Run validates rows and, unless opts.DryRun is set, inserts the valid ones.
+// Row problems end up in the report; the returned error is reserved for
+// failures that stop the whole import, such as a lost database connection.
+func (im *Importer) Run(ctx context.Context, rows []Row, opts Options) (*Report, error) {
+	report := &Report{DryRun: opts.DryRun, Total: len(rows), Rows: make([]RowResult, len(rows))}
+	res := newResolver(im.lookup)
+	dups := duplicateChecker{writer: im.writer, existing: map[int][]dedupe.Item{}, imported: map[int][]importedItem{}}
+
+	valid := make([]models.Question, 0, len(rows))
+	validIdx := make([]int, 0, len(rows))
+	for i, row := range rows {
+		report.Rows[i].Line = row.Line
+		q, problems, err := res.toQuestion(ctx, row)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", row.Line, err)
+		}
+		if len(problems) > 0 {
+			report.Rows[i].Errors = problems
+			report.Failed++
+			continue
+		}
+		if opts.TutorID != nil {
+			q.TutorID = opts.TutorID
+		}
+		if opts.Duplicates != dedupe.ModeIgnore {
+			matches, err := dups.check(ctx, row.Line, *q)
+			if err != nil {
+				return nil, err
+			}
+			report.Rows[i].Duplicates = matches
+			if opts.Duplicates == dedupe.ModeBlock && len(matches) > 0 {
+				report.Rows[i].Errors = []string{"question looks like a duplicate"}
+				report.Failed++
+				continue
+			}
+		}
+		valid = append(valid, *q)
+		validIdx = append(validIdx, i)
+	}
+	report.Valid = len(valid)
+
+	if opts.DryRun || len(valid) == 0 {
+		return report, nil
+	}
+
+	ids, err := im.writer.CreateMany(ctx, valid)
+	if err != nil {
+		return nil, err
+	}
+	for n, i := range validIdx {
+		id := ids[n]
+		report.Rows[i].ID = &id
+	}
+	report.Created = len(ids)
+	return report, nil
+}
+
+// duplicateChecker compares rows with their lesson's existing questions,
+// loaded once per lesson, and with the rows before them.
+type duplicateChecker struct {
+	writer   Writer
+	existing map[int][]dedupe.Item
+	imported map[int][]importedItem
+}
+
+type importedItem struct {
+	line int
+	item dedupe.Item
+}
+
+func (d *duplicateChecker) check(ctx context.Context, line int, q models.Question) ([]models.DuplicateMatch, error) {
+	existing, ok := d.existing[q.LessonID]
+	if !ok {
+		var err error
+		if existing, err = d.writer.DuplicateCandidates(ctx, q.LessonID); err != nil {
+			return nil, err
+		}
+		d.existing[q.LessonID] = existing
+	}
+
+	it := dedupe.ItemOf(q)
+	var matches []models.DuplicateMatch
+	for _, m := range dedupe.Find(it, existing, dedupe.DefaultThreshold) {
+		other := existing[m.Index]
+		matches = append(matches, models.DuplicateMatch{QuestionID: other.ID, LessonID: other.LessonID, Question: other.Question, Score: m.Score})
+	}
+	earlier := d.imported[q.LessonID]
+	pool := make([]dedupe.Item, len(earlier))
+	for n, e := range earlier {
+		pool[n] = e.item
+	}
+	for _, m := range dedupe.Find(it, pool, dedupe.DefaultThreshold) {
+		matches = append(matches, models.DuplicateMatch{Line: earlier[m.Index].line, LessonID: q.LessonID, Question: pool[m.Index].Question, Score: m.Score})
+	}
+	d.imported[q.LessonID] = append(earlier, importedItem{line: line, item: it})
+	return matches, nil
+}
+
+// resolver caches lookups so a file with hundreds of rows for the same lesson
+// only hits the database once per distinct reference.
+type resolver struct {
+	lookup   Lookup
+	grades   map[string]*menuconfigmodels.Grade
+	lessons  map[string]*menuconfigmodels.Lesson
+	subjects map[int64]*menuconfigmodels.Subject
+	topics   map[string]*menuconfigmodels.Topic
+	subs     map[string]*menuconfigmodels.Subtopic
+}
+
+func newResolver(lookup Lookup) *resolver {
+	return &resolver{
+		lookup:   lookup,
+		grades:   map[string]*menuconfigmodels.Grade{},
+		lessons:  map[string]*menuconfigmodels.Lesson{},
+		subjects: map[int64]*menuconfigmodels.Subject{},
+		topics:   map[string]*menuconfigmodels.Topic{},
+		subs:     map[string]*menuconfigmodels.Subtopic{},
+	}
+}
+
+func (r *resolver) toQuestion(ctx context.Context, row Row) (*models.Question, []string, error) {
+	problems := append([]string{}, row.parseErrors...)
+
+	if row.Question == "" {
+		problems = append(problems, "question is required")
+	}
+	if row.CorrectAnswer == "" {
+		problems = append(problems, "correctAnswer is required")
+	} else if len(row.CorrectAnswer) > 255 {
+		problems = append(problems, "correctAnswer must be at most 255 characters")
+	}
+	if len(row.OtherAnswers) == 0 {
+		problems = append(problems, "otherAnswers must list at least one option")
+	}
+	if len(row.QuestionImg) > 512 {
+		problems = append(problems, "questionImgUrl must be at most 512 characters")
+	}
+
+	grade, problem, err := r.grade(ctx, row)
+	if err != nil {
+		return nil, nil, err
+	}
+	if problem != "" {
+		return nil, append(problems, problem), nil
+	}
+	lesson, problem, err := r.lesson(ctx, row, grade)
+	if err != nil {
+		return nil, nil, err
+	}
+	if problem != "" {
+		return nil, append(problems, problem), nil
+	}
+	topic, problem, err := r.topic(ctx, row, lesson)
+	if err != nil {
+		return nil, nil, err
+	}
+	if problem != "" {
+		return nil, append(problems, problem), nil
+	}
+	subtopic, problem, err := r.subtopic(ctx, row, topic)
+	if err != nil {
+		return nil, nil, err
+	}
+	if problem != "" {
+		problems = append(problems, problem)
+	}
+	if len(problems) > 0 {
+		return nil, problems, nil
+	}
+
+	q := &models.Question{
+		GradeID:       int(grade.ID),
+		LessonID:      int(lesson.ID),
+		TutorID:       intPtr(row.TutorID),
+		TuteID:        intPtr(row.TuteID),
+		Question:      row.Question,
+		QuestionImg:   strPtr(row.QuestionImg),
+		CorrectAnswer: row.CorrectAnswer,
+		Theory:        strPtr(row.Theory),
+		Solution:      strPtr(row.Solution),
+		OtherAnswers:  models.StringArray(row.OtherAnswers),
+	}
+	if topic != nil {
+		id := int(topic.ID)
+		q.TopicID = &id
+	}
+	if subtopic != nil {
+		id := int(subtopic.ID)
+		q.SubtopicID = &id
+	}
+	return q, nil, nil
+}
+
+func (r *resolver) grade(ctx context.Context, row Row) (*menuconfigmodels.Grade, string, error) {
+	var key string
+	var find func() (*menuconfigmodels.Grade, error)
+	switch {
+	case row.GradeID != nil:
+		key = fmt.Sprintf("id:%d", *row.GradeID)
+		find = func() (*menuconfigmodels.Grade, error) { return r.lookup.GetGrade(ctx, *row.GradeID) }
+	case row.Grade != "":
+		key = "name:" + strings.ToLower(row.Grade)
+		find = func() (*menuconfigmodels.Grade, error) { return r.lookup.FindGradeByName(ctx, row.Grade) }
+	default:
+		return nil, "gradeId or grade is required", nil
+	}
+	return cached(r.grades, key, find, "grade")
+}
+
+func (r *resolver) lesson(ctx context.Context, row Row, grade *menuconfigmodels.Grade) (*menuconfigmodels.Lesson, string, error) {
+	var key string
+	var find func() (*menuconfigmodels.Lesson, error)
+	switch {
+	case row.LessonID != nil:
+		key = fmt.Sprintf("id:%d", *row.LessonID)
+		find = func() (*menuconfigmodels.Lesson, error) { return r.lookup.GetLesson(ctx, *row.LessonID) }
+	case row.Lesson != "":
+		key = fmt.Sprintf("grade:%d:name:%s", grade.ID, strings.ToLower(row.Lesson))
+		find = func() (*menuconfigmodels.Lesson, error) { return r.lookup.FindLessonByName(ctx, grade.ID, row.Lesson) }
+	default:
+		return nil, "lessonId or lesson is required", nil
+	}
+	lesson, problem, err := cached(r.lessons, key, find, "lesson")
+	if lesson == nil {
+		return nil, problem, err
+	}
+
+	subject, ok := r.subjects[lesson.SubjectID]
+	if !ok {
+		subject, err = r.lookup.GetSubject(ctx, lesson.SubjectID)
+		if err != nil && !errors.Is(err, repository.ErrMenuConfigNotFound) {
+			return nil, "", err
+		}
+		r.subjects[lesson.SubjectID] = subject
+	}
+	if subject == nil || subject.GradeID != grade.ID {
+		return nil, fmt.Sprintf("lesson %d does not belong to grade %d", lesson.ID, grade.ID), nil
+	}
+	return lesson, "", nil
+}
+
+func (r *resolver) topic(ctx context.Context, row Row, lesson *menuconfigmodels.Lesson) (*menuconfigmodels.Topic, string, error) {
+	var key string
+	var find func() (*menuconfigmodels.Topic, error)
+	switch {
+	case row.TopicID != nil:
+		key = fmt.Sprintf("id:%d", *row.TopicID)
+		find = func() (*menuconfigmodels.Topic, error) { return r.lookup.GetTopic(ctx, *row.TopicID) }
+	case row.Topic != "":
+		key = fmt.Sprintf("lesson:%d:name:%s", lesson.ID, strings.ToLower(row.Topic))
+		find = func() (*menuconfigmodels.Topic, error) { return r.lookup.FindTopicByName(ctx, lesson.ID, row.Topic) }
+	default:
+		if row.SubtopicID != nil || row.Subtopic != "" {
+			return nil, "topicId or topic is required when a subtopic is given", nil
+		}
+		return nil, "", nil
+	}
+	topic, problem, err := cached(r.topics, key, find, "topic")
+	if topic != nil && topic.LessonID != lesson.ID {
+		return nil, fmt.Sprintf("topic %d does not belong to lesson %d", topic.ID, lesson.ID), nil
+	}
+	return topic, problem, err
+}
+
+func (r *resolver) subtopic(ctx context.Context, row Row, topic *menuconfigmodels.Topic) (*menuconfigmodels.Subtopic, string, error) {
+	if topic == nil {
+		return nil, "", nil
+	}
+	var key string
+	var find func() (*menuconfigmodels.Subtopic, error)
+	switch {
+	case row.SubtopicID != nil:
+		key = fmt.Sprintf("id:%d", *row.SubtopicID)
+		find = func() (*menuconfigmodels.Subtopic, error) { return r.lookup.GetSubtopic(ctx, *row.SubtopicID) }
+	case row.Subtopic != "":
+		key = fmt.Sprintf("topic:%d:name:%s", topic.ID, strings.ToLower(row.Subtopic))
+		find = func() (*menuconfigmodels.Subtopic, error) {
+			return r.lookup.FindSubtopicByName(ctx, topic.ID, row.Subtopic)
+		}
+	default:
+		return nil, "", nil
+	}
+	subtopic, problem, err := cached(r.subs, key, find, "subtopic")
+	if subtopic != nil && subtopic.TopicID != topic.ID {
+		return nil, fmt.Sprintf("subtopic %d does not belong to topic %d", subtopic.ID, topic.ID), nil
+	}
+	return subtopic, problem, err
+}
+
+// cached runs find once per key. Not-found and ambiguous results are turned
+// into row problems; any other error is returned as is.
+func cached[T any](cache map[string]*T, key string, find func() (*T, error), label string) (*T, string, error) {
+	if v, ok := cache[key]; ok {
+		if v == nil {
+			return nil, label + " not found", nil
+		}
+		return v, "", nil
+	}
+	v, err := find()
+	switch {
+	case errors.Is(err, repository.ErrMenuConfigNotFound):
+		cache[key] = nil
+		return nil, label + " not found", nil
+	case errors.Is(err, repository.ErrMenuConfigAmbiguous):
+		return nil, label + " name is ambiguous, use its id instead", nil
+	case err != nil:
+		return nil, "", err
+	}
+	cache[key] = v
+	return v, "", nil
+}
+
+func intPtr(v *int64) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+func strPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,189 @@
+package importer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/dedupe"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	menuconfigmodels "github.com/tharindulakmal/sl-edu-service/internal/models/menuconfig"
+	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+)
+
+type fakeLookup struct{}
+
+var (
+	grade6   = &menuconfigmodels.Grade{ID: 6, Name: "Grade 6"}
+	maths    = &menuconfigmodels.Subject{ID: 10, GradeID: 6, Name: "Mathematics"}
+	geometry = &menuconfigmodels.Lesson{ID: 1, SubjectID: 10, Name: "Geometry"}
+	shapes   = &menuconfigmodels.Topic{ID: 1, LessonID: 1, Name: "Shapes"}
+	squares  = &menuconfigmodels.Subtopic{ID: 2, TopicID: 1, Name: "Squares"}
+)
+
+func (fakeLookup) GetGrade(_ context.Context, id int64) (*menuconfigmodels.Grade, error) {
+	if id == grade6.ID {
+		return grade6, nil
+	}
+	return nil, repository.ErrMenuConfigNotFound
+}
+
+func (fakeLookup) FindGradeByName(_ context.Context, name string) (*menuconfigmodels.Grade, error) {
+	if strings.EqualFold(name, grade6.Name) {
+		return grade6, nil
+	}
+	return nil, repository.ErrMenuConfigNotFound
+}
+
+func (fakeLookup) GetSubject(_ context.Context, id int64) (*menuconfigmodels.Subject, error) {
+	if id == maths.ID {
+		return maths, nil
+	}
+	return nil, repository.ErrMenuConfigNotFound
+}
+
+func (fakeLookup) GetLesson(_ context.Context, id int64) (*menuconfigmodels.Lesson, error) {
+	if id == geometry.ID {
+		return geometry, nil
+	}
+	return nil, repository.ErrMenuConfigNotFound
+}
+
+func (fakeLookup) FindLessonByName(_ context.Context, gradeID int64, name string) (*menuconfigmodels.Lesson, error) {
+	if gradeID == grade6.ID && strings.EqualFold(name, geometry.Name) {
+		return geometry, nil
+	}
+	return nil, repository.ErrMenuConfigNotFound
+}
+
+func (fakeLookup) GetTopic(_ context.Context, id int64) (*menuconfigmodels.Topic, error) {
+	if id == shapes.ID {
+		return shapes, nil
+	}
+	return nil, repository.ErrMenuConfigNotFound
+}
+
+func (fakeLookup) FindTopicByName(_ context.Context, lessonID int64, name string) (*menuconfigmodels.Topic, error) {
+	if lessonID == shapes.LessonID && strings.EqualFold(name, shapes.Name) {
+		return shapes, nil
+	}
+	return nil, repository.ErrMenuConfigNotFound
+}
+
+func (fakeLookup) GetSubtopic(_ context.Context, id int64) (*menuconfigmodels.Subtopic, error) {
+	if id == squares.ID {
+		return squares, nil
+	}
+	return nil, repository.ErrMenuConfigNotFound
+}
+
+func (fakeLookup) FindSubtopicByName(_ context.Context, topicID int64, name string) (*menuconfigmodels.Subtopic, error) {
+	if topicID == squares.TopicID && strings.EqualFold(name, squares.Name) {
+		return squares, nil
+	}
+	return nil, repository.ErrMenuConfigNotFound
+}
+
+type fakeWriter struct {
+	saved    []models.Question
+	existing []dedupe.Item
+}
+
+func (w *fakeWriter) DuplicateCandidates(_ context.Context, lessonID int) ([]dedupe.Item, error) {
+	var out []dedupe.Item
+	for _, it := range w.existing {
+		if it.LessonID == lessonID {
+			out = append(out, it)
+		}
+	}
+	return out, nil
+}
+
+func (w *fakeWriter) CreateMany(_ context.Context, qs []models.Question) ([]int64, error) {
+	ids := make([]int64, len(qs))
+	for i := range qs {
+		w.saved = append(w.saved, qs[i])
+		ids[i] = int64(len(w.saved))
+	}
+	return ids, nil
+}
+
+const sampleCSV = `grade,lesson,topic,subtopic,question,correctAnswer,otherAnswers,solution
+Grade 6,Geometry,Shapes,Squares,Perimeter of a square with side 5?,20,10|15|25,4 x 5 = 20
+Grade 6,History,,,Who?,Someone,A|B,
+Grade 6,Geometry,,,Missing answer,,1|2,
+`
+
+func TestImportCSVResolvesNamesAndReportsRowErrors(t *testing.T) {
+	rows, err := ParseCSV(strings.NewReader(sampleCSV))
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	writer := &fakeWriter{}
+	report, err := New(fakeLookup{}, writer).Run(context.Background(), rows, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.Failed)
+
+	assert.NotNil(t, report.Rows[0].ID)
+	assert.Equal(t, 2, report.Rows[0].Line)
+	assert.Contains(t, report.Rows[1].Errors, "lesson not found")
+	assert.Contains(t, report.Rows[2].Errors, "correctAnswer is required")
+
+	require.Len(t, writer.saved, 1)
+	saved := writer.saved[0]
+	assert.Equal(t, 6, saved.GradeID)
+	assert.Equal(t, 1, saved.LessonID)
+	assert.Equal(t, 2, *saved.SubtopicID)
+	assert.Equal(t, models.StringArray{"10", "15", "25"}, saved.OtherAnswers)
+}
+
+func TestImportJSONDryRunWritesNothing(t *testing.T) {
+	input := `[{"gradeId": 6, "lessonId": "1", "topicId": 1, "question": "Q?", "correctAnswer": "A", "otherAnswers": ["B", "C"]}]`
+	rows, err := ParseJSON(strings.NewReader(input))
+	require.NoError(t, err)
+
+	writer := &fakeWriter{}
+	tutor := 4
+	report, err := New(fakeLookup{}, writer).Run(context.Background(), rows, Options{DryRun: true, TutorID: &tutor})
+	require.NoError(t, err)
+
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Valid)
+	assert.Equal(t, 0, report.Created)
+	assert.Empty(t, report.Rows[0].Errors)
+	assert.Empty(t, writer.saved)
+}
+
+func TestImportReportsAndBlocksDuplicates(t *testing.T) {
+	input := `[
+		{"gradeId": 6, "lessonId": "1", "question": "What is the perimeter of a square with side 5 cm?", "correctAnswer": "20 cm", "otherAnswers": ["10 cm", "25 cm"]},
+		{"gradeId": 6, "lessonId": "1", "question": "What is the area of a square with side 4 cm?", "correctAnswer": "16 cm2", "otherAnswers": ["8 cm2", "12 cm2"]},
+		{"gradeId": 6, "lessonId": "1", "question": "What is the area of a square with side 4 cm", "correctAnswer": "16 cm2", "otherAnswers": ["12 cm2", "8 cm2"]}
+	]`
+	rows, err := ParseJSON(strings.NewReader(input))
+	require.NoError(t, err)
+	existing := []dedupe.Item{{ID: 7, LessonID: 1, Question: "What is the perimeter of a square with side 5cm?", Answers: []string{"20 cm", "10 cm", "25 cm"}}}
+
+	writer := &fakeWriter{existing: existing}
+	report, err := New(fakeLookup{}, writer).Run(context.Background(), rows, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Created)
+	require.Len(t, report.Rows[0].Duplicates, 1)
+	assert.Equal(t, 7, report.Rows[0].Duplicates[0].QuestionID)
+	assert.Empty(t, report.Rows[1].Duplicates)
+	require.Len(t, report.Rows[2].Duplicates, 1)
+	assert.Equal(t, rows[1].Line, report.Rows[2].Duplicates[0].Line)
+
+	writer = &fakeWriter{existing: existing}
+	report, err = New(fakeLookup{}, writer).Run(context.Background(), rows, Options{Duplicates: dedupe.ModeBlock})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.Failed)
+	assert.NotNil(t, report.Rows[1].ID)
+	assert.Contains(t, report.Rows[0].Errors, "question looks like a duplicate")
+}
@@ -0,0 +1,214 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Row is one question as read from an import file. Curriculum references can
+// be given either by id or by name; ids win when both are present.
+type Row struct {
+	Line          int      `json:"line"`
+	GradeID       *int64   `json:"gradeId,omitempty"`
+	Grade         string   `json:"grade,omitempty"`
+	LessonID      *int64   `json:"lessonId,omitempty"`
+	Lesson        string   `json:"lesson,omitempty"`
+	TopicID       *int64   `json:"topicId,omitempty"`
+	Topic         string   `json:"topic,omitempty"`
+	SubtopicID    *int64   `json:"subtopicId,omitempty"`
+	Subtopic      string   `json:"subtopic,omitempty"`
+	TutorID       *int64   `json:"tutorId,omitempty"`
+	TuteID        *int64   `json:"tuteId,omitempty"`
+	Question      string   `json:"question"`
+	QuestionImg   string   `json:"questionImgUrl,omitempty"`
+	CorrectAnswer string   `json:"correctAnswer"`
+	Theory        string   `json:"theory,omitempty"`
+	Solution      string   `json:"solution,omitempty"`
+	OtherAnswers  []string `json:"otherAnswers"`
+
+	// problems found while reading the row, reported alongside validation errors
+	parseErrors []string
+}
+
+// FormatFromName guesses the import format from a file name or content type.
+func FormatFromName(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".csv"), strings.Contains(lower, "text/csv"):
+		return FormatCSV
+	case strings.HasSuffix(lower, ".json"), strings.Contains(lower, "application/json"):
+		return FormatJSON
+	}
+	return ""
+}
+
+// Parse reads all rows from r in the given format.
+func Parse(r io.Reader, format string) ([]Row, error) {
+	switch format {
+	case FormatCSV:
+		return ParseCSV(r)
+	case FormatJSON:
+		return ParseJSON(r)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// ParseCSV reads a CSV file whose first line names the columns. Column names
+// match the JSON field names of models.Question plus grade, lesson, topic and
+// subtopic for name lookups. otherAnswers is either a JSON array or a list
+// separated by "|".
+func ParseCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("file is empty")
+		}
+		return nil, err
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+
+	rows := make([]Row, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(record) {
+				fields[name] = record[i]
+			}
+		}
+		if isBlank(fields) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, rowFromFields(line, fields))
+	}
+	return rows, nil
+}
+
+// ParseJSON reads a JSON array of objects using the same field names as the
+// CSV columns. Ids may be numbers or numeric strings.
+func ParseJSON(r io.Reader) ([]Row, error) {
+	var records []map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	rows := make([]Row, 0, len(records))
+	for i, record := range records {
+		fields := make(map[string]string, len(record))
+		for key, raw := range record {
+			var s string
+			if err := json.Unmarshal(raw, &s); err == nil {
+				fields[key] = s
+				continue
+			}
+			if string(raw) == "null" {
+				continue
+			}
+			// numbers and arrays keep their JSON text
+			fields[key] = string(raw)
+		}
+		rows = append(rows, rowFromFields(i+1, fields))
+	}
+	return rows, nil
+}
+
+func rowFromFields(line int, fields map[string]string) Row {
+	row := Row{
+		Line:          line,
+		Grade:         strings.TrimSpace(fields["grade"]),
+		Lesson:        strings.TrimSpace(fields["lesson"]),
+		Topic:         strings.TrimSpace(fields["topic"]),
+		Subtopic:      strings.TrimSpace(fields["subtopic"]),
+		Question:      strings.TrimSpace(fields["question"]),
+		QuestionImg:   strings.TrimSpace(fields["questionImgUrl"]),
+		CorrectAnswer: strings.TrimSpace(fields["correctAnswer"]),
+		Theory:        strings.TrimSpace(fields["theory"]),
+		Solution:      strings.TrimSpace(fields["solution"]),
+	}
+
+	ids := []struct {
+		field string
+		dest  **int64
+	}{
+		{"gradeId", &row.GradeID},
+		{"lessonId", &row.LessonID},
+		{"topicId", &row.TopicID},
+		{"subtopicId", &row.SubtopicID},
+		{"tutorId", &row.TutorID},
+		{"tuteId", &row.TuteID},
+	}
+	for _, id := range ids {
+		raw := strings.TrimSpace(fields[id.field])
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			row.parseErrors = append(row.parseErrors, id.field+" must be a valid integer")
+			continue
+		}
+		*id.dest = &v
+	}
+
+	answers, err := parseAnswers(fields["otherAnswers"])
+	if err != nil {
+		row.parseErrors = append(row.parseErrors, err.Error())
+	}
+	row.OtherAnswers = answers
+	return row
+}
+
+func parseAnswers(raw string) ([]string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	var answers []string
+	if strings.HasPrefix(raw, "[") {
+		if err := json.Unmarshal([]byte(raw), &answers); err != nil {
+			return nil, errors.New("otherAnswers must be a JSON array of strings")
+		}
+	} else {
+		answers = strings.Split(raw, "|")
+	}
+
+	cleaned := make([]string, 0, len(answers))
+	for _, a := range answers {
+		if a = strings.TrimSpace(a); a != "" {
+			cleaned = append(cleaned, a)
+		}
+	}
+	return cleaned, nil
+}
+
+func isBlank(fields map[string]string) bool {
+	for _, v := range fields {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,421 @@
+// Package irt estimates how hard each question is from students' answers,
+// with classical item statistics and a one or two parameter logistic item
+// response model.
+package irt
+
+import (
+	"math"
+	"sort"
+)
+
+// Response is one marked answer. Person groups the answers given together,
+// such as the questions of one quiz attempt.
+type Response struct {
+	Person  int
+	Item    int
+	Correct bool
+}
+
+// ItemStats describes one question.
+//
+// PValue is the share of correct answers and PointBiserial the correlation
+// between answering the item correctly and the score on the rest of the
+// person's items; it is nil when it cannot be computed. Difficulty (b) and
+// Discrimination (a) are the IRT parameters, in the logit scale where
+// abilities have mean 0 and standard deviation 1. They are nil for items
+// with fewer than Options.MinResponses answers.
+type ItemStats struct {
+	Item           int
+	Responses      int
+	PValue         float64
+	PointBiserial  *float64
+	Difficulty     *float64
+	Discrimination *float64
+}
+
+// Model selects the item response model.
+type Model string
+
+const (
+	// OnePL (Rasch) fixes every discrimination at 1.
+	OnePL Model = "1pl"
+	// TwoPL also fits a discrimination per item.
+	TwoPL Model = "2pl"
+)
+
+// Difficulty bands, by the b parameter.
+const (
+	BandEasy   = "easy"
+	BandMedium = "medium"
+	BandHard   = "hard"
+
+	// items with b below EasyBelow are easy and above HardAbove hard
+	EasyBelow = -1.0
+	HardAbove = 1.0
+)
+
+// Band names the difficulty band of b.
+func Band(b float64) string {
+	switch {
+	case b < EasyBelow:
+		return BandEasy
+	case b > HardAbove:
+		return BandHard
+	}
+	return BandMedium
+}
+
+// Review flags for questions that need an editor's attention.
+const (
+	FlagTooEasy                = "too_easy"
+	FlagTooHard                = "too_hard"
+	FlagNegativeDiscrimination = "negative_discrimination"
+
+	TooEasyAbove = 0.9
+	TooHardBelow = 0.2
+)
+
+// Flags lists what is wrong with an item, given its statistics. Negative
+// discrimination means stronger students get the item wrong more often than
+// weaker ones, which usually points to a wrong answer key.
+func Flags(pValue float64, pointBiserial, discrimination *float64) []string {
+	flags := []string{}
+	switch {
+	case pValue > TooEasyAbove:
+		flags = append(flags, FlagTooEasy)
+	case pValue < TooHardBelow:
+		flags = append(flags, FlagTooHard)
+	}
+	if (pointBiserial != nil && *pointBiserial < 0) || (discrimination != nil && *discrimination < 0) {
+		flags = append(flags, FlagNegativeDiscrimination)
+	}
+	return flags
+}
+
+// Options controls Fit. Zero values take the defaults.
+type Options struct {
+	Model        Model
+	MinResponses int // items with fewer answers get no IRT parameters; default 20
+	Iterations   int // upper bound on estimation rounds; default 200
+}
+
+const (
+	// weak normal priors on the item parameters keep them finite for items
+	// everyone gets right and for sparse data
+	cPriorSD   = 3.0
+	aPriorMean = 1.0
+	aPriorSD   = 1.0
+
+	// b is unstable when a is near zero, so it is reported within this range
+	maxDifficulty = 6.0
+
+	maxStep   = 1.0
+	tolerance = 1e-4
+)
+
+// Fit computes the statistics of every item with at least one response,
+// ordered by item.
+func Fit(responses []Response, opts Options) []ItemStats {
+	if opts.Model == "" {
+		opts.Model = TwoPL
+	}
+	if opts.MinResponses <= 0 {
+		opts.MinResponses = 20
+	}
+	if opts.Iterations <= 0 {
+		opts.Iterations = 200
+	}
+
+	d := index(responses)
+	stats := classical(d)
+
+	fitted := make([]bool, len(d.items))
+	fitAny := false
+	for i := range d.items {
+		if len(d.byItem[i]) >= opts.MinResponses {
+			fitted[i] = true
+			fitAny = true
+		}
+	}
+	if !fitAny {
+		return stats
+	}
+	a, b := estimate(d, fitted, opts)
+	for i := range stats {
+		if fitted[i] {
+			bi, ai := b[i], a[i]
+			stats[i].Difficulty = &bi
+			stats[i].Discrimination = &ai
+		}
+	}
+	return stats
+}
+
+type data struct {
+	items    []int // item ids by index
+	persons  int
+	resp     []obs
+	byItem   [][]int // indexes into resp
+	byPerson [][]int
+}
+
+type obs struct {
+	person, item int
+	y            float64
+}
+
+func index(responses []Response) *data {
+	itemIdx := map[int]int{}
+	personIdx := map[int]int{}
+	d := &data{}
+	for _, r := range responses {
+		if _, ok := itemIdx[r.Item]; !ok {
+			itemIdx[r.Item] = 0
+			d.items = append(d.items, r.Item)
+		}
+	}
+	sort.Ints(d.items)
+	for i, id := range d.items {
+		itemIdx[id] = i
+	}
+	d.byItem = make([][]int, len(d.items))
+
+	for _, r := range responses {
+		p, ok := personIdx[r.Person]
+		if !ok {
+			p = len(personIdx)
+			personIdx[r.Person] = p
+			d.byPerson = append(d.byPerson, nil)
+		}
+		y := 0.0
+		if r.Correct {
+			y = 1
+		}
+		i := itemIdx[r.Item]
+		d.byItem[i] = append(d.byItem[i], len(d.resp))
+		d.byPerson[p] = append(d.byPerson[p], len(d.resp))
+		d.resp = append(d.resp, obs{person: p, item: i, y: y})
+	}
+	d.persons = len(personIdx)
+	return d
+}
+
+// classical computes p-values and the corrected point-biserial correlation,
+// which compares each answer with the share of the person's other items
+// answered correctly, so the item does not correlate with itself.
+func classical(d *data) []ItemStats {
+	correct := make([]float64, d.persons)
+	for _, o := range d.resp {
+		correct[o.person] += o.y
+	}
+
+	stats := make([]ItemStats, len(d.items))
+	for i, id := range d.items {
+		s := ItemStats{Item: id, Responses: len(d.byItem[i])}
+		var xs, ys []float64
+		sum := 0.0
+		for _, k := range d.byItem[i] {
+			o := d.resp[k]
+			sum += o.y
+			others := len(d.byPerson[o.person]) - 1
+			if others == 0 {
+				continue
+			}
+			xs = append(xs, o.y)
+			ys = append(ys, (correct[o.person]-o.y)/float64(others))
+		}
+		s.PValue = sum / float64(s.Responses)
+		if r, ok := correlation(xs, ys); ok {
+			s.PointBiserial = &r
+		}
+		stats[i] = s
+	}
+	return stats
+}
+
+func correlation(xs, ys []float64) (float64, bool) {
+	n := float64(len(xs))
+	if n < 2 {
+		return 0, false
+	}
+	var mx, my float64
+	for i := range xs {
+		mx += xs[i]
+		my += ys[i]
+	}
+	mx, my = mx/n, my/n
+	var sxy, sxx, syy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0, false
+	}
+	return sxy / math.Sqrt(sxx*syy), true
+}
+
+// estimate fits the item parameters by marginal maximum likelihood with
+// EM (Bock and Aitkin): abilities are integrated out over a grid, so the
+// estimates stay consistent when each person answers only a few items.
+// Each item is fitted as a logistic regression on the grid with slope a and
+// intercept c = -a*b. Discriminations are left free to go negative, which
+// is the usual sign of a wrong key.
+func estimate(d *data, fitted []bool, opts Options) (a, b []float64) {
+	nodes, weights := grid()
+	a = make([]float64, len(d.items))
+	c := make([]float64, len(d.items))
+	for i := range a {
+		a[i] = 1
+		// start from the p-value
+		p := 0.0
+		for _, k := range d.byItem[i] {
+			p += d.resp[k].y
+		}
+		p = (p + 0.5) / (float64(len(d.byItem[i])) + 1)
+		c[i] = math.Log(p / (1 - p))
+	}
+
+	q := len(nodes)
+	post := make([]float64, q)
+	n := make([][]float64, len(d.items)) // expected answers at each node
+	r := make([][]float64, len(d.items)) // expected correct answers
+	for i := range n {
+		n[i] = make([]float64, q)
+		r[i] = make([]float64, q)
+	}
+
+	for iter := 0; iter < opts.Iterations; iter++ {
+		for i := range n {
+			for k := 0; k < q; k++ {
+				n[i][k], r[i][k] = 0, 0
+			}
+		}
+
+		// E step: each person's ability distribution given their answers
+		for p := range d.byPerson {
+			maxLog := math.Inf(-1)
+			for k, theta := range nodes {
+				l := math.Log(weights[k])
+				for _, idx := range d.byPerson[p] {
+					o := d.resp[idx]
+					if !fitted[o.item] {
+						continue
+					}
+					z := a[o.item]*theta + c[o.item]
+					if o.y == 1 {
+						l -= softplus(-z)
+					} else {
+						l -= softplus(z)
+					}
+				}
+				post[k] = l
+				maxLog = math.Max(maxLog, l)
+			}
+			total := 0.0
+			for k := range post {
+				post[k] = math.Exp(post[k] - maxLog)
+				total += post[k]
+			}
+			for _, idx := range d.byPerson[p] {
+				o := d.resp[idx]
+				if !fitted[o.item] {
+					continue
+				}
+				for k := range post {
+					w := post[k] / total
+					n[o.item][k] += w
+					r[o.item][k] += w * o.y
+				}
+			}
+		}
+
+		// M step: a few Newton steps per item
+		change := 0.0
+		for i := range a {
+			if !fitted[i] {
+				continue
+			}
+			for step := 0; step < 5; step++ {
+				ga, gc := 0.0, -c[i]/(cPriorSD*cPriorSD)
+				haa, hac, hcc := 0.0, 0.0, -1/(cPriorSD*cPriorSD)
+				if opts.Model == TwoPL {
+					ga = -(a[i] - aPriorMean) / (aPriorSD * aPriorSD)
+					haa = -1 / (aPriorSD * aPriorSD)
+				}
+				for k, theta := range nodes {
+					pr := 1 / (1 + math.Exp(-(a[i]*theta + c[i])))
+					resid := r[i][k] - n[i][k]*pr
+					info := n[i][k] * pr * (1 - pr)
+					ga += resid * theta
+					gc += resid
+					haa -= info * theta * theta
+					hac -= info * theta
+					hcc -= info
+				}
+				var da, dc float64
+				if opts.Model == TwoPL {
+					det := haa*hcc - hac*hac
+					if det == 0 {
+						break
+					}
+					da = -(hcc*ga - hac*gc) / det
+					dc = -(haa*gc - hac*ga) / det
+				} else {
+					dc = -gc / hcc
+				}
+				da = clamp(da, -maxStep, maxStep)
+				dc = clamp(dc, -maxStep, maxStep)
+				a[i] += da
+				c[i] += dc
+				change = math.Max(change, math.Max(math.Abs(da), math.Abs(dc)))
+				if math.Abs(da) < tolerance && math.Abs(dc) < tolerance {
+					break
+				}
+			}
+		}
+		if change < tolerance {
+			break
+		}
+	}
+
+	b = make([]float64, len(d.items))
+	for i := range b {
+		if a[i] != 0 {
+			b[i] = clamp(-c[i]/a[i], -maxDifficulty, maxDifficulty)
+		}
+	}
+	return a, b
+}
+
+// grid returns the quadrature points over the standard normal ability
+// distribution and their normalised weights.
+func grid() (nodes, weights []float64) {
+	const points = 21
+	total := 0.0
+	for k := 0; k < points; k++ {
+		theta := -4 + 8*float64(k)/float64(points-1)
+		w := math.Exp(-theta * theta / 2)
+		nodes = append(nodes, theta)
+		weights = append(weights, w)
+		total += w
+	}
+	for k := range weights {
+		weights[k] /= total
+	}
+	return nodes, weights
+}
+
+// softplus is log(1 + e^x) without overflow.
+func softplus(x float64) float64 {
+	if x > 30 {
+		return x
+	}
+	return math.Log1p(math.Exp(x))
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
@@ -0,0 +1,80 @@
+package irt
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// simulate draws answers from a 2PL model with the given item parameters.
+func simulate(rng *rand.Rand, persons int, a, b []float64) []Response {
+	var out []Response
+	for p := 0; p < persons; p++ {
+		theta := rng.NormFloat64()
+		for i := range a {
+			pr := 1 / (1 + math.Exp(-a[i]*(theta-b[i])))
+			out = append(out, Response{Person: p, Item: i + 1, Correct: rng.Float64() < pr})
+		}
+	}
+	return out
+}
+
+func TestFitRecoversItemParameters(t *testing.T) {
+	a := []float64{1, 1.5, 0.8, 1.2, 1, -1}
+	b := []float64{-2, -1, 0, 1, 2, 0}
+	stats := Fit(simulate(rand.New(rand.NewSource(3)), 800, a, b), Options{})
+	require.Len(t, stats, len(a))
+
+	for i, s := range stats[:5] {
+		assert.Equal(t, i+1, s.Item)
+		require.NotNil(t, s.Difficulty)
+		assert.InDelta(t, b[i], *s.Difficulty, 0.4, "difficulty of item %d", s.Item)
+		assert.InDelta(t, a[i], *s.Discrimination, 0.5, "discrimination of item %d", s.Item)
+		assert.Greater(t, *s.PointBiserial, 0.0)
+	}
+	for i := 1; i < 5; i++ {
+		assert.Less(t, stats[i].PValue, stats[i-1].PValue, "harder items are answered correctly less often")
+	}
+
+	keyed := stats[5]
+	assert.Less(t, *keyed.Discrimination, 0.0)
+	assert.Less(t, *keyed.PointBiserial, 0.0)
+}
+
+func TestFitRaschKeepsDiscriminationFixed(t *testing.T) {
+	stats := Fit(simulate(rand.New(rand.NewSource(5)), 300, []float64{1, 1}, []float64{-1, 1}), Options{Model: OnePL})
+	for _, s := range stats {
+		assert.Equal(t, 1.0, *s.Discrimination)
+	}
+	assert.Less(t, *stats[0].Difficulty, *stats[1].Difficulty)
+}
+
+func TestFitLeavesSparseItemsUncalibrated(t *testing.T) {
+	responses := []Response{
+		{Person: 1, Item: 9, Correct: true},
+		{Person: 2, Item: 9, Correct: true},
+		{Person: 2, Item: 4, Correct: false},
+	}
+	stats := Fit(responses, Options{MinResponses: 5})
+	require.Len(t, stats, 2)
+	assert.Equal(t, 4, stats[0].Item)
+	assert.Equal(t, 1.0, stats[1].PValue)
+	assert.Nil(t, stats[1].Difficulty)
+	assert.Nil(t, stats[1].PointBiserial, "an item everyone got right has no correlation")
+}
+
+func TestBand(t *testing.T) {
+	assert.Equal(t, BandEasy, Band(-1.5))
+	assert.Equal(t, BandMedium, Band(0.3))
+	assert.Equal(t, BandHard, Band(1.2))
+}
+
+func TestFlags(t *testing.T) {
+	neg, pos := -0.2, 0.3
+	assert.Equal(t, []string{FlagTooEasy}, Flags(0.95, &pos, nil))
+	assert.Equal(t, []string{FlagTooHard, FlagNegativeDiscrimination}, Flags(0.1, &pos, &neg))
+	assert.Empty(t, Flags(0.6, nil, nil))
+}
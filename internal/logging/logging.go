@@ -0,0 +1,33 @@
+// Package logging writes the server's logs as JSON through log/slog. Each
+// request gets an id, taken from the X-Request-ID header or generated, that
+// is echoed in the response and attached to every line logged for it.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+// New returns a JSON logger writing records at level and above.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+type loggerKey struct{}
+
+// WithLogger returns ctx carrying l, for FromContext.
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger of the request ctx belongs to, which tags
+// its lines with the request id, or the default logger outside requests.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+			return l
+		}
+	}
+	return slog.Default()
+}
@@ -0,0 +1,130 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request id in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+const requestIDKey = "logging.requestID"
+
+// CodeInternal is the error code clients get for every server-side failure;
+// the details only go to the log.
+const CodeInternal = "internal_error"
+
+// RequestID assigns the request its id: the caller's X-Request-ID when it is
+// a sensible one, so ids can follow a request across services, or a new one.
+// The id is sent back in the response header and tags the request's logger.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+
+		logger := slog.Default().With("request_id", id)
+		c.Request = c.Request.WithContext(WithLogger(c.Request.Context(), logger))
+		c.Next()
+	}
+}
+
+// GetRequestID returns the id RequestID gave the request, if it ran.
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// validRequestID accepts up to 128 printable ASCII characters, so a client
+// can't inject anything odd into the logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// AccessLog logs one line per request once it is handled, together with the
+// errors handlers attached with c.Error. Server errors log at error level
+// and client errors at warn.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+		FromContext(c.Request.Context()).LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// InternalError answers with a 500 that names no internals, only a stable
+// code and the request id to quote in a report, and hands err to AccessLog.
+func InternalError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.AbortWithStatusJSON(http.StatusInternalServerError, errorBody(c))
+}
+
+func errorBody(c *gin.Context) gin.H {
+	return gin.H{
+		"error":     "internal server error",
+		"code":      CodeInternal,
+		"requestId": GetRequestID(c),
+	}
+}
+
+// Recovery turns a panic into the same 500 as InternalError, logging the
+// stack instead of gin's plain text dump.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered interface{}) {
+		err, ok := recovered.(error)
+		if !ok {
+			err = fmt.Errorf("%v", recovered)
+		}
+		if errors.Is(err, http.ErrAbortHandler) {
+			panic(err)
+		}
+		FromContext(c.Request.Context()).Error("panic", "error", err.Error(), "stack", string(debug.Stack()))
+		InternalError(c, fmt.Errorf("panic: %w", err))
+	})
+}
@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capture points the default logger at a buffer for the test.
+func capture(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(New(&buf, slog.LevelDebug))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func newRouter() *gin.Engine {
+	router := gin.New()
+	router.Use(RequestID(), AccessLog(), Recovery())
+	router.GET("/items/:id", func(c *gin.Context) {
+		FromContext(c.Request.Context()).Info("looking up item")
+		c.Status(http.StatusNoContent)
+	})
+	router.GET("/fail", func(c *gin.Context) {
+		InternalError(c, errors.New("Error 1146: Table 'edu.items' doesn't exist"))
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	return router
+}
+
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &entry), string(line))
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestRequestIDIsPropagated(t *testing.T) {
+	buf := capture(t)
+	req := httptest.NewRequest(http.MethodGet, "/items/7", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	w := httptest.NewRecorder()
+	newRouter().ServeHTTP(w, req)
+
+	assert.Equal(t, "abc-123", w.Header().Get(RequestIDHeader))
+	lines := logLines(t, buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "looking up item", lines[0]["msg"])
+	assert.Equal(t, "abc-123", lines[0]["request_id"])
+	assert.Equal(t, "request", lines[1]["msg"])
+	assert.Equal(t, "abc-123", lines[1]["request_id"])
+	assert.Equal(t, "/items/:id", lines[1]["route"])
+	assert.Equal(t, float64(http.StatusNoContent), lines[1]["status"])
+}
+
+func TestRequestIDIsGeneratedForBadHeaders(t *testing.T) {
+	capture(t)
+	req := httptest.NewRequest(http.MethodGet, "/items/7", nil)
+	req.Header.Set(RequestIDHeader, "bad id\nwith newline")
+	w := httptest.NewRecorder()
+	newRouter().ServeHTTP(w, req)
+
+	id := w.Header().Get(RequestIDHeader)
+	assert.Len(t, id, 32)
+	assert.True(t, validRequestID(id))
+}
+
+func TestInternalErrorHidesDetailsFromClients(t *testing.T) {
+	for _, path := range []string{"/fail", "/panic"} {
+		buf := capture(t)
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		require.Equal(t, http.StatusInternalServerError, w.Code, path)
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, CodeInternal, body["code"])
+		assert.Equal(t, w.Header().Get(RequestIDHeader), body["requestId"])
+		assert.NotContains(t, w.Body.String(), "1146")
+		assert.NotContains(t, w.Body.String(), "boom")
+
+		lines := logLines(t, buf)
+		last := lines[len(lines)-1]
+		assert.Equal(t, "ERROR", last["level"], path)
+		assert.NotEmpty(t, last["error"], path)
+	}
+}
@@ -0,0 +1,145 @@
+// Package mastery estimates how well a student knows each subtopic with
+// Bayesian Knowledge Tracing and picks what they should practice next.
+package mastery
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Model holds the BKT parameters: the chance a student knows a subtopic
+// before practising it, learns it from one question, answers wrongly while
+// knowing it (slip) and answers correctly without knowing it (guess).
+type Model struct {
+	PInit    float64
+	PTransit float64
+	PSlip    float64
+	PGuess   float64
+}
+
+// DefaultModel uses textbook BKT parameters, with a guess rate matching a
+// four-option multiple choice question.
+var DefaultModel = Model{PInit: 0.2, PTransit: 0.1, PSlip: 0.1, PGuess: 0.25}
+
+// Mastered is the estimate above which a subtopic counts as learned.
+const Mastered = 0.95
+
+// Update returns the estimate that a student knows a subtopic after
+// answering one of its questions, given the estimate p before the answer.
+func (m Model) Update(p float64, correct bool) float64 {
+	var posterior float64
+	if correct {
+		known := p * (1 - m.PSlip)
+		posterior = known / (known + (1-p)*m.PGuess)
+	} else {
+		known := p * m.PSlip
+		posterior = known / (known + (1-p)*(1-m.PGuess))
+	}
+	return posterior + (1-posterior)*m.PTransit
+}
+
+// Candidate is a question that can be served for practice. Questions without
+// a subtopic do not take part in mastery tracking.
+type Candidate struct {
+	QuestionID int
+	SubtopicID *int
+}
+
+// weakestPool is how many of the weakest subtopics the next question is drawn
+// from, so practice does not stall on a single subtopic.
+const weakestPool = 3
+
+// Picker chooses practice questions.
+type Picker struct {
+	Model Model
+	Rand  *rand.Rand
+}
+
+// Next picks the question to practice from candidates. known maps subtopic
+// ids to the student's current estimate; recent holds questions the student
+// has just seen, which are avoided unless nothing else is left.
+//
+// Subtopics are ranked by estimate and one of the weakest few that still
+// has unseen questions is drawn, weighted towards the weakest. Mastered
+// subtopics are only revisited once everything is mastered. Questions
+// without a subtopic are used when no subtopic question is available.
+func (p Picker) Next(candidates []Candidate, known map[int]float64, recent map[int]bool) (Candidate, bool) {
+	if len(candidates) == 0 {
+		return Candidate{}, false
+	}
+
+	fresh := make([]Candidate, 0, len(candidates))
+	for _, c := range candidates {
+		if !recent[c.QuestionID] {
+			fresh = append(fresh, c)
+		}
+	}
+	if len(fresh) == 0 {
+		fresh = candidates
+	}
+
+	bySubtopic := map[int][]Candidate{}
+	var untracked []Candidate
+	for _, c := range fresh {
+		if c.SubtopicID == nil {
+			untracked = append(untracked, c)
+			continue
+		}
+		bySubtopic[*c.SubtopicID] = append(bySubtopic[*c.SubtopicID], c)
+	}
+	if len(bySubtopic) == 0 {
+		return untracked[p.Rand.Intn(len(untracked))], true
+	}
+
+	estimate := func(subtopicID int) float64 {
+		if v, ok := known[subtopicID]; ok {
+			return v
+		}
+		return p.Model.PInit
+	}
+	subtopics := make([]int, 0, len(bySubtopic))
+	for id := range bySubtopic {
+		subtopics = append(subtopics, id)
+	}
+	sort.Slice(subtopics, func(i, j int) bool {
+		ei, ej := estimate(subtopics[i]), estimate(subtopics[j])
+		if ei != ej {
+			return ei < ej
+		}
+		return subtopics[i] < subtopics[j]
+	})
+
+	pool := make([]int, 0, weakestPool)
+	for _, id := range subtopics {
+		if estimate(id) < Mastered {
+			pool = append(pool, id)
+		}
+		if len(pool) == weakestPool {
+			break
+		}
+	}
+	if len(pool) == 0 {
+		if len(untracked) > 0 {
+			return untracked[p.Rand.Intn(len(untracked))], true
+		}
+		pool = subtopics[:min(weakestPool, len(subtopics))]
+	}
+
+	// weight by how far each subtopic is from being known
+	total := 0.0
+	for _, id := range pool {
+		total += 1 - estimate(id) + 0.01
+	}
+	r := p.Rand.Float64() * total
+	chosen := pool[len(pool)-1]
+	for _, id := range pool {
+		r -= 1 - estimate(id) + 0.01
+		if r < 0 {
+			chosen = id
+			break
+		}
+	}
+
+	options := bySubtopic[chosen]
+	return options[p.Rand.Intn(len(options))], true
+}
@@ -0,0 +1,82 @@
+package mastery
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(i int) *int { return &i }
+
+func TestUpdateMovesTowardsTheAnswers(t *testing.T) {
+	m := DefaultModel
+	p := m.PInit
+
+	afterCorrect := m.Update(p, true)
+	afterWrong := m.Update(p, false)
+	assert.Greater(t, afterCorrect, p)
+	assert.Less(t, afterWrong, afterCorrect)
+
+	for i := 0; i < 10; i++ {
+		p = m.Update(p, true)
+	}
+	assert.GreaterOrEqual(t, p, Mastered, "a run of correct answers reaches mastery")
+	assert.LessOrEqual(t, p, 1.0)
+}
+
+func TestNextPrefersWeakSubtopics(t *testing.T) {
+	candidates := []Candidate{
+		{QuestionID: 1, SubtopicID: intPtr(10)},
+		{QuestionID: 2, SubtopicID: intPtr(10)},
+		{QuestionID: 3, SubtopicID: intPtr(20)},
+		{QuestionID: 4, SubtopicID: intPtr(30)},
+	}
+	known := map[int]float64{10: 0.05, 20: 0.6, 30: 0.99}
+	picker := Picker{Model: DefaultModel, Rand: rand.New(rand.NewSource(1))}
+
+	picks := map[int]int{}
+	for i := 0; i < 500; i++ {
+		c, ok := picker.Next(candidates, known, nil)
+		require.True(t, ok)
+		picks[*c.SubtopicID]++
+	}
+	assert.Greater(t, picks[10], picks[20])
+	assert.Zero(t, picks[30], "mastered subtopics are skipped")
+}
+
+func TestNextAvoidsRecentQuestions(t *testing.T) {
+	candidates := []Candidate{
+		{QuestionID: 1, SubtopicID: intPtr(10)},
+		{QuestionID: 2, SubtopicID: intPtr(10)},
+		{QuestionID: 3, SubtopicID: intPtr(20)},
+	}
+	picker := Picker{Model: DefaultModel, Rand: rand.New(rand.NewSource(1))}
+
+	for i := 0; i < 50; i++ {
+		c, ok := picker.Next(candidates, map[int]float64{10: 0.1, 20: 0.9}, map[int]bool{1: true, 2: true})
+		require.True(t, ok)
+		assert.Equal(t, 3, c.QuestionID)
+	}
+
+	// once everything was seen recently, repeats are allowed
+	c, ok := picker.Next(candidates, nil, map[int]bool{1: true, 2: true, 3: true})
+	require.True(t, ok)
+	assert.NotZero(t, c.QuestionID)
+}
+
+func TestNextFallsBackToUntrackedQuestions(t *testing.T) {
+	picker := Picker{Model: DefaultModel, Rand: rand.New(rand.NewSource(1))}
+
+	c, ok := picker.Next([]Candidate{{QuestionID: 7}}, nil, nil)
+	require.True(t, ok)
+	assert.Equal(t, 7, c.QuestionID)
+
+	c, ok = picker.Next([]Candidate{{QuestionID: 7}, {QuestionID: 8, SubtopicID: intPtr(10)}}, map[int]float64{10: 0.99}, nil)
+	require.True(t, ok)
+	assert.Equal(t, 7, c.QuestionID, "mastered subtopics give way to untracked questions")
+
+	_, ok = picker.Next(nil, nil, nil)
+	assert.False(t, ok)
+}
@@ -0,0 +1,256 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/logging"
+)
+
+var dbQueryDuration = Default.NewHistogramVec("db_query_duration_seconds",
+	"Time for MySQL to answer queries, by the repository method that sent them.",
+	DefaultBuckets, "repository", "method", "outcome")
+
+// DBStats reports a connection pool's state at scrape time.
+type DBStats struct {
+	db *sql.DB
+}
+
+func NewDBStats(db *sql.DB) *DBStats {
+	return &DBStats{db: db}
+}
+
+func (s *DBStats) Collect(w *Writer) {
+	stats := s.db.Stats()
+	for _, m := range []struct {
+		name, help, typ string
+		value           float64
+	}{
+		{"db_max_open_connections", "Maximum number of open connections to the database.", "gauge", float64(stats.MaxOpenConnections)},
+		{"db_open_connections", "Established connections, in use and idle.", "gauge", float64(stats.OpenConnections)},
+		{"db_in_use_connections", "Connections currently in use.", "gauge", float64(stats.InUse)},
+		{"db_idle_connections", "Idle connections.", "gauge", float64(stats.Idle)},
+		{"db_wait_count_total", "Times a query waited for a free connection.", "counter", float64(stats.WaitCount)},
+		{"db_wait_duration_seconds_total", "Total time spent waiting for a free connection.", "counter", stats.WaitDuration.Seconds()},
+		{"db_max_idle_closed_total", "Connections closed because of the idle connection limit.", "counter", float64(stats.MaxIdleClosed)},
+		{"db_max_idle_time_closed_total", "Connections closed because they were idle too long.", "counter", float64(stats.MaxIdleTimeClosed)},
+		{"db_max_lifetime_closed_total", "Connections closed because they reached their maximum lifetime.", "counter", float64(stats.MaxLifetimeClosed)},
+	} {
+		w.Family(m.name, m.help, m.typ)
+		w.Sample(m.name, m.value)
+	}
+}
+
+// InstrumentConnector wraps a driver connector so every query sent through
+// it is timed. Each timing is labelled with the outermost method in
+// internal/repository on the calling goroutine's stack, e.g.
+// MenuConfigRepository.DeleteLesson, so queries are attributed to the
+// repository method that sent them without instrumenting each one. Queries
+// sent from elsewhere, such as migrations, are not timed.
+//
+// Repository queries taking at least slow, when it is positive, are also
+// logged with their SQL on the request's logger. A timing ends when MySQL
+// answers, before the rows are read.
+func InstrumentConnector(c driver.Connector, slow time.Duration) driver.Connector {
+	return &connector{Connector: c, slow: slow}
+}
+
+type connector struct {
+	driver.Connector
+	slow time.Duration
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConn{conn: conn, slow: c.slow}, nil
+}
+
+// instrumentedConn forwards to the driver's connection, which is expected to
+// implement the context-aware interfaces, as go-sql-driver/mysql's does.
+type instrumentedConn struct {
+	conn driver.Conn
+	slow time.Duration
+}
+
+func (c *instrumentedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedStmt{stmt: stmt, query: query, slow: c.slow}, nil
+}
+
+func (c *instrumentedConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *instrumentedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+// QueryContext and ExecContext only run queries without arguments, unless
+// the driver interpolates them; for the others it returns driver.ErrSkip and
+// database/sql prepares a statement instead, timed by instrumentedStmt.
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := c.conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+	observeQuery(ctx, start, query, c.slow, err)
+	return rows, err
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	res, err := c.conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+	observeQuery(ctx, start, query, c.slow, err)
+	return res, err
+}
+
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	return c.conn.(driver.Pinger).Ping(ctx)
+}
+
+func (c *instrumentedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (c *instrumentedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) IsValid() bool {
+	if validator, ok := c.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+type instrumentedStmt struct {
+	stmt  driver.Stmt
+	query string
+	slow  time.Duration
+}
+
+func (s *instrumentedStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *instrumentedStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *instrumentedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("metrics: Stmt.Exec is not supported, use ExecContext")
+}
+
+func (s *instrumentedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("metrics: Stmt.Query is not supported, use QueryContext")
+}
+
+func (s *instrumentedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	res, err := s.stmt.(driver.StmtExecContext).ExecContext(ctx, args)
+	observeQuery(ctx, start, s.query, s.slow, err)
+	return res, err
+}
+
+func (s *instrumentedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := s.stmt.(driver.StmtQueryContext).QueryContext(ctx, args)
+	observeQuery(ctx, start, s.query, s.slow, err)
+	return rows, err
+}
+
+func (s *instrumentedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func observeQuery(ctx context.Context, start time.Time, query string, slow time.Duration, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	elapsed := time.Since(start)
+	repo, method := repositoryCaller()
+	if repo == "" {
+		return
+	}
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	dbQueryDuration.Observe(elapsed.Seconds(), repo, method, outcome)
+
+	if slow > 0 && elapsed >= slow {
+		logging.FromContext(ctx).Warn("slow query",
+			"repository", repo,
+			"method", method,
+			"duration_ms", float64(elapsed.Microseconds())/1000,
+			"query", strings.Join(strings.Fields(query), " "))
+	}
+}
+
+// closure matches the names Go gives function literals, as in
+// requireParent.func1, which would otherwise pass for a method.
+var closure = regexp.MustCompile(`^func\d+$`)
+
+const repositoryPkg = "github.com/tharindulakmal/sl-edu-service/internal/repository."
+
+// repositoryCaller finds the outermost repository method on the stack.
+func repositoryCaller() (repo, method string) {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if r, m, ok := splitMethod(frame.Function); ok {
+			repo, method = r, m
+		}
+		if !more {
+			return repo, method
+		}
+	}
+}
+
+// splitMethod splits a function name such as
+// ".../internal/repository.(*MenuConfigRepository).ListGrades.func1" into
+// the receiver type and method. Plain functions don't count.
+func splitMethod(function string) (repo, method string, ok bool) {
+	name, found := strings.CutPrefix(function, repositoryPkg)
+	if !found {
+		return "", "", false
+	}
+	receiver, rest, found := strings.Cut(name, ".")
+	if !found {
+		return "", "", false
+	}
+	receiver = strings.TrimSuffix(strings.TrimPrefix(receiver, "(*"), ")")
+	method, _, _ = strings.Cut(rest, ".")
+	if receiver == "" || method == "" || closure.MatchString(method) {
+		return "", "", false
+	}
+	return receiver, method, true
+}
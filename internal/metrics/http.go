@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	httpRequests = Default.NewCounterVec("http_requests_total",
+		"HTTP requests handled, by route template and status.",
+		"method", "route", "status")
+	httpDuration = Default.NewHistogramVec("http_request_duration_seconds",
+		"Time to handle HTTP requests, by route template and status.",
+		DefaultBuckets, "method", "route", "status")
+)
+
+// Middleware counts and times every request. Requests are labelled with the
+// route template, such as /api/v1/mcq/questions/:id, so ids don't each get a
+// series; requests that match no route share the label "unmatched".
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		httpRequests.Inc(c.Request.Method, route, status)
+		httpDuration.Observe(time.Since(start).Seconds(), c.Request.Method, route, status)
+	}
+}
@@ -0,0 +1,231 @@
+// Package metrics keeps counters and histograms in memory and serves them in
+// the Prometheus text format on /metrics. It covers the handful of metric
+// kinds this service needs, without pulling in the Prometheus client.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultBuckets are the Prometheus client's default latency buckets, in
+// seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Default is the registry the server exposes.
+var Default = NewRegistry()
+
+// Collector writes one or more metric families when /metrics is scraped.
+type Collector interface {
+	Collect(w *Writer)
+}
+
+// Registry is a set of collectors, written out in registration order.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// NewCounterVec registers a counter with the given label names.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{name: name, help: help, labels: labels, values: make(map[string]*counter)}
+	r.Register(c)
+	return c
+}
+
+// NewHistogramVec registers a histogram with the given upper bounds, which
+// must be sorted, and label names.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{name: name, help: help, buckets: buckets, labels: labels, values: make(map[string]*histogram)}
+	r.Register(h)
+	return h
+}
+
+// Handler serves every registered metric.
+func (r *Registry) Handler(c *gin.Context) {
+	r.mu.Lock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+	w := &Writer{w: bufio.NewWriter(c.Writer)}
+	for _, collector := range collectors {
+		collector.Collect(w)
+	}
+	_ = w.w.Flush()
+}
+
+// Writer writes the text exposition format.
+type Writer struct {
+	w *bufio.Writer
+}
+
+// Family starts a metric family; typ is counter, gauge or histogram.
+func (w *Writer) Family(name, help, typ string) {
+	fmt.Fprintf(w.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// Sample writes one value. labels alternates names and values.
+func (w *Writer) Sample(name string, value float64, labels ...string) {
+	w.w.WriteString(name)
+	if len(labels) > 0 {
+		w.w.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.w.WriteByte(',')
+			}
+			fmt.Fprintf(w.w, `%s="%s"`, labels[i], labelEscaper.Replace(labels[i+1]))
+		}
+		w.w.WriteByte('}')
+	}
+	w.w.WriteByte(' ')
+	w.w.WriteString(formatFloat(value))
+	w.w.WriteByte('\n')
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// labelKey joins label values into a map key.
+func labelKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+// pairs zips label names with values for Writer.Sample.
+func pairs(names, values []string, extra ...string) []string {
+	out := make([]string, 0, 2*len(names)+len(extra))
+	for i, name := range names {
+		out = append(out, name, values[i])
+	}
+	return append(out, extra...)
+}
+
+type counter struct {
+	values []string
+	value  float64
+}
+
+// CounterVec is a counter per combination of label values.
+type CounterVec struct {
+	name, help string
+	labels     []string
+
+	mu     sync.Mutex
+	values map[string]*counter
+}
+
+// Add adds delta to the counter for the label values, given in the order the
+// label names were registered.
+func (c *CounterVec) Add(delta float64, values ...string) {
+	key := labelKey(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.values[key]
+	if !ok {
+		v = &counter{values: values}
+		c.values[key] = v
+	}
+	v.value += delta
+}
+
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *CounterVec) Collect(w *Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w.Family(c.name, c.help, "counter")
+	for _, key := range sortedKeys(c.values) {
+		v := c.values[key]
+		w.Sample(c.name, v.value, pairs(c.labels, v.values)...)
+	}
+}
+
+type histogram struct {
+	values []string
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// HistogramVec is a histogram per combination of label values.
+type HistogramVec struct {
+	name, help string
+	buckets    []float64
+	labels     []string
+
+	mu     sync.Mutex
+	values map[string]*histogram
+}
+
+// Observe records v for the label values.
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	key := labelKey(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hist, ok := h.values[key]
+	if !ok {
+		hist = &histogram{values: values, counts: make([]uint64, len(h.buckets))}
+		h.values[key] = hist
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		hist.counts[i]++
+	}
+	hist.count++
+	hist.sum += v
+}
+
+func (h *HistogramVec) Collect(w *Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	w.Family(h.name, h.help, "histogram")
+	for _, key := range sortedKeys(h.values) {
+		hist := h.values[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += hist.counts[i]
+			w.Sample(h.name+"_bucket", float64(cumulative), pairs(h.labels, hist.values, "le", formatFloat(bound))...)
+		}
+		w.Sample(h.name+"_bucket", float64(hist.count), pairs(h.labels, hist.values, "le", "+Inf")...)
+		w.Sample(h.name+"_sum", hist.sum, pairs(h.labels, hist.values)...)
+		w.Sample(h.name+"_count", float64(hist.count), pairs(h.labels, hist.values)...)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
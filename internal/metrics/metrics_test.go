@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, reg *Registry) string {
+	t.Helper()
+	router := gin.New()
+	router.GET("/metrics", reg.Handler)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "version=0.0.4")
+	return w.Body.String()
+}
+
+func TestExposition(t *testing.T) {
+	reg := NewRegistry()
+	requests := reg.NewCounterVec("requests_total", "Requests.", "path")
+	latency := reg.NewHistogramVec("latency_seconds", "Latency.", []float64{0.1, 1}, "path")
+
+	requests.Inc(`/a"b\c`)
+	requests.Add(2, "/x")
+	latency.Observe(0.05, "/x")
+	latency.Observe(0.5, "/x")
+	latency.Observe(5, "/x")
+
+	assert.Equal(t, `# HELP requests_total Requests.
+# TYPE requests_total counter
+requests_total{path="/a\"b\\c"} 1
+requests_total{path="/x"} 2
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{path="/x",le="0.1"} 1
+latency_seconds_bucket{path="/x",le="1"} 2
+latency_seconds_bucket{path="/x",le="+Inf"} 3
+latency_seconds_sum{path="/x"} 5.55
+latency_seconds_count{path="/x"} 3
+`, scrape(t, reg))
+}
+
+func TestMiddlewareLabelsByRouteTemplate(t *testing.T) {
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/mcq/questions/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	for _, path := range []string{"/mcq/questions/1", "/mcq/questions/2", "/nowhere"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t, Default)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/mcq/questions/:id",status="204"} 2`)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/mcq/questions/:id",status="204"} 2`)
+}
+
+func TestSplitMethod(t *testing.T) {
+	for _, tc := range []struct {
+		function, repo, method string
+	}{
+		{repositoryPkg + "(*MenuConfigRepository).ListGrades", "MenuConfigRepository", "ListGrades"},
+		{repositoryPkg + "(*questionRepository).Create.func1", "questionRepository", "Create"},
+		{repositoryPkg + "(*questionRepository).Create.func1.2", "questionRepository", "Create"},
+		{repositoryPkg + "requireParent", "", ""},
+		{repositoryPkg + "requireParent.func1", "", ""},
+		{"github.com/tharindulakmal/sl-edu-service/internal/migrate.(*Runner).Up", "", ""},
+	} {
+		repo, method, ok := splitMethod(tc.function)
+		assert.Equal(t, tc.repo != "", ok, tc.function)
+		assert.Equal(t, tc.repo, repo, tc.function)
+		assert.Equal(t, tc.method, method, tc.function)
+	}
+}
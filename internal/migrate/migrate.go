@@ -0,0 +1,405 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// lockName is the MySQL advisory lock held while migrating, so instances
+// starting together apply each migration once.
+const lockName = "sl-edu-service.schema_migrations"
+
+// ErrLocked is returned when another process held the migration lock for
+// longer than the runner's LockTimeout.
+var ErrLocked = errors.New("migrate: timed out waiting for the migration lock")
+
+// ChecksumError is returned when migrations that were already applied no
+// longer match their files. Applied migrations must not be edited; add a new
+// one instead.
+type ChecksumError struct {
+	Versions []int64
+}
+
+func (e *ChecksumError) Error() string {
+	versions := make([]string, len(e.Versions))
+	for i, v := range e.Versions {
+		versions[i] = fmt.Sprint(v)
+	}
+	return "migrate: applied migrations were edited since they ran: " + strings.Join(versions, ", ")
+}
+
+// DirtyError is returned when a migration failed halfway. MySQL can't roll
+// DDL back, so the schema has to be repaired by hand and the version's row
+// in schema_migrations deleted (after undoing it) or its dirty flag cleared
+// (after finishing it).
+type DirtyError struct {
+	Version int64
+}
+
+func (e *DirtyError) Error() string {
+	return fmt.Sprintf("migrate: migration %d failed halfway and needs fixing by hand", e.Version)
+}
+
+// Status is one migration as the database sees it. A version that was
+// applied but has no file, e.g. from a newer release, has an empty Name.
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt string
+	Dirty     bool
+	Modified  bool
+}
+
+// applied is a row of schema_migrations.
+type applied struct {
+	version   int64
+	checksum  string
+	dirty     bool
+	appliedAt string
+}
+
+// Runner applies migrations to one database.
+type Runner struct {
+	db         *sql.DB
+	migrations []Migration
+
+	// LockTimeout bounds the wait for another instance's migrations.
+	LockTimeout time.Duration
+	// Logf, when set, is told about each migration as it runs.
+	Logf func(format string, args ...interface{})
+}
+
+func NewRunner(db *sql.DB, migrations []Migration) *Runner {
+	return &Runner{db: db, migrations: migrations, LockTimeout: 5 * time.Minute}
+}
+
+// Up applies every pending migration and returns the ones it ran.
+func (r *Runner) Up(ctx context.Context) ([]Migration, error) {
+	var ran []Migration
+	err := r.locked(ctx, func(conn *sql.Conn, done map[int64]applied) error {
+		for _, m := range r.migrations {
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+			if err := r.apply(ctx, conn, m); err != nil {
+				return err
+			}
+			ran = append(ran, m)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// Down rolls back the n most recently applied migrations and returns them.
+func (r *Runner) Down(ctx context.Context, n int) ([]Migration, error) {
+	var ran []Migration
+	err := r.locked(ctx, func(conn *sql.Conn, done map[int64]applied) error {
+		for i := len(r.migrations) - 1; i >= 0 && len(ran) < n; i-- {
+			m := r.migrations[i]
+			if _, ok := done[m.Version]; !ok {
+				continue
+			}
+			if err := r.revert(ctx, conn, m); err != nil {
+				return err
+			}
+			ran = append(ran, m)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// To migrates up or down until exactly the migrations up to version are
+// applied; 0 rolls everything back.
+func (r *Runner) To(ctx context.Context, version int64) ([]Migration, error) {
+	known := version == 0
+	for _, m := range r.migrations {
+		known = known || m.Version == version
+	}
+	if !known {
+		return nil, fmt.Errorf("migrate: no migration %d", version)
+	}
+
+	var ran []Migration
+	err := r.locked(ctx, func(conn *sql.Conn, done map[int64]applied) error {
+		for i := len(r.migrations) - 1; i >= 0; i-- {
+			m := r.migrations[i]
+			if _, ok := done[m.Version]; ok && m.Version > version {
+				if err := r.revert(ctx, conn, m); err != nil {
+					return err
+				}
+				ran = append(ran, m)
+			}
+		}
+		for _, m := range r.migrations {
+			if _, ok := done[m.Version]; !ok && m.Version <= version {
+				if err := r.apply(ctx, conn, m); err != nil {
+					return err
+				}
+				ran = append(ran, m)
+			}
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// Status lists every known and applied migration by version.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := loadApplied(ctx, conn)
+		statuses = status(r.migrations, done)
+		return err
+	})
+	return statuses, err
+}
+
+// Verify checks that no applied migration was edited or left dirty and
+// returns how many are pending, without applying any.
+func (r *Runner) Verify(ctx context.Context) (int, error) {
+	statuses, err := r.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, s := range statuses {
+		if !s.Applied {
+			pending++
+		}
+	}
+	return pending, check(statuses)
+}
+
+// Current returns the newest applied version and how many migrations are
+// pending. Unlike Verify it doesn't wait for the migration lock, so it suits
+// frequent polling; a migration running meanwhile shows up as dirty.
+func (r *Runner) Current(ctx context.Context) (version int64, pending int, err error) {
+	done, err := loadApplied(ctx, r.db)
+	if err != nil {
+		return 0, 0, err
+	}
+	for v := range done {
+		version = max(version, v)
+	}
+	statuses := status(r.migrations, done)
+	for _, s := range statuses {
+		if !s.Applied {
+			pending++
+		}
+	}
+	return version, pending, check(statuses)
+}
+
+// locked runs fn under the migration lock, after making sure the applied
+// migrations are intact.
+func (r *Runner) locked(ctx context.Context, fn func(conn *sql.Conn, done map[int64]applied) error) error {
+	return r.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := check(status(r.migrations, done)); err != nil {
+			return err
+		}
+		return fn(conn, done)
+	})
+}
+
+// withLock runs fn on a connection holding the migration lock, once
+// schema_migrations exists.
+func (r *Runner) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var got sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, int(r.LockTimeout.Seconds())).Scan(&got); err != nil {
+		return err
+	}
+	if !got.Valid || got.Int64 != 1 {
+		return ErrLocked
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", lockName)
+	}()
+
+	if err := ensureTable(ctx, conn, r.migrations); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func (r *Runner) apply(ctx context.Context, conn *sql.Conn, m Migration) error {
+	r.logf("migrate: applying %d_%s", m.Version, m.Name)
+	if _, err := conn.ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, name, checksum, dirty) VALUES (?, ?, ?, TRUE)",
+		m.Version, m.Name, m.Checksum); err != nil {
+		return err
+	}
+	if err := run(ctx, conn, m.Up); err != nil {
+		return fmt.Errorf("migrate: %d_%s up: %w", m.Version, m.Name, err)
+	}
+	_, err := conn.ExecContext(ctx, "UPDATE schema_migrations SET dirty = FALSE WHERE version = ?", m.Version)
+	return err
+}
+
+func (r *Runner) revert(ctx context.Context, conn *sql.Conn, m Migration) error {
+	if strings.TrimSpace(m.Down) == "" {
+		return fmt.Errorf("migrate: %d_%s has no down migration", m.Version, m.Name)
+	}
+	r.logf("migrate: reverting %d_%s", m.Version, m.Name)
+	if _, err := conn.ExecContext(ctx, "UPDATE schema_migrations SET dirty = TRUE WHERE version = ?", m.Version); err != nil {
+		return err
+	}
+	if err := run(ctx, conn, m.Down); err != nil {
+		return fmt.Errorf("migrate: %d_%s down: %w", m.Version, m.Name, err)
+	}
+	_, err := conn.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = ?", m.Version)
+	return err
+}
+
+func (r *Runner) logf(format string, args ...interface{}) {
+	if r.Logf != nil {
+		r.Logf(format, args...)
+	}
+}
+
+func run(ctx context.Context, conn *sql.Conn, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureTable creates schema_migrations. A database migrated so far with the
+// golang-migrate CLI has a one-row schema_migrations of its own; that table
+// is kept as schema_migrations_legacy and the versions it covers are
+// recorded with the checksums of the current files.
+func ensureTable(ctx context.Context, conn *sql.Conn, migrations []Migration) error {
+	var columns, checksums int
+	if err := conn.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(column_name = 'checksum'), 0)
+		FROM information_schema.columns
+		WHERE table_schema = DATABASE() AND table_name = 'schema_migrations'`).Scan(&columns, &checksums); err != nil {
+		return err
+	}
+	if columns > 0 && checksums > 0 {
+		return nil
+	}
+
+	var legacyVersion int64 = -1
+	if columns > 0 {
+		var dirty bool
+		err := conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&legacyVersion, &dirty)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			legacyVersion = 0
+		case err != nil:
+			return err
+		case dirty:
+			return &DirtyError{Version: legacyVersion}
+		}
+		if _, err := conn.ExecContext(ctx, "RENAME TABLE schema_migrations TO schema_migrations_legacy"); err != nil {
+			return err
+		}
+	}
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum CHAR(64) NOT NULL,
+			dirty BOOLEAN NOT NULL DEFAULT FALSE,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`); err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if m.Version > legacyVersion {
+			break
+		}
+		if _, err := conn.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)",
+			m.Version, m.Name, m.Checksum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryer is a *sql.DB or a *sql.Conn.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func loadApplied(ctx context.Context, q queryer) (map[int64]applied, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT version, checksum, dirty, DATE_FORMAT(applied_at, '%Y-%m-%dT%H:%i:%sZ')
+		FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	done := make(map[int64]applied)
+	for rows.Next() {
+		var a applied
+		if err := rows.Scan(&a.version, &a.checksum, &a.dirty, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		done[a.version] = a
+	}
+	return done, rows.Err()
+}
+
+// status merges the known migrations with the applied ones, by version.
+func status(migrations []Migration, done map[int64]applied) []Status {
+	statuses := make([]Status, 0, len(migrations))
+	known := make(map[int64]bool, len(migrations))
+	for _, m := range migrations {
+		known[m.Version] = true
+		s := Status{Version: m.Version, Name: m.Name}
+		if a, ok := done[m.Version]; ok {
+			s.Applied, s.AppliedAt, s.Dirty = true, a.appliedAt, a.dirty
+			s.Modified = a.checksum != m.Checksum
+		}
+		statuses = append(statuses, s)
+	}
+	for _, a := range done {
+		if !known[a.version] {
+			statuses = append(statuses, Status{Version: a.version, Applied: true, AppliedAt: a.appliedAt, Dirty: a.dirty})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses
+}
+
+// check reports a dirty migration first, since it is what needs fixing
+// before anything else can run, and then any edited ones.
+func check(statuses []Status) error {
+	var modified []int64
+	for _, s := range statuses {
+		if s.Dirty {
+			return &DirtyError{Version: s.Version}
+		}
+		if s.Modified {
+			modified = append(modified, s.Version)
+		}
+	}
+	if len(modified) > 0 {
+		return &ChecksumError{Versions: modified}
+	}
+	return nil
+}
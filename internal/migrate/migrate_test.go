@@ -0,0 +1,46 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusMergesKnownAndAppliedVersions(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "a", Checksum: "aaa"},
+		{Version: 2, Name: "b", Checksum: "bbb"},
+		{Version: 3, Name: "c", Checksum: "ccc"},
+	}
+	done := map[int64]applied{
+		1: {version: 1, checksum: "aaa", appliedAt: "2026-01-01T00:00:00Z"},
+		2: {version: 2, checksum: "edited"},
+		9: {version: 9, checksum: "zzz"},
+	}
+
+	statuses := status(migrations, done)
+	assert.Equal(t, []Status{
+		{Version: 1, Name: "a", Applied: true, AppliedAt: "2026-01-01T00:00:00Z"},
+		{Version: 2, Name: "b", Applied: true, Modified: true},
+		{Version: 3, Name: "c"},
+		{Version: 9, Applied: true},
+	}, statuses)
+
+	err := check(statuses)
+	var checksum *ChecksumError
+	if assert.ErrorAs(t, err, &checksum) {
+		assert.Equal(t, []int64{2}, checksum.Versions)
+	}
+}
+
+func TestCheckReportsDirtyBeforeEdits(t *testing.T) {
+	err := check([]Status{
+		{Version: 1, Applied: true, Modified: true},
+		{Version: 2, Applied: true, Dirty: true},
+	})
+	var dirty *DirtyError
+	if assert.ErrorAs(t, err, &dirty) {
+		assert.Equal(t, int64(2), dirty.Version)
+	}
+	assert.NoError(t, check([]Status{{Version: 1, Applied: true}, {Version: 2}}))
+}
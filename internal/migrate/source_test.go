@@ -0,0 +1,58 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tharindulakmal/sl-edu-service/db/migrations"
+)
+
+func TestLoadOrdersVersionsAcrossGaps(t *testing.T) {
+	all, err := Load(fstest.MapFS{
+		"000012_b.up.sql":   {Data: []byte("CREATE TABLE b (id INT);")},
+		"000012_b.down.sql": {Data: []byte("DROP TABLE b;")},
+		"000001_a.up.sql":   {Data: []byte("CREATE TABLE a (id INT);")},
+		"embed.go":          {Data: []byte("package migrations")},
+	})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, int64(1), all[0].Version)
+	assert.Equal(t, "a", all[0].Name)
+	assert.Empty(t, all[0].Down)
+	assert.Equal(t, int64(12), all[1].Version)
+	assert.Equal(t, "DROP TABLE b;", all[1].Down)
+	assert.Len(t, all[1].Checksum, 64)
+	assert.NotEqual(t, all[0].Checksum, all[1].Checksum)
+}
+
+func TestLoadRejectsMigrationWithoutUp(t *testing.T) {
+	_, err := Load(fstest.MapFS{"000003_c.down.sql": {Data: []byte("DROP TABLE c;")}})
+	assert.EqualError(t, err, "migration 3_c has no up file")
+}
+
+func TestEmbeddedMigrationsLoadAndSplit(t *testing.T) {
+	all, err := Load(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+	for _, m := range all {
+		assert.NotEmpty(t, m.Down, "%d_%s has no down file", m.Version, m.Name)
+		assert.NotEmpty(t, splitStatements(m.Up), "%d_%s has no statements", m.Version, m.Name)
+	}
+}
+
+func TestSplitStatementsIgnoresSemicolonsInStringsAndComments(t *testing.T) {
+	stmts := splitStatements(`
+-- topic_id 2; check your ids
+INSERT INTO notes (body) VALUES ('a; b'), ("it\'s; fine"), ('don''t;');
+# another; comment
+/* block; comment */ UPDATE t SET ` + "`x;y`" + ` = 1;
+SELECT 1`)
+	assert.Equal(t, []string{
+		`INSERT INTO notes (body) VALUES ('a; b'), ("it\'s; fine"), ('don''t;')`,
+		"UPDATE t SET `x;y` = 1",
+		"SELECT 1",
+	}, stmts)
+}
@@ -0,0 +1,44 @@
+package models
+
+import "encoding/json"
+
+// Audit log actions.
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+	// AuditRestore brings a row back out of the trash.
+	AuditRestore = "restore"
+)
+
+// Audited entities besides the translatable ones.
+const (
+	EntityUser          = "user"
+	EntityExamBlueprint = "exam_blueprint"
+)
+
+// AuditEntry records one change to curriculum or question data. Before and
+// After hold the whole row as JSON; Before is empty for creates and After
+// for deletes.
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	ActorID    *int            `json:"actorId"`
+	ActorRole  *string         `json:"actorRole,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityID   int64           `json:"entityId"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreatedAt  string          `json:"createdAt"`
+}
+
+// AuditFilter narrows the audit log. From and To are inclusive dates in
+// the form 2025-01-31.
+type AuditFilter struct {
+	ActorID    *int
+	Action     string
+	EntityType string
+	EntityID   *int64
+	From       string
+	To         string
+}
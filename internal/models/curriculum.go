@@ -0,0 +1,18 @@
+package models
+
+// CurriculumCounts is the number of questions and smart notes filed under a
+// curriculum node, including the nodes below it.
+type CurriculumCounts struct {
+	Questions  int `json:"questions"`
+	SmartNotes int `json:"smartNotes"`
+}
+
+// CurriculumNode is one level of the grade → subject → lesson → topic →
+// subtopic tree. Type is one of the Entity* constants.
+type CurriculumNode struct {
+	Type     string            `json:"type"`
+	ID       int64             `json:"id"`
+	Name     string            `json:"name"`
+	Counts   *CurriculumCounts `json:"counts,omitempty"`
+	Children []CurriculumNode  `json:"children"`
+}
@@ -0,0 +1,25 @@
+package models
+
+// EditorialEvent is an entry in the review history of a question or smart
+// note: a status change, a reviewer comment, or both.
+type EditorialEvent struct {
+	ID          int     `json:"id"`
+	ContentType string  `json:"contentType"`
+	ContentID   int     `json:"contentId"`
+	FromStatus  *string `json:"fromStatus,omitempty"`
+	ToStatus    *string `json:"toStatus,omitempty"`
+	Comment     *string `json:"comment,omitempty"`
+	UserID      *int    `json:"userId"`
+	CreatedAt   string  `json:"createdAt"`
+}
+
+// StatusChangeRequest moves content to another status, optionally with a
+// note for the author.
+type StatusChangeRequest struct {
+	Status  string  `json:"status"`
+	Comment *string `json:"comment"`
+}
+
+type EditorialCommentRequest struct {
+	Comment string `json:"comment"`
+}
@@ -0,0 +1,46 @@
+package models
+
+const (
+	LatePolicyMark   = "mark"
+	LatePolicyReject = "reject"
+)
+
+// ExamBlueprint describes a mock exam: how many questions to draw from which
+// part of the syllabus, and how long students get to answer them.
+type ExamBlueprint struct {
+	ID              int           `json:"id"`
+	Name            string        `json:"name"`
+	GradeID         *int          `json:"gradeId,omitempty"`
+	TotalQuestions  int           `json:"totalQuestions"`
+	DurationMinutes int           `json:"durationMinutes"`
+	LatePolicy      string        `json:"latePolicy"`
+	Sections        []ExamSection `json:"sections"`
+	CreatedBy       *int          `json:"createdBy,omitempty"`
+	CreatedAt       string        `json:"createdAt"`
+}
+
+// ExamSection is one quota of a blueprint. Its questions come from the most
+// specific of LessonID, TopicID and SubtopicID that is set, and only from the
+// calibrated questions of one band when Difficulty is easy, medium or hard,
+// so several sections over the same lesson give a mix of difficulties. The
+// quota is either a fixed Count or a Percent of the blueprint's
+// TotalQuestions.
+type ExamSection struct {
+	Name       string `json:"name"`
+	LessonID   *int   `json:"lessonId,omitempty"`
+	TopicID    *int   `json:"topicId,omitempty"`
+	SubtopicID *int   `json:"subtopicId,omitempty"`
+	Difficulty string `json:"difficulty,omitempty"`
+	Count      *int   `json:"count,omitempty"`
+	Percent    *int   `json:"percent,omitempty"`
+}
+
+// GeneratedExam is a blueprint drawn with a given seed. Drawing the same
+// blueprint with the same seed from the same question bank gives the same
+// questions in the same order.
+type GeneratedExam struct {
+	BlueprintID     int        `json:"blueprintId"`
+	Seed            int64      `json:"seed"`
+	DurationMinutes int        `json:"durationMinutes"`
+	Questions       []Question `json:"questions"`
+}
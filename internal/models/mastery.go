@@ -0,0 +1,47 @@
+package models
+
+// SubtopicMastery is a student's estimated knowledge of one subtopic.
+type SubtopicMastery struct {
+	SubtopicID   int     `json:"subtopicId" db:"subtopic_id"`
+	SubtopicName string  `json:"subtopicName,omitempty" db:"subtopic_name"`
+	PKnown       float64 `json:"pKnown" db:"p_known"`
+	Attempts     int     `json:"attempts" db:"attempts"`
+	Correct      int     `json:"correct" db:"correct"`
+	Mastered     bool    `json:"mastered"`
+}
+
+// GradedAnswer is one answer a student gave, already marked.
+type GradedAnswer struct {
+	QuestionID int
+	Correct    bool
+}
+
+// PracticeQuestion is the next question served in practice mode. The answer
+// key is only revealed once it has been answered.
+type PracticeQuestion struct {
+	QuestionID  int         `json:"questionId"`
+	LessonID    int         `json:"lessonId"`
+	TopicID     *int        `json:"topicId,omitempty"`
+	SubtopicID  *int        `json:"subtopicId,omitempty"`
+	Type        string      `json:"type"`
+	Question    string      `json:"question"`
+	QuestionImg *string     `json:"questionImgUrl,omitempty"`
+	Prompts     StringArray `json:"prompts,omitempty"`
+	Options     StringArray `json:"options"`
+}
+
+type PracticeAnswerRequest struct {
+	QuestionID int         `json:"questionId" binding:"required"`
+	Answer     AnswerValue `json:"answer"`
+}
+
+// PracticeAnswerResult marks a practice answer and reports the updated
+// mastery of the question's subtopic, when it has one.
+type PracticeAnswerResult struct {
+	QuestionID    int              `json:"questionId"`
+	IsCorrect     bool             `json:"isCorrect"`
+	CorrectAnswer string           `json:"correctAnswer"`
+	Theory        *string          `json:"theory,omitempty"`
+	Solution      *string          `json:"solution,omitempty"`
+	Mastery       *SubtopicMastery `json:"mastery,omitempty"`
+}
@@ -240,3 +240,84 @@ type TutorialUpsert struct {
 	Name string  `json:"name"`
 	URL  *string `json:"url"`
 }
+
+const (
+	ExamTypeScholarship = "grade5_scholarship"
+	ExamTypeOL          = "ol"
+	ExamTypeAL          = "al"
+)
+
+// PastPaper is one paper of a national exam sitting, such as the 2019 O/L
+// Mathematics paper I. Year is the exam year the paper's YearID points at.
+type PastPaper struct {
+	ID              int64  `json:"id"`
+	ExamType        string `json:"examType"`
+	YearID          int64  `json:"yearId"`
+	Year            int    `json:"year"`
+	SubjectID       *int64 `json:"subjectId"`
+	PaperNumber     int    `json:"paperNumber"`
+	Name            string `json:"name"`
+	DurationMinutes *int   `json:"durationMinutes"`
+	QuestionCount   int    `json:"questionCount"`
+	CreatedAt       string `json:"createdAt"`
+}
+
+type PastPaperUpsert struct {
+	ExamType        string `json:"examType"`
+	YearID          int64  `json:"yearId"`
+	SubjectID       *int64 `json:"subjectId"`
+	PaperNumber     int    `json:"paperNumber"`
+	Name            string `json:"name"`
+	DurationMinutes *int   `json:"durationMinutes"`
+}
+
+func (p *PastPaperUpsert) UnmarshalJSON(data []byte) error {
+	type pastPaperUpsertJSON struct {
+		ExamType        string          `json:"examType"`
+		YearID          json.RawMessage `json:"yearId"`
+		SubjectID       json.RawMessage `json:"subjectId"`
+		PaperNumber     int             `json:"paperNumber"`
+		Name            string          `json:"name"`
+		DurationMinutes *int            `json:"durationMinutes"`
+	}
+
+	var aux pastPaperUpsertJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	p.ExamType = aux.ExamType
+	p.PaperNumber = aux.PaperNumber
+	p.Name = aux.Name
+	p.DurationMinutes = aux.DurationMinutes
+	yearID, err := parseFlexibleInt64(aux.YearID, "yearId")
+	if err != nil {
+		return err
+	}
+	p.YearID = yearID
+	subjectID, err := parseFlexibleInt64(aux.SubjectID, "subjectId")
+	if err != nil {
+		return err
+	}
+	p.SubjectID = nil
+	if subjectID != 0 {
+		p.SubjectID = &subjectID
+	}
+	return nil
+}
+
+// PastPaperFilter narrows the past paper list; zero values match everything.
+type PastPaperFilter struct {
+	ExamType  string
+	YearID    int64
+	SubjectID int64
+}
+
+// TrashItem is a soft-deleted menu config row. Entity is one of grade,
+// subject, lesson, topic, subtopic, tutor, year or tutorial.
+type TrashItem struct {
+	Entity    string `json:"entity"`
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	DeletedAt string `json:"deletedAt"`
+}
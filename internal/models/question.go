@@ -30,19 +30,166 @@ func (a StringArray) Value() (driver.Value, error) {
 	return json.Marshal(a)
 }
 
+// Question types. Rows created before types existed are single choice.
+const (
+	QuestionTypeSingleChoice = "single_choice"
+	QuestionTypeMultiSelect  = "multi_select"
+	QuestionTypeTrueFalse    = "true_false"
+	QuestionTypeNumeric      = "numeric"
+	QuestionTypeFillBlank    = "fill_blank"
+	QuestionTypeMatching     = "matching"
+)
+
+// Question is one item in the question bank. Single choice and true/false
+// questions are keyed by CorrectAnswer, with the distractors of single
+// choice questions in OtherAnswers. Every other type keeps its key in
+// Answer, and CorrectAnswer holds a readable form of it for display; multi
+// select questions also take their distractors from OtherAnswers.
 type Question struct {
-	ID            int         `json:"id" db:"id"`
-	GradeID       int         `json:"gradeId" db:"grade_id"`
-	LessonID      int         `json:"lessonId" db:"lesson_id"`
-	TopicID       *int        `json:"topicId,omitempty" db:"topic_id"`
-	SubtopicID    *int        `json:"subtopicId,omitempty" db:"subtopic_id"`
-	TutorID       *int        `json:"tutorId,omitempty" db:"tutor_id"`
-	TuteID        *int        `json:"tuteId,omitempty" db:"tute_id"`
-	Question      string      `json:"question" db:"question"`
-	QuestionImg   *string     `json:"questionImgUrl,omitempty" db:"question_img_url"`
-	CorrectAnswer string      `json:"correctAnswer" db:"correct_answer"`
-	Theory        *string     `json:"theory,omitempty" db:"theory"`
-	Solution      *string     `json:"solution,omitempty" db:"solution"`
-	OtherAnswers  StringArray `json:"otherAnswers" db:"other_answers"` // stored as JSON
-	CreatedAt     string      `json:"createdAt" db:"created_at"`
+	ID             int         `json:"id" db:"id"`
+	GradeID        int         `json:"gradeId" db:"grade_id"`
+	LessonID       int         `json:"lessonId" db:"lesson_id"`
+	TopicID        *int        `json:"topicId,omitempty" db:"topic_id"`
+	SubtopicID     *int        `json:"subtopicId,omitempty" db:"subtopic_id"`
+	TutorID        *int        `json:"tutorId,omitempty" db:"tutor_id"`
+	TuteID         *int        `json:"tuteId,omitempty" db:"tute_id"`
+	YearID         *int        `json:"yearId,omitempty" db:"year_id"`
+	PaperID        *int        `json:"paperId,omitempty" db:"paper_id"`
+	QuestionNumber *int        `json:"questionNumber,omitempty" db:"question_number"`
+	Question       string      `json:"question" db:"question"`
+	QuestionImg    *string     `json:"questionImgUrl,omitempty" db:"question_img_url"`
+	CorrectAnswer  string      `json:"correctAnswer" db:"correct_answer"`
+	Theory         *string     `json:"theory,omitempty" db:"theory"`
+	Solution       *string     `json:"solution,omitempty" db:"solution"`
+	OtherAnswers   StringArray `json:"otherAnswers" db:"other_answers"` // stored as JSON
+	Type           string      `json:"type" db:"question_type"`
+	Answer         *AnswerSpec `json:"answer,omitempty" db:"answer_spec"`
+	Status         string      `json:"status" db:"status"` // changed only through the review workflow
+	CreatedAt      string      `json:"createdAt" db:"created_at"`
+
+	// item statistics from the last calibration, read only
+	ResponseCount  int      `json:"responseCount" db:"response_count"`
+	PValue         *float64 `json:"pValue,omitempty" db:"p_value"`
+	PointBiserial  *float64 `json:"pointBiserial,omitempty" db:"point_biserial"`
+	Difficulty     *float64 `json:"difficulty,omitempty" db:"difficulty"`
+	Discrimination *float64 `json:"discrimination,omitempty" db:"discrimination"`
+}
+
+// PublicQuestion is a question as the public question endpoints show it:
+// its options in a random order, without the answer key or explanations.
+type PublicQuestion struct {
+	ID             int         `json:"id"`
+	GradeID        int         `json:"gradeId"`
+	LessonID       int         `json:"lessonId"`
+	TopicID        *int        `json:"topicId,omitempty"`
+	SubtopicID     *int        `json:"subtopicId,omitempty"`
+	TutorID        *int        `json:"tutorId,omitempty"`
+	TuteID         *int        `json:"tuteId,omitempty"`
+	YearID         *int        `json:"yearId,omitempty"`
+	PaperID        *int        `json:"paperId,omitempty"`
+	QuestionNumber *int        `json:"questionNumber,omitempty"`
+	Type           string      `json:"type"`
+	Question       string      `json:"question"`
+	QuestionImg    *string     `json:"questionImgUrl,omitempty"`
+	Prompts        StringArray `json:"prompts,omitempty"`
+	Options        StringArray `json:"options"`
+	Difficulty     *float64    `json:"difficulty,omitempty"`
+	CreatedAt      string      `json:"createdAt"`
+}
+
+// AnswerSpec is the answer key of the question types that do not fit a
+// single correct answer. Only the fields of the question's type are set.
+type AnswerSpec struct {
+	// multi_select: every correct option; all of them must be chosen
+	Correct []string `json:"correct,omitempty"`
+
+	// numeric: answers within Tolerance of Number are correct, with or
+	// without the Unit written after them
+	Number    *float64 `json:"number,omitempty"`
+	Tolerance float64  `json:"tolerance,omitempty"`
+	Unit      string   `json:"unit,omitempty"`
+
+	// fill_blank: the accepted answers for each ___ in the question text
+	Blanks [][]string `json:"blanks,omitempty"`
+
+	// matching: each left-hand prompt with the right-hand item it matches
+	Pairs []MatchPair `json:"pairs,omitempty"`
+}
+
+type MatchPair struct {
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+// Prompts returns the left-hand side of a matching question in order, or
+// nil for other types.
+func (s *AnswerSpec) Prompts() StringArray {
+	if s == nil || len(s.Pairs) == 0 {
+		return nil
+	}
+	prompts := make(StringArray, len(s.Pairs))
+	for i, p := range s.Pairs {
+		prompts[i] = p.Left
+	}
+	return prompts
+}
+
+func (s *AnswerSpec) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan AnswerSpec")
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+func (s AnswerSpec) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// QuestionCalibration is a question in the calibration report, with the
+// review flags its statistics raise.
+type QuestionCalibration struct {
+	QuestionID     int      `json:"questionId"`
+	LessonID       int      `json:"lessonId"`
+	Type           string   `json:"type"`
+	Question       string   `json:"question"`
+	CorrectAnswer  string   `json:"correctAnswer"`
+	ResponseCount  int      `json:"responseCount"`
+	PValue         float64  `json:"pValue"`
+	PointBiserial  *float64 `json:"pointBiserial,omitempty"`
+	Difficulty     *float64 `json:"difficulty,omitempty"`
+	Discrimination *float64 `json:"discrimination,omitempty"`
+	CalibratedAt   *string  `json:"calibratedAt,omitempty"`
+	Flags          []string `json:"flags"`
+}
+
+// CalibrationFilter narrows the calibration report. Flag is one of the irt
+// flag names, or empty for every flagged question.
+type CalibrationFilter struct {
+	Flag         string
+	LessonID     int
+	MinResponses int
+}
+
+// DuplicateMatch is a question that another closely resembles. Imports
+// also report matches against earlier rows of the same file, by Line.
+type DuplicateMatch struct {
+	QuestionID int     `json:"questionId,omitempty"`
+	Line       int     `json:"line,omitempty"`
+	LessonID   int     `json:"lessonId,omitempty"`
+	Question   string  `json:"question"`
+	Score      float64 `json:"score"`
+}
+
+// DuplicateCluster is a group of questions that are likely duplicates of
+// one another.
+type DuplicateCluster struct {
+	Questions []DuplicateMatch `json:"questions"`
+}
+
+// CreatedQuestion is a newly saved question with the existing questions it
+// closely resembles.
+type CreatedQuestion struct {
+	Question
+	Duplicates []DuplicateMatch `json:"duplicates,omitempty"`
 }
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+const (
+	QuizStatusInProgress = "in_progress"
+	QuizStatusSubmitted  = "submitted"
+)
+
+// QuizAttempt is a student's run through a set of questions. The answer key
+// fields on its questions are only populated once the attempt is submitted.
+//
+// Timed attempts carry a TimeLimit, counted from creation to DeadlineAt. Submissions after it are marked Late or
+// rejected, depending on LatePolicy; Overdue is set when the deadline and
+// its grace period have passed.
+type QuizAttempt struct {
+	ID             int            `json:"id" db:"id"`
+	StudentID      *int           `json:"studentId,omitempty" db:"student_id"`
+	GradeID        *int           `json:"gradeId,omitempty" db:"grade_id"`
+	LessonID       *int           `json:"lessonId,omitempty" db:"lesson_id"`
+	TopicID        *int           `json:"topicId,omitempty" db:"topic_id"`
+	SubtopicID     *int           `json:"subtopicId,omitempty" db:"subtopic_id"`
+	PaperID        *int           `json:"paperId,omitempty" db:"paper_id"`
+	BlueprintID    *int           `json:"blueprintId,omitempty" db:"blueprint_id"`
+	Seed           *int64         `json:"seed,omitempty" db:"seed"`
+	Lang           string         `json:"lang" db:"lang"`
+	Status         string         `json:"status" db:"status"`
+	Score          int            `json:"score" db:"score"`
+	TotalQuestions int            `json:"totalQuestions" db:"total_questions"`
+	CreatedAt      string         `json:"createdAt" db:"created_at"`
+	SubmittedAt    *string        `json:"submittedAt,omitempty" db:"submitted_at"`
+	TimeLimit      *int           `json:"timeLimitMinutes,omitempty" db:"time_limit_minutes"`
+	DeadlineAt     *string        `json:"deadlineAt,omitempty" db:"deadline_at"`
+	LatePolicy     *string        `json:"latePolicy,omitempty" db:"late_policy"`
+	Late           bool           `json:"late" db:"late"`
+	Overdue        bool           `json:"-"`
+	Questions      []QuizQuestion `json:"questions"`
+}
+
+// QuizQuestion is a question as it appears inside an attempt.
+type QuizQuestion struct {
+	QuestionID     int         `json:"questionId" db:"question_id"`
+	Position       int         `json:"position" db:"position"`
+	Question       string      `json:"question" db:"question"`
+	QuestionImg    *string     `json:"questionImgUrl,omitempty" db:"question_img_url"`
+	Type           string      `json:"type" db:"question_type"`
+	Prompts        StringArray `json:"prompts,omitempty"`
+	Options        StringArray `json:"options" db:"options"`
+	SelectedAnswer AnswerValue `json:"selectedAnswer,omitempty" db:"selected_answer"`
+	IsCorrect      *bool       `json:"isCorrect,omitempty" db:"is_correct"`
+
+	// revealed after submission only
+	CorrectAnswer *string `json:"correctAnswer,omitempty" db:"correct_answer"`
+	Theory        *string `json:"theory,omitempty" db:"theory"`
+	Solution      *string `json:"solution,omitempty" db:"solution"`
+
+	// Answer is the key used for grading and is never sent
+	Answer *AnswerSpec `json:"-" db:"answer_spec"`
+}
+
+type StartQuizRequest struct {
+	GradeID       *int `json:"gradeId"`
+	LessonID      *int `json:"lessonId"`
+	TopicID       *int `json:"topicId"`
+	SubtopicID    *int `json:"subtopicId"`
+	TutorID       *int `json:"tutorId"`
+	TuteID        *int `json:"tuteId"`
+	QuestionCount int  `json:"questionCount"`
+
+	// PaperID starts a run through a whole past paper in its printed order;
+	// the other filters and QuestionCount are ignored
+	PaperID *int `json:"paperId"`
+	// BlueprintID starts a timed mock exam drawn from the blueprint with Seed,
+	// or with a random seed when Seed is not given
+	BlueprintID *int   `json:"blueprintId"`
+	Seed        *int64 `json:"seed"`
+}
+
+type QuizAnswer struct {
+	QuestionID int         `json:"questionId"`
+	Answer     AnswerValue `json:"answer"`
+}
+
+// AnswerValue is a submitted answer. Single choice, true/false and numeric
+// answers have one value; multi select answers have one per chosen option,
+// fill in the blank answers one per blank and matching answers the
+// right-hand item chosen for each prompt, in prompt order.
+//
+// In JSON a single value can be sent as a plain string, number or boolean,
+// and is sent back as a string.
+type AnswerValue []string
+
+func (a *AnswerValue) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*a = nil
+		return nil
+	}
+	if len(data) > 0 && data[0] == '[' {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		values := make(AnswerValue, len(raw))
+		for i, r := range raw {
+			v, err := answerScalar(r)
+			if err != nil {
+				return err
+			}
+			values[i] = v
+		}
+		*a = values
+		return nil
+	}
+	v, err := answerScalar(data)
+	if err != nil {
+		return err
+	}
+	*a = AnswerValue{v}
+	return nil
+}
+
+func answerScalar(data json.RawMessage) (string, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return "", err
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		// keep the number as written, e.g. 9.80
+		return string(data), nil
+	}
+	return "", errors.New("answer must be a string, number, boolean or a list of them")
+}
+
+func (a AnswerValue) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// Scan reads answers stored before there were multi-value answers as a
+// single value.
+func (a *AnswerValue) Scan(value interface{}) error {
+	if value == nil {
+		*a = nil
+		return nil
+	}
+	var s string
+	switch v := value.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return errors.New("failed to scan AnswerValue")
+	}
+	var values []string
+	if len(s) > 0 && s[0] == '[' && json.Unmarshal([]byte(s), &values) == nil {
+		*a = values
+		return nil
+	}
+	*a = AnswerValue{s}
+	return nil
+}
+
+// Value stores a single value as is and several as a JSON list.
+func (a AnswerValue) Value() (driver.Value, error) {
+	switch len(a) {
+	case 0:
+		if a == nil {
+			return nil, nil
+		}
+		return "[]", nil
+	case 1:
+		return a[0], nil
+	}
+	b, err := json.Marshal([]string(a))
+	return string(b), err
+}
+
+type SubmitQuizRequest struct {
+	Answers []QuizAnswer `json:"answers"`
+}
+
+// PaperQuestion is a past paper question as printed, without its answer key.
+type PaperQuestion struct {
+	QuestionID     int         `json:"questionId"`
+	QuestionNumber *int        `json:"questionNumber,omitempty"`
+	Type           string      `json:"type"`
+	Question       string      `json:"question"`
+	QuestionImg    *string     `json:"questionImgUrl,omitempty"`
+	Prompts        StringArray `json:"prompts,omitempty"`
+	Options        StringArray `json:"options"`
+}
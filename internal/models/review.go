@@ -0,0 +1,34 @@
+package models
+
+const (
+	ReviewCardQuestion  = "question"
+	ReviewCardSmartNote = "smartnote"
+)
+
+// ReviewCard is an item in a student's spaced-repetition deck. Prompt and
+// Answer are the two sides of the card: the question and its key, or the
+// smart note's subtopic and definition.
+type ReviewCard struct {
+	ID             int     `json:"id"`
+	CardType       string  `json:"type"`
+	ItemID         int     `json:"itemId"`
+	EaseFactor     float64 `json:"easeFactor"`
+	IntervalDays   int     `json:"intervalDays"`
+	Repetitions    int     `json:"repetitions"`
+	Lapses         int     `json:"lapses"`
+	DueAt          string  `json:"dueAt"`
+	LastReviewedAt *string `json:"lastReviewedAt,omitempty"`
+
+	Prompt      string  `json:"prompt"`
+	Answer      *string `json:"answer,omitempty"`
+	Explanation *string `json:"explanation,omitempty"`
+}
+
+type AddReviewCardRequest struct {
+	Type   string `json:"type" binding:"required"`
+	ItemID int    `json:"itemId" binding:"required"`
+}
+
+type GradeReviewCardRequest struct {
+	Grade *int `json:"grade" binding:"required"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// SearchCrumb is one step of a hit's curriculum path. Type is one of the
+// Entity* constants.
+type SearchCrumb struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// SearchHit is a ranked search result. Snippet is HTML-escaped text with the
+// matched words wrapped in <mark>.
+type SearchHit struct {
+	Type       string        `json:"type"`
+	ID         int64         `json:"id"`
+	Title      string        `json:"title"`
+	Snippet    string        `json:"snippet"`
+	Score      float64       `json:"score"`
+	Breadcrumb []SearchCrumb `json:"breadcrumb"`
+}
+
+type SearchResponse struct {
+	Query  string      `json:"query"`
+	Lang   string      `json:"lang"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+	Hits   []SearchHit `json:"hits"`
+}
@@ -0,0 +1,57 @@
+package models
+
+// SmartNoteRecord is the full, editable form of a smart note used by the
+// admin endpoints. Public endpoints keep returning the slimmer SmartNote.
+type SmartNoteRecord struct {
+	ID              int64   `json:"id"`
+	LessonID        int64   `json:"lessonId"`
+	TopicID         *int64  `json:"topicId"`
+	SubtopicID      *int64  `json:"subtopicId"`
+	SubTopicName    string  `json:"subTopicName"`
+	ImageDefUrl     *string `json:"imageDefUrl"`
+	Definition      *string `json:"definition"`
+	Theory          *string `json:"theory"`
+	ImageTheoryUrl  *string `json:"imageTheoryUrl"`
+	Example         *string `json:"example"`
+	ImageExampleUrl *string `json:"imageExampleUrl"`
+	IsDefault       bool    `json:"isDefault"`
+	Status          string  `json:"status"`
+	Revision        int     `json:"revision"`
+	UpdatedBy       *int64  `json:"updatedBy"`
+	CreatedAt       string  `json:"createdAt"`
+	UpdatedAt       *string `json:"updatedAt"`
+}
+
+type SmartNoteUpsert struct {
+	LessonID        int64   `json:"lessonId"`
+	TopicID         *int64  `json:"topicId"`
+	SubtopicID      *int64  `json:"subtopicId"`
+	SubTopicName    string  `json:"subTopicName"`
+	ImageDefUrl     *string `json:"imageDefUrl"`
+	Definition      *string `json:"definition"`
+	Theory          *string `json:"theory"`
+	ImageTheoryUrl  *string `json:"imageTheoryUrl"`
+	Example         *string `json:"example"`
+	ImageExampleUrl *string `json:"imageExampleUrl"`
+	IsDefault       bool    `json:"isDefault"`
+}
+
+// SmartNoteRevision is a snapshot of a smart note as it was before an edit.
+type SmartNoteRevision struct {
+	ID              int64   `json:"id"`
+	SmartNoteID     int64   `json:"smartNoteId"`
+	Revision        int     `json:"revision"`
+	LessonID        int64   `json:"lessonId"`
+	TopicID         *int64  `json:"topicId"`
+	SubtopicID      *int64  `json:"subtopicId"`
+	SubTopicName    string  `json:"subTopicName"`
+	ImageDefUrl     *string `json:"imageDefUrl"`
+	Definition      *string `json:"definition"`
+	Theory          *string `json:"theory"`
+	ImageTheoryUrl  *string `json:"imageTheoryUrl"`
+	Example         *string `json:"example"`
+	ImageExampleUrl *string `json:"imageExampleUrl"`
+	IsDefault       bool    `json:"isDefault"`
+	EditedBy        *int64  `json:"editedBy"`
+	CreatedAt       string  `json:"createdAt"`
+}
@@ -14,6 +14,7 @@ type Topic struct {
 }
 
 type SmartNote struct {
+	ID              int    `json:"id"`
 	SubTopicName    string `json:"subTopicName"`
 	ImageDefUrl     string `json:"imageDefUrl"`
 	Definition      string `json:"definition"`
@@ -0,0 +1,47 @@
+package models
+
+// Entities that carry translatable text.
+const (
+	EntityGrade     = "grade"
+	EntitySubject   = "subject"
+	EntityLesson    = "lesson"
+	EntityTopic     = "topic"
+	EntitySubtopic  = "subtopic"
+	EntitySmartNote = "smartnote"
+	EntityQuestion  = "question"
+)
+
+// TranslationFields holds the translatable text of an entity. Which fields
+// apply depends on the entity: curriculum entities only have Name, smart
+// notes and questions have their own text columns. A nil field means "use
+// the base text". Answer carries the translated key of multi select, fill in
+// the blank and matching questions: only its Correct, Blanks or Pairs.
+type TranslationFields struct {
+	Name          *string     `json:"name,omitempty"`
+	SubTopicName  *string     `json:"subTopicName,omitempty"`
+	Definition    *string     `json:"definition,omitempty"`
+	Example       *string     `json:"example,omitempty"`
+	Question      *string     `json:"question,omitempty"`
+	CorrectAnswer *string     `json:"correctAnswer,omitempty"`
+	OtherAnswers  StringArray `json:"otherAnswers,omitempty"`
+	Answer        *AnswerSpec `json:"answer,omitempty"`
+	Theory        *string     `json:"theory,omitempty"`
+	Solution      *string     `json:"solution,omitempty"`
+}
+
+type Translation struct {
+	Entity   string `json:"entity"`
+	EntityID int64  `json:"entityId"`
+	Lang     string `json:"lang"`
+	TranslationFields
+	UpdatedBy *int64 `json:"updatedBy"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// MissingTranslation is an entity with no translation in a language, listed
+// with its base text so editors know what to translate.
+type MissingTranslation struct {
+	Entity   string `json:"entity"`
+	EntityID int64  `json:"entityId"`
+	BaseText string `json:"baseText"`
+}
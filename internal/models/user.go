@@ -0,0 +1,54 @@
+package models
+
+const (
+	RoleAdmin         = "admin"
+	RoleContentEditor = "content_editor"
+	RoleTutor         = "tutor"
+	RoleStudent       = "student"
+)
+
+// ValidRole reports whether role is one of the known account roles.
+func ValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleContentEditor, RoleTutor, RoleStudent:
+		return true
+	}
+	return false
+}
+
+type User struct {
+	ID           int    `json:"id" db:"id"`
+	Email        string `json:"email" db:"email"`
+	Name         string `json:"name" db:"name"`
+	PasswordHash string `json:"-" db:"password_hash"`
+	Role         string `json:"role" db:"role"`
+	TutorID      *int   `json:"tutorId,omitempty" db:"tutor_id"`
+	CreatedAt    string `json:"createdAt" db:"created_at"`
+}
+
+type RegisterRequest struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type LoginResponse struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expiresAt"`
+	User      User   `json:"user"`
+}
+
+// UserUpsert is used by admins to create accounts or change a user's role.
+// Password is optional on update.
+type UserUpsert struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+	TutorID  *int   `json:"tutorId"`
+}
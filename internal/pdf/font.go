@@ -0,0 +1,99 @@
+package pdf
+
+// Font is one of the standard PDF fonts, which every reader provides, so
+// nothing has to be embedded. They only cover the Windows-1252 (WinAnsi)
+// character set; other characters are drawn as '?'.
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+func (f Font) baseFont() string {
+	if f == HelveticaBold {
+		return "Helvetica-Bold"
+	}
+	return "Helvetica"
+}
+
+func (f Font) resource() string {
+	if f == HelveticaBold {
+		return "F2"
+	}
+	return "F1"
+}
+
+// widths of the printable ASCII characters from ' ' to '~', in thousandths
+// of the font size, from the Adobe font metrics
+var asciiWidths = [2][95]uint16{
+	Helvetica: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	HelveticaBold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+// widths of the WinAnsi characters above ASCII that differ from the 556 most
+// accented letters use; the metrics are close enough for both weights
+var extendedWidths = map[byte]uint16{
+	0x85: 1000, 0x91: 222, 0x92: 222, 0x93: 333, 0x94: 333, 0x95: 350, 0x96: 556, 0x97: 1000,
+	0xA0: 278, 0xB0: 400, 0xB1: 584, 0xB2: 333, 0xB3: 333, 0xB7: 278, 0xB9: 333,
+	0xBC: 834, 0xBD: 834, 0xBE: 834, 0xD7: 584, 0xF7: 584,
+}
+
+// winAnsi maps the characters of Windows-1252 that are not at their Latin-1
+// code point.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encode converts s to WinAnsi bytes.
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r >= 0x20 && r < 0x7F, r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsi[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+// width returns the width of encoded text in points.
+func (f Font) width(text []byte, size float64) float64 {
+	total := 0
+	for _, b := range text {
+		switch {
+		case b >= 0x20 && b < 0x7F:
+			total += int(asciiWidths[f][b-0x20])
+		case extendedWidths[b] != 0:
+			total += int(extendedWidths[b])
+		default:
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
@@ -0,0 +1,67 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif" // decoders for image.Decode
+	"image/jpeg"
+	_ "image/png"
+)
+
+// Image is a picture that can be drawn in a Document.
+type Image struct {
+	Width, Height int
+
+	data       []byte
+	colorSpace string
+	filter     string
+	name       string
+	added      bool
+}
+
+// ErrUnsupportedImage is returned for data that is not a JPEG, PNG or GIF.
+var ErrUnsupportedImage = errors.New("unsupported image format")
+
+// DecodeImage prepares image data for embedding. Baseline JPEGs are embedded
+// as they are; anything else is decoded and re-compressed, with transparent
+// areas drawn on white.
+func DecodeImage(data []byte) (*Image, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if format == "jpeg" {
+		if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+		switch cfg.ColorModel {
+		case color.YCbCrModel:
+			return &Image{Width: cfg.Width, Height: cfg.Height, data: data, colorSpace: "DeviceRGB", filter: "DCTDecode"}, nil
+		case color.GrayModel:
+			return &Image{Width: cfg.Width, Height: cfg.Height, data: data, colorSpace: "DeviceGray", filter: "DCTDecode"}, nil
+		}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	rgb := make([]byte, 0, b.Dx()*b.Dy()*3)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, a := img.At(x, y).RGBA()
+			// colours are alpha-premultiplied, so adding the uncovered
+			// share of white composites onto a white page
+			white := 0xffff - a
+			rgb = append(rgb, byte((r+white)>>8), byte((g+white)>>8), byte((bl+white)>>8))
+		}
+	}
+	compressed, err := deflate(rgb)
+	if err != nil {
+		return nil, err
+	}
+	return &Image{Width: b.Dx(), Height: b.Dy(), data: compressed, colorSpace: "DeviceRGB", filter: "FlateDecode"}, nil
+}
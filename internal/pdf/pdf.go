@@ -0,0 +1,285 @@
+// Package pdf writes simple flowing A4 documents: wrapped paragraphs in the
+// standard Helvetica fonts and embedded JPEG or PNG images. It is pure Go
+// and needs no fonts or binaries on the host.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// A4 in points
+	PageWidth  = 595.28
+	PageHeight = 841.89
+	Margin     = 56.0
+
+	lineSpacing  = 1.3
+	footerSize   = 9.0
+	footerOffset = 28.0
+)
+
+type page struct {
+	content bytes.Buffer
+}
+
+// Document is laid out top to bottom; content that does not fit on the
+// current page moves to a new one.
+type Document struct {
+	title  string
+	pages  []*page
+	y      float64 // distance of the next line from the top of the page
+	images []*Image
+}
+
+// New starts a document whose pages are footed with title and page numbers.
+func New(title string) *Document {
+	d := &Document{title: title}
+	d.AddPage()
+	return d
+}
+
+// ContentWidth is the width between the margins.
+func (d *Document) ContentWidth() float64 {
+	return PageWidth - 2*Margin
+}
+
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &page{})
+	d.y = Margin
+}
+
+// PageCount is the number of pages so far.
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+func (d *Document) current() *page {
+	return d.pages[len(d.pages)-1]
+}
+
+// ensure starts a new page unless height more points fit on this one.
+func (d *Document) ensure(height float64) {
+	if d.y+height > PageHeight-Margin && d.y > Margin {
+		d.AddPage()
+	}
+}
+
+// Space moves down by height points.
+func (d *Document) Space(height float64) {
+	d.y += height
+}
+
+// Paragraph writes text wrapped to the content width, starting indent points
+// from the left margin. Line breaks in text are kept.
+func (d *Document) Paragraph(text string, font Font, size, indent float64) {
+	d.Hanging("", text, font, size, indent, 0)
+}
+
+// Hanging writes a paragraph with a label, such as a question number, in
+// front of its first line. Wrapped lines line up with the text after the
+// label, labelWidth points in.
+func (d *Document) Hanging(label, text string, font Font, size, indent, labelWidth float64) {
+	lineHeight := size * lineSpacing
+	lines := wrap(text, font, size, d.ContentWidth()-indent-labelWidth)
+	for i, line := range lines {
+		d.ensure(lineHeight)
+		baseline := d.y + size
+		if i == 0 && label != "" {
+			d.text(Margin+indent, baseline, font, size, encode(label))
+		}
+		d.text(Margin+indent+labelWidth, baseline, font, size, line)
+		d.y += lineHeight
+	}
+}
+
+func (d *Document) text(x, baseline float64, font Font, size float64, text []byte) {
+	fmt.Fprintf(&d.current().content, "BT /%s %s Tf %s %s Td %s Tj ET\n",
+		font.resource(), num(size), num(x), num(PageHeight-baseline), literal(text))
+}
+
+// Image draws img indent points from the left margin, scaled down to fit
+// within maxWidth and maxHeight but never enlarged.
+func (d *Document) Image(img *Image, indent, maxWidth, maxHeight float64) {
+	if maxWidth <= 0 || maxWidth > d.ContentWidth()-indent {
+		maxWidth = d.ContentWidth() - indent
+	}
+	w, h := float64(img.Width), float64(img.Height)
+	scale := 1.0
+	if w > maxWidth {
+		scale = maxWidth / w
+	}
+	if h*scale > maxHeight {
+		scale = maxHeight / h
+	}
+	w, h = w*scale, h*scale
+
+	d.ensure(h)
+	if !img.added {
+		d.images = append(d.images, img)
+		img.name = "Im" + strconv.Itoa(len(d.images))
+		img.added = true
+	}
+	fmt.Fprintf(&d.current().content, "q %s 0 0 %s %s %s cm /%s Do Q\n",
+		num(w), num(h), num(Margin+indent), num(PageHeight-d.y-h), img.name)
+	d.y += h
+}
+
+// Rule draws a thin horizontal line across the content width.
+func (d *Document) Rule() {
+	d.ensure(1)
+	y := num(PageHeight - d.y)
+	fmt.Fprintf(&d.current().content, "0.5 w %s %s m %s %s l S\n", num(Margin), y, num(PageWidth-Margin), y)
+}
+
+// WriteTo serialises the document, adding the footer to every page.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	offsets := []int{0}
+	object := func(body string, stream []byte) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s", len(offsets)-1, body)
+		if stream != nil {
+			buf.WriteString("\nstream\n")
+			buf.Write(stream)
+			buf.WriteString("\nendstream")
+		}
+		buf.WriteString("\nendobj\n")
+	}
+
+	// objects 1-4 are fixed; images follow, then a page and its content
+	// stream per page
+	firstImage := 5
+	firstPage := firstImage + len(d.images)
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>", nil)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)), nil)
+	for _, f := range []Font{Helvetica, HelveticaBold} {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.baseFont()), nil)
+	}
+
+	var xobjects strings.Builder
+	for i, img := range d.images {
+		fmt.Fprintf(&xobjects, " /%s %d 0 R", img.name, firstImage+i)
+		object(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /%s /Length %d >>",
+			img.Width, img.Height, img.colorSpace, img.filter, len(img.data)), img.data)
+	}
+	resources := "<< /Font << /F1 3 0 R /F2 4 0 R >>"
+	if xobjects.Len() > 0 {
+		resources += " /XObject <<" + xobjects.String() + " >>"
+	}
+	resources += " >>"
+
+	for i, p := range d.pages {
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(d.pages))
+		if d.title != "" {
+			footer = d.title + "  ·  " + footer
+		}
+		content := p.content.Bytes()
+		var footed bytes.Buffer
+		footed.Write(content)
+		text := encode(footer)
+		fmt.Fprintf(&footed, "BT /F1 %s Tf %s %s Td %s Tj ET\n", num(footerSize),
+			num((PageWidth-Helvetica.width(text, footerSize))/2), num(footerOffset), literal(text))
+
+		stream, err := deflate(footed.Bytes())
+		if err != nil {
+			return 0, err
+		}
+		contentRef := firstPage + 2*i + 1
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+			num(PageWidth), num(PageHeight), resources, contentRef), nil)
+		object(fmt.Sprintf("<< /Filter /FlateDecode /Length %d >>", len(stream)), stream)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets))
+	for _, off := range offsets[1:] {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets), xref)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// wrap breaks text into lines no wider than width. Words longer than a line
+// are split.
+func wrap(text string, font Font, size, width float64) [][]byte {
+	var lines [][]byte
+	space := font.width([]byte{' '}, size)
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		var line []byte
+		lineWidth := 0.0
+		for _, word := range strings.Fields(para) {
+			w := encode(word)
+			ww := font.width(w, size)
+			if len(line) > 0 && lineWidth+space+ww <= width {
+				line = append(append(line, ' '), w...)
+				lineWidth += space + ww
+				continue
+			}
+			if len(line) > 0 {
+				lines = append(lines, line)
+			}
+			// split words that cannot fit on a line of their own
+			for ww > width && len(w) > 1 {
+				n := 1
+				for n < len(w) && font.width(w[:n+1], size) <= width {
+					n++
+				}
+				lines = append(lines, w[:n])
+				w = w[n:]
+				ww = font.width(w, size)
+			}
+			line, lineWidth = w, ww
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// literal writes text as a PDF string, escaping delimiters and anything
+// outside printable ASCII.
+func literal(text []byte) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, c := range text {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c > 0x7E:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+func num(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
+
+func deflate(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,85 @@
+package pdf
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeAndWidth(t *testing.T) {
+	assert.Equal(t, []byte("caf\xe9 \x80 ?"), encode("café € ත"))
+	for f := range asciiWidths {
+		for i, w := range asciiWidths[f] {
+			assert.NotZero(t, w, "width of %q", rune(' '+i))
+		}
+	}
+	assert.InDelta(t, 10*0.556*2, Helvetica.width([]byte("ab"), 10), 1e-9)
+}
+
+func TestWrap(t *testing.T) {
+	lines := wrap("the quick brown fox\n\njumps", Helvetica, 10, 60)
+	var got []string
+	for _, l := range lines {
+		got = append(got, string(l))
+	}
+	assert.Equal(t, []string{"the quick", "brown fox", "", "jumps"}, got)
+
+	for _, l := range wrap(strings.Repeat("x", 100), Helvetica, 10, 50) {
+		assert.LessOrEqual(t, Helvetica.width(l, 10), 50.0)
+	}
+}
+
+func TestLiteralEscapes(t *testing.T) {
+	assert.Equal(t, `(a\(b\)\\ \351)`, literal([]byte("a(b)\\ \xe9")))
+}
+
+func TestDocumentStructure(t *testing.T) {
+	var pngData bytes.Buffer
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	src.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	require.NoError(t, png.Encode(&pngData, src))
+	img, err := DecodeImage(pngData.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "FlateDecode", img.filter)
+
+	d := New("Worksheet")
+	for i := 0; i < 80; i++ {
+		d.Hanging(strconv.Itoa(i+1)+".", "A question long enough to wrap onto a second line of the page at this size, probably.", Helvetica, 11, 0, 20)
+	}
+	d.Image(img, 20, 200, 100)
+	require.Greater(t, d.PageCount(), 1)
+
+	var out bytes.Buffer
+	_, err = d.WriteTo(&out)
+	require.NoError(t, err)
+	pdf := out.String()
+
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.Contains(t, pdf, "/Count "+strconv.Itoa(d.PageCount()))
+	assert.Contains(t, pdf, "/Subtype /Image /Width 4 /Height 2")
+
+	// every xref entry points at the object it names
+	xref := regexp.MustCompile(`(?m)^(\d{10}) 00000 n $`).FindAllStringSubmatch(pdf, -1)
+	require.NotEmpty(t, xref)
+	for i, m := range xref {
+		off, _ := strconv.Atoi(m[1])
+		assert.True(t, strings.HasPrefix(pdf[off:], strconv.Itoa(i+1)+" 0 obj"), "object %d", i+1)
+	}
+	start := regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(pdf)
+	off, _ := strconv.Atoi(start[1])
+	assert.True(t, strings.HasPrefix(pdf[off:], "xref"))
+}
+
+func TestDecodeImageRejectsUnknownData(t *testing.T) {
+	_, err := DecodeImage([]byte("<svg/>"))
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+}
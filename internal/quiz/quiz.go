@@ -0,0 +1,135 @@
+// Package quiz holds the grading rules for quiz attempts, kept free of any
+// database or HTTP concerns so they can be tested directly.
+package quiz
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+// SubmitGrace is how long after its deadline a timed attempt can still be
+// submitted on time, to absorb network delay.
+const SubmitGrace = 30 * time.Second
+
+// BuildOptions returns the options to show for q, in a random order where
+// order does not matter. For choice questions the correct answers are always
+// included even when the author left them out of OtherAnswers; matching
+// questions offer their right-hand items, and numeric and fill in the blank
+// questions have no options.
+func BuildOptions(q models.Question, rng *rand.Rand) models.StringArray {
+	var candidates []string
+	switch q.Type {
+	case models.QuestionTypeTrueFalse:
+		return models.StringArray{True, False}
+	case models.QuestionTypeNumeric, models.QuestionTypeFillBlank:
+		return models.StringArray{}
+	case models.QuestionTypeMultiSelect:
+		if q.Answer != nil {
+			candidates = append(candidates, q.Answer.Correct...)
+		}
+		candidates = append(candidates, q.OtherAnswers...)
+	case models.QuestionTypeMatching:
+		if q.Answer != nil {
+			for _, p := range q.Answer.Pairs {
+				candidates = append(candidates, p.Right)
+			}
+		}
+	default:
+		candidates = append([]string{q.CorrectAnswer}, q.OtherAnswers...)
+	}
+
+	seen := make(map[string]bool, len(candidates))
+	options := make(models.StringArray, 0, len(candidates))
+	for _, opt := range candidates {
+		key := normalize(opt)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		options = append(options, opt)
+	}
+	rng.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+	return options
+}
+
+// Public strips the answer key and explanations from q, leaving what a
+// student sees before answering it.
+func Public(q models.Question, rng *rand.Rand) models.PublicQuestion {
+	return models.PublicQuestion{
+		ID:             q.ID,
+		GradeID:        q.GradeID,
+		LessonID:       q.LessonID,
+		TopicID:        q.TopicID,
+		SubtopicID:     q.SubtopicID,
+		TutorID:        q.TutorID,
+		TuteID:         q.TuteID,
+		YearID:         q.YearID,
+		PaperID:        q.PaperID,
+		QuestionNumber: q.QuestionNumber,
+		Type:           Type(q),
+		Question:       q.Question,
+		QuestionImg:    q.QuestionImg,
+		Prompts:        q.Answer.Prompts(),
+		Options:        BuildOptions(q, rng),
+		Difficulty:     q.Difficulty,
+		CreatedAt:      q.CreatedAt,
+	}
+}
+
+// IsCorrect reports whether answer matches the key, ignoring surrounding
+// whitespace and letter case.
+func IsCorrect(answer, correct string) bool {
+	return normalize(answer) != "" && normalize(answer) == normalize(correct)
+}
+
+// Grade records the submitted answers on the attempt's questions and returns
+// the score, one point per fully correct question. Questions must carry
+// their answer key; unanswered questions are marked incorrect.
+func Grade(questions []models.QuizQuestion, answers []models.QuizAnswer) int {
+	byQuestion := make(map[int]models.AnswerValue, len(answers))
+	for _, a := range answers {
+		byQuestion[a.QuestionID] = a.Answer
+	}
+
+	score := 0
+	for i := range questions {
+		q := &questions[i]
+		correct := false
+		if answer, ok := byQuestion[q.QuestionID]; ok {
+			selected := make(models.AnswerValue, len(answer))
+			for j, v := range answer {
+				selected[j] = strings.TrimSpace(v)
+			}
+			q.SelectedAnswer = selected
+			if q.CorrectAnswer != nil {
+				correct = Check(q.Type, *q.CorrectAnswer, q.Answer, selected)
+			}
+		}
+		q.IsCorrect = &correct
+		if correct {
+			score++
+		}
+	}
+	return score
+}
+
+// Redact hides the answer key and explanations from an attempt that has not
+// been submitted yet.
+func Redact(attempt *models.QuizAttempt) {
+	if attempt.Status == models.QuizStatusSubmitted {
+		return
+	}
+	for i := range attempt.Questions {
+		attempt.Questions[i].CorrectAnswer = nil
+		attempt.Questions[i].Theory = nil
+		attempt.Questions[i].Solution = nil
+		attempt.Questions[i].IsCorrect = nil
+	}
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
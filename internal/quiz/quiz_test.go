@@ -0,0 +1,57 @@
+package quiz
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestBuildOptionsIncludesCorrectAnswerOnce(t *testing.T) {
+	q := models.Question{
+		CorrectAnswer: "20 cm",
+		OtherAnswers:  models.StringArray{"10 cm", "20 cm", "25 cm"},
+	}
+
+	options := BuildOptions(q, rand.New(rand.NewSource(1)))
+
+	assert.Len(t, options, 3)
+	assert.ElementsMatch(t, []string{"10 cm", "20 cm", "25 cm"}, []string(options))
+}
+
+func TestGradeScoresAndMarksAnswers(t *testing.T) {
+	questions := []models.QuizQuestion{
+		{QuestionID: 1, CorrectAnswer: strPtr("5")},
+		{QuestionID: 2, CorrectAnswer: strPtr("48 cm²")},
+		{QuestionID: 3, CorrectAnswer: strPtr("9")},
+	}
+	answers := []models.QuizAnswer{
+		{QuestionID: 1, Answer: models.AnswerValue{" 5 "}},
+		{QuestionID: 2, Answer: models.AnswerValue{"24 cm²"}},
+	}
+
+	score := Grade(questions, answers)
+
+	assert.Equal(t, 1, score)
+	assert.True(t, *questions[0].IsCorrect)
+	assert.False(t, *questions[1].IsCorrect)
+	assert.False(t, *questions[2].IsCorrect)
+	assert.Nil(t, questions[2].SelectedAnswer)
+}
+
+func TestRedactHidesKeyUntilSubmitted(t *testing.T) {
+	attempt := &models.QuizAttempt{
+		Status: models.QuizStatusInProgress,
+		Questions: []models.QuizQuestion{
+			{QuestionID: 1, CorrectAnswer: strPtr("5"), Solution: strPtr("3² + 4² = 25")},
+		},
+	}
+
+	Redact(attempt)
+
+	assert.Nil(t, attempt.Questions[0].CorrectAnswer)
+	assert.Nil(t, attempt.Questions[0].Solution)
+}
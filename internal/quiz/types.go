@@ -0,0 +1,190 @@
+package quiz
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+// The options of a true/false question, and its stored answer key.
+const (
+	True  = "True"
+	False = "False"
+)
+
+// numericEpsilon absorbs floating point error at the tolerance boundary.
+const numericEpsilon = 1e-9
+
+// Type returns the type of q, treating rows from before question types as
+// single choice.
+func Type(q models.Question) string {
+	if q.Type == "" {
+		return models.QuestionTypeSingleChoice
+	}
+	return q.Type
+}
+
+// Check reports whether answer is correct for a question of the given type.
+// correct is the question's CorrectAnswer, which keys single choice and
+// true/false questions; the other types are keyed by spec. Answers are
+// compared ignoring surrounding whitespace and letter case, and must be
+// complete: a multi select answer has to pick every correct option and
+// nothing else.
+func Check(typ, correct string, spec *models.AnswerSpec, answer models.AnswerValue) bool {
+	switch typ {
+	case "", models.QuestionTypeSingleChoice, models.QuestionTypeTrueFalse:
+		return len(answer) == 1 && IsCorrect(answer[0], correct)
+	}
+	if spec == nil {
+		return false
+	}
+
+	switch typ {
+	case models.QuestionTypeMultiSelect:
+		want := normalizedSet(spec.Correct)
+		got := normalizedSet(answer)
+		if len(want) == 0 || len(got) != len(want) {
+			return false
+		}
+		for k := range got {
+			if !want[k] {
+				return false
+			}
+		}
+		return true
+
+	case models.QuestionTypeNumeric:
+		if len(answer) != 1 || spec.Number == nil {
+			return false
+		}
+		v, ok := parseNumber(answer[0], spec.Unit)
+		return ok && math.Abs(v-*spec.Number) <= spec.Tolerance+numericEpsilon
+
+	case models.QuestionTypeFillBlank:
+		if len(answer) != len(spec.Blanks) {
+			return false
+		}
+		for i, accepted := range spec.Blanks {
+			if !normalizedSet(accepted)[normalize(answer[i])] {
+				return false
+			}
+		}
+		return true
+
+	case models.QuestionTypeMatching:
+		if len(answer) != len(spec.Pairs) {
+			return false
+		}
+		for i, p := range spec.Pairs {
+			if !IsCorrect(answer[i], p.Right) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// AnswerText renders the answer key of q for people: the review deck, the
+// revealed key of a submitted attempt and printed answer keys.
+func AnswerText(q models.Question) string {
+	spec := q.Answer
+	switch Type(q) {
+	case models.QuestionTypeTrueFalse:
+		if normalize(q.CorrectAnswer) == "true" {
+			return True
+		}
+		return False
+	case models.QuestionTypeMultiSelect:
+		if spec != nil {
+			return strings.Join(trimmed(spec.Correct), "; ")
+		}
+	case models.QuestionTypeNumeric:
+		if spec != nil && spec.Number != nil {
+			text := strconv.FormatFloat(*spec.Number, 'f', -1, 64)
+			if spec.Tolerance > 0 {
+				text += " ± " + strconv.FormatFloat(spec.Tolerance, 'f', -1, 64)
+			}
+			if unit := strings.TrimSpace(spec.Unit); unit != "" {
+				text += " " + unit
+			}
+			return text
+		}
+	case models.QuestionTypeFillBlank:
+		if spec != nil {
+			first := make([]string, len(spec.Blanks))
+			for i, accepted := range spec.Blanks {
+				if a := trimmed(accepted); len(a) > 0 {
+					first[i] = a[0]
+				}
+			}
+			return strings.Join(first, "; ")
+		}
+	case models.QuestionTypeMatching:
+		if spec != nil {
+			pairs := make([]string, len(spec.Pairs))
+			for i, p := range spec.Pairs {
+				pairs[i] = strings.TrimSpace(p.Left) + " = " + strings.TrimSpace(p.Right)
+			}
+			return strings.Join(pairs, "; ")
+		}
+	}
+	return strings.TrimSpace(q.CorrectAnswer)
+}
+
+// Normalize prepares a validated question for storage: it fills in the
+// default type, keeps CorrectAnswer readable for types keyed by their
+// answer spec and drops answer fields the type does not use.
+func Normalize(q *models.Question) {
+	q.Type = Type(*q)
+	switch q.Type {
+	case models.QuestionTypeSingleChoice:
+		q.Answer = nil
+	case models.QuestionTypeTrueFalse:
+		q.Answer = nil
+		q.OtherAnswers = models.StringArray{}
+	case models.QuestionTypeMultiSelect:
+	default:
+		q.OtherAnswers = models.StringArray{}
+	}
+	q.CorrectAnswer = AnswerText(*q)
+	if q.OtherAnswers == nil {
+		q.OtherAnswers = models.StringArray{}
+	}
+}
+
+// parseNumber reads a numeric answer, allowing the unit after it and a
+// decimal comma.
+func parseNumber(s, unit string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	if unit = strings.TrimSpace(unit); unit != "" && strings.HasSuffix(strings.ToLower(s), strings.ToLower(unit)) {
+		s = strings.TrimSpace(s[:len(s)-len(unit)])
+	}
+	if strings.Count(s, ",") == 1 && !strings.Contains(s, ".") {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil && !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+func normalizedSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		if k := normalize(v); k != "" {
+			set[k] = true
+		}
+	}
+	return set
+}
+
+func trimmed(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,105 @@
+package quiz
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+func num(f float64) *float64 { return &f }
+
+func TestCheckByType(t *testing.T) {
+	multi := &models.AnswerSpec{Correct: []string{"2", "3", "5"}}
+	numeric := &models.AnswerSpec{Number: num(9.8), Tolerance: 0.05, Unit: "m/s²"}
+	blanks := &models.AnswerSpec{Blanks: [][]string{{"oxygen", "O2"}, {"carbon dioxide", "CO2"}}}
+	matching := &models.AnswerSpec{Pairs: []models.MatchPair{{Left: "Na", Right: "Sodium"}, {Left: "K", Right: "Potassium"}}}
+
+	cases := []struct {
+		name    string
+		typ     string
+		correct string
+		spec    *models.AnswerSpec
+		answer  models.AnswerValue
+		want    bool
+	}{
+		{"legacy row", "", "20 cm", nil, models.AnswerValue{" 20 CM "}, true},
+		{"single choice needs one answer", models.QuestionTypeSingleChoice, "5", nil, models.AnswerValue{"5", "6"}, false},
+		{"true false", models.QuestionTypeTrueFalse, True, nil, models.AnswerValue{"true"}, true},
+		{"multi select in any order", models.QuestionTypeMultiSelect, "", multi, models.AnswerValue{"5", "2", "3"}, true},
+		{"multi select missing one", models.QuestionTypeMultiSelect, "", multi, models.AnswerValue{"2", "3"}, false},
+		{"multi select with extra", models.QuestionTypeMultiSelect, "", multi, models.AnswerValue{"2", "3", "5", "9"}, false},
+		{"numeric within tolerance", models.QuestionTypeNumeric, "", numeric, models.AnswerValue{"9.85"}, true},
+		{"numeric with unit and comma", models.QuestionTypeNumeric, "", numeric, models.AnswerValue{"9,78 m/s²"}, true},
+		{"numeric outside tolerance", models.QuestionTypeNumeric, "", numeric, models.AnswerValue{"9.9"}, false},
+		{"numeric not a number", models.QuestionTypeNumeric, "", numeric, models.AnswerValue{"ten"}, false},
+		{"fill blank alternatives", models.QuestionTypeFillBlank, "", blanks, models.AnswerValue{"O2", "Carbon Dioxide"}, true},
+		{"fill blank one wrong", models.QuestionTypeFillBlank, "", blanks, models.AnswerValue{"oxygen", "water"}, false},
+		{"fill blank too few", models.QuestionTypeFillBlank, "", blanks, models.AnswerValue{"oxygen"}, false},
+		{"matching", models.QuestionTypeMatching, "", matching, models.AnswerValue{"sodium", "potassium"}, true},
+		{"matching swapped", models.QuestionTypeMatching, "", matching, models.AnswerValue{"Potassium", "Sodium"}, false},
+		{"missing spec", models.QuestionTypeNumeric, "", nil, models.AnswerValue{"1"}, false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, Check(tc.typ, tc.correct, tc.spec, tc.answer), tc.name)
+	}
+}
+
+func TestBuildOptionsByType(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	options := BuildOptions(models.Question{Type: models.QuestionTypeMultiSelect,
+		Answer: &models.AnswerSpec{Correct: []string{"2", "3"}}, OtherAnswers: models.StringArray{"4", "3"}}, rng)
+	assert.ElementsMatch(t, []string{"2", "3", "4"}, []string(options))
+
+	assert.Equal(t, models.StringArray{True, False}, BuildOptions(models.Question{Type: models.QuestionTypeTrueFalse}, rng))
+	assert.Empty(t, BuildOptions(models.Question{Type: models.QuestionTypeNumeric}, rng))
+
+	options = BuildOptions(models.Question{Type: models.QuestionTypeMatching,
+		Answer: &models.AnswerSpec{Pairs: []models.MatchPair{{Left: "a", Right: "x"}, {Left: "b", Right: "y"}}}}, rng)
+	assert.ElementsMatch(t, []string{"x", "y"}, []string(options))
+}
+
+func TestNormalizeKeepsReadableKey(t *testing.T) {
+	q := models.Question{Type: models.QuestionTypeNumeric, OtherAnswers: models.StringArray{"stray"},
+		Answer: &models.AnswerSpec{Number: num(9.8), Tolerance: 0.1, Unit: "m/s²"}}
+	Normalize(&q)
+	assert.Equal(t, "9.8 ± 0.1 m/s²", q.CorrectAnswer)
+	assert.Empty(t, q.OtherAnswers)
+
+	legacy := models.Question{CorrectAnswer: " 5 ", Answer: &models.AnswerSpec{}}
+	Normalize(&legacy)
+	assert.Equal(t, models.QuestionTypeSingleChoice, legacy.Type)
+	assert.Nil(t, legacy.Answer)
+	assert.NotNil(t, legacy.OtherAnswers)
+}
+
+func TestAnswerValueJSON(t *testing.T) {
+	var answers []models.QuizAnswer
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"questionId": 1, "answer": "20 cm"},
+		{"questionId": 2, "answer": ["a", "b"]},
+		{"questionId": 3, "answer": 9.80},
+		{"questionId": 4, "answer": true}]`), &answers))
+	assert.Equal(t, models.AnswerValue{"20 cm"}, answers[0].Answer)
+	assert.Equal(t, models.AnswerValue{"a", "b"}, answers[1].Answer)
+	assert.Equal(t, models.AnswerValue{"9.80"}, answers[2].Answer)
+	assert.Equal(t, models.AnswerValue{"true"}, answers[3].Answer)
+
+	var bad models.QuizAnswer
+	assert.Error(t, json.Unmarshal([]byte(`{"answer": {"a": 1}}`), &bad))
+
+	out, err := json.Marshal(models.AnswerValue{"20 cm"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `"20 cm"`, string(out))
+
+	var stored models.AnswerValue
+	require.NoError(t, stored.Scan([]byte(`["a","b"]`)))
+	assert.Equal(t, models.AnswerValue{"a", "b"}, stored)
+	v, err := stored.Value()
+	require.NoError(t, err)
+	assert.Equal(t, `["a","b"]`, v)
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/auth"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+// AuditRepository reads the audit log. Entries are written by the
+// repositories making the changes, inside their own transactions.
+type AuditRepository interface {
+	// List returns entries newest first.
+	List(ctx context.Context, filter models.AuditFilter, page, pageSize int) ([]models.AuditEntry, int, error)
+}
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) List(ctx context.Context, filter models.AuditFilter, page, pageSize int) ([]models.AuditEntry, int, error) {
+	conds := []string{"1=1"}
+	args := []interface{}{}
+	if filter.ActorID != nil {
+		conds = append(conds, "actor_id = ?")
+		args = append(args, *filter.ActorID)
+	}
+	if filter.Action != "" {
+		conds = append(conds, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.EntityType != "" {
+		conds = append(conds, "entity_type = ?")
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		conds = append(conds, "entity_id = ?")
+		args = append(args, *filter.EntityID)
+	}
+	if filter.From != "" {
+		conds = append(conds, "created_at >= ?")
+		args = append(args, filter.From)
+	}
+	if filter.To != "" {
+		conds = append(conds, "created_at < DATE_ADD(?, INTERVAL 1 DAY)")
+		args = append(args, filter.To)
+	}
+	where := strings.Join(conds, " AND ")
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, actor_id, actor_role, action, entity_type, entity_id, before_json, after_json,
+		       DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at
+		FROM audit_log
+		WHERE `+where+`
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?`, append(args, pageSize, offsetFromPage(page, pageSize))...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.AuditEntry, 0)
+	for rows.Next() {
+		var e models.AuditEntry
+		var before, after []byte
+		if err := rows.Scan(&e.ID, &e.ActorID, &e.ActorRole, &e.Action, &e.EntityType, &e.EntityID,
+			&before, &after, &e.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		e.Before, e.After = before, after
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_log WHERE "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// auditedChange runs change in a transaction together with its audit log
+// entry, snapshotting the row before and after. id is 0 for creates, whose
+// change returns the new id; for updates and deletes a missing row is
+// reported as notFound.
+func auditedChange(ctx context.Context, db *sql.DB, notFound error, action, entity, table string, id int64, change func(tx *sql.Tx) (int64, error)) (int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var before json.RawMessage
+	if id != 0 {
+		if before, err = snapshotRow(ctx, tx, table, id); err != nil {
+			return 0, err
+		}
+		if before == nil {
+			return 0, notFound
+		}
+	}
+	if id, err = change(tx); err != nil {
+		return 0, err
+	}
+	if err := recordRowChange(ctx, tx, action, entity, table, id, before); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// recordRowChange records a change to a row whose earlier state was before,
+// taking its state after the change from the table unless it was deleted.
+func recordRowChange(ctx context.Context, tx *sql.Tx, action, entity, table string, id int64, before json.RawMessage) error {
+	var after json.RawMessage
+	if action != models.AuditDelete {
+		var err error
+		if after, err = snapshotRow(ctx, tx, table, id); err != nil {
+			return err
+		}
+	}
+	return recordAudit(ctx, tx, action, entity, id, before, after)
+}
+
+// recordAudit writes an audit entry for the caller found on ctx, using the
+// transaction that made the change.
+func recordAudit(ctx context.Context, tx *sql.Tx, action, entity string, id int64, before, after json.RawMessage) error {
+	var actorID *int
+	var actorRole *string
+	if claims := auth.ClaimsFromContext(ctx); claims != nil {
+		actorID, actorRole = &claims.UserID, &claims.Role
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO audit_log (actor_id, actor_role, action, entity_type, entity_id, before_json, after_json)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		actorID, actorRole, action, entity, id, nullableJSON(before), nullableJSON(after))
+	return err
+}
+
+// auditRedacted lists the columns per table that are never copied into the
+// audit log.
+var auditRedacted = map[string][]string{
+	"users": {"password_hash"},
+}
+
+// snapshotRow returns the row of table with the given id as a JSON object,
+// or nil when there is none. The row is locked until the transaction ends.
+// Columns holding JSON are embedded as JSON rather than as strings.
+func snapshotRow(ctx context.Context, tx *sql.Tx, table string, id int64) (json.RawMessage, error) {
+	return snapshotWhere(ctx, tx, table, "id = ?", id)
+}
+
+// snapshotWhere is snapshotRow for tables keyed other than by id; where
+// must match at most one row.
+func snapshotWhere(ctx context.Context, tx *sql.Tx, table, where string, args ...interface{}) (json.RawMessage, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT * FROM "+table+" WHERE "+where+" FOR UPDATE", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(columns))
+	for i := range values {
+		values[i] = new(interface{})
+	}
+	if err := rows.Scan(values...); err != nil {
+		return nil, err
+	}
+	row := make(map[string]interface{}, len(columns))
+	for i, name := range columns {
+		v := *(values[i].(*interface{}))
+		if b, ok := v.([]byte); ok {
+			if len(b) > 0 && (b[0] == '{' || b[0] == '[') && json.Valid(b) {
+				v = json.RawMessage(b)
+			} else {
+				v = string(b)
+			}
+		}
+		row[name] = v
+	}
+	for _, name := range auditRedacted[table] {
+		delete(row, name)
+	}
+	out, err := json.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+	return out, rows.Err()
+}
+
+func nullableJSON(v json.RawMessage) interface{} {
+	if v == nil {
+		return nil
+	}
+	return string(v)
+}
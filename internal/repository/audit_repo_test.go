@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+// auditActions lists the actions recorded against one entity, newest first.
+func auditActions(t *testing.T, audit AuditRepository, entity string, id int64) ([]string, []models.AuditEntry) {
+	t.Helper()
+	entries, _, err := audit.List(context.Background(), models.AuditFilter{EntityType: entity, EntityID: &id}, 1, 10)
+	require.NoError(t, err)
+	actions := make([]string, 0, len(entries))
+	for _, e := range entries {
+		actions = append(actions, e.Action)
+	}
+	return actions, entries
+}
+
+func TestUserChangesAreAuditedWithoutPasswords(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+
+	u := &models.User{Email: fmt.Sprintf("%d@example.com", time.Now().UnixNano()), Name: "Nimal", PasswordHash: "secret-hash", Role: models.RoleStudent}
+	id, err := users.Create(ctx, u)
+	require.NoError(t, err)
+	u.ID = int(id)
+	u.Role = models.RoleTutor
+	require.NoError(t, users.Update(ctx, u))
+	u.ID = 0
+	assert.ErrorIs(t, users.Update(ctx, u), ErrUserNotFound)
+
+	actions, entries := auditActions(t, NewAuditRepository(db), models.EntityUser, id)
+	assert.Equal(t, []string{models.AuditUpdate, models.AuditCreate}, actions)
+	for _, e := range entries {
+		assert.NotContains(t, string(e.Before), "password_hash")
+		assert.NotContains(t, string(e.After), "password_hash")
+	}
+	assert.Contains(t, string(entries[0].After), `"role":"tutor"`)
+}
+
+func TestTranslationChangesAreAudited(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	translations := NewTranslationRepository(db)
+
+	grade, _ := testLesson(t, NewMenuConfigRepository(db))
+	for _, name := range []string{"Shreniya", "Shreni"} {
+		name := name
+		_, err := translations.Upsert(ctx, models.Translation{Entity: models.EntityGrade, EntityID: grade.ID, Lang: "si",
+			TranslationFields: models.TranslationFields{Name: &name}})
+		require.NoError(t, err)
+	}
+	require.NoError(t, translations.Delete(ctx, models.EntityGrade, grade.ID, "si"))
+	assert.ErrorIs(t, translations.Delete(ctx, models.EntityGrade, grade.ID, "si"), ErrTranslationNotFound)
+
+	actions, entries := auditActions(t, NewAuditRepository(db), models.EntityGrade+"_translation", grade.ID)
+	assert.Equal(t, []string{models.AuditDelete, models.AuditUpdate, models.AuditCreate}, actions)
+	assert.Contains(t, string(entries[1].Before), "Shreniya")
+	assert.Contains(t, string(entries[1].After), "Shreni")
+}
+
+func TestBlueprintChangesAreAudited(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	exams := NewExamRepository(db)
+
+	bp := &models.ExamBlueprint{Name: "Term test", TotalQuestions: 10, DurationMinutes: 30, LatePolicy: "mark"}
+	id, err := exams.CreateBlueprint(ctx, bp)
+	require.NoError(t, err)
+	bp.ID = int(id)
+	bp.DurationMinutes = 45
+	require.NoError(t, exams.UpdateBlueprint(ctx, bp))
+	require.NoError(t, exams.DeleteBlueprint(ctx, bp.ID))
+	assert.ErrorIs(t, exams.DeleteBlueprint(ctx, bp.ID), ErrExamBlueprintNotFound)
+
+	actions, _ := auditActions(t, NewAuditRepository(db), models.EntityExamBlueprint, id)
+	assert.Equal(t, []string{models.AuditDelete, models.AuditUpdate, models.AuditCreate}, actions)
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/irt"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+// CalibrationRepository reads the answers question statistics are fitted
+// from and stores the results on the questions.
+type CalibrationRepository interface {
+	Responses(ctx context.Context) ([]irt.Response, error)
+	Save(ctx context.Context, stats []irt.ItemStats) error
+	Report(ctx context.Context, filter models.CalibrationFilter, page, pageSize int) ([]models.QuestionCalibration, int, error)
+}
+
+type calibrationRepository struct {
+	db *sql.DB
+}
+
+func NewCalibrationRepository(db *sql.DB) CalibrationRepository {
+	return &calibrationRepository{db: db}
+}
+
+// Responses returns every answered question of every submitted quiz attempt,
+// with the attempt as the person. Questions left blank are not counted, and
+// practice answers are left out because practice picks questions by the
+// student's weaknesses.
+func (r *calibrationRepository) Responses(ctx context.Context) ([]irt.Response, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT qa.attempt_id, qa.question_id, qa.is_correct
+		FROM quiz_attempt_answers qa
+			INNER JOIN quiz_attempts a ON a.id = qa.attempt_id
+		WHERE a.status = ? AND qa.selected_answer IS NOT NULL AND qa.is_correct IS NOT NULL`,
+		models.QuizStatusSubmitted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []irt.Response
+	for rows.Next() {
+		var resp irt.Response
+		if err := rows.Scan(&resp.Person, &resp.Item, &resp.Correct); err != nil {
+			return nil, err
+		}
+		out = append(out, resp)
+	}
+	return out, rows.Err()
+}
+
+// Save replaces the statistics of every question in one transaction.
+// Questions without answers are reset, so nothing stale survives.
+func (r *calibrationRepository) Save(ctx context.Context, stats []irt.ItemStats) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE questions
+		SET response_count = 0, p_value = NULL, point_biserial = NULL, difficulty = NULL, discrimination = NULL,
+		    calibrated_at = CURRENT_TIMESTAMP`); err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		UPDATE questions
+		SET response_count = ?, p_value = ?, point_biserial = ?, difficulty = ?, discrimination = ?
+		WHERE id = ?`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, s := range stats {
+		if _, err := stmt.ExecContext(ctx, s.Responses, s.PValue, s.PointBiserial, s.Difficulty, s.Discrimination, s.Item); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Report lists the questions with at least filter.MinResponses answers whose
+// statistics raise a review flag, the most answered first.
+func (r *calibrationRepository) Report(ctx context.Context, filter models.CalibrationFilter, page, pageSize int) ([]models.QuestionCalibration, int, error) {
+	where := "response_count >= ?"
+	args := []interface{}{filter.MinResponses}
+	if filter.LessonID > 0 {
+		where += " AND lesson_id = ?"
+		args = append(args, filter.LessonID)
+	}
+
+	negative := "(point_biserial < 0 OR discrimination < 0)"
+	switch filter.Flag {
+	case irt.FlagTooEasy:
+		where += " AND p_value > ?"
+		args = append(args, irt.TooEasyAbove)
+	case irt.FlagTooHard:
+		where += " AND p_value < ?"
+		args = append(args, irt.TooHardBelow)
+	case irt.FlagNegativeDiscrimination:
+		where += " AND " + negative
+	default:
+		where += " AND (p_value > ? OR p_value < ? OR " + negative + ")"
+		args = append(args, irt.TooEasyAbove, irt.TooHardBelow)
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM questions WHERE "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, lesson_id, question_type, question, correct_answer,
+		       response_count, p_value, point_biserial, difficulty, discrimination,
+		       DATE_FORMAT(calibrated_at, '%Y-%m-%dT%H:%i:%sZ')
+		FROM questions
+		WHERE `+where+`
+		ORDER BY response_count DESC, id ASC
+		LIMIT ? OFFSET ?`, append(args, pageSize, offsetFromPage(page, pageSize))...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]models.QuestionCalibration, 0)
+	for rows.Next() {
+		var q models.QuestionCalibration
+		if err := rows.Scan(&q.QuestionID, &q.LessonID, &q.Type, &q.Question, &q.CorrectAnswer,
+			&q.ResponseCount, &q.PValue, &q.PointBiserial, &q.Difficulty, &q.Discrimination, &q.CalibratedAt); err != nil {
+			return nil, 0, err
+		}
+		q.Flags = irt.Flags(q.PValue, q.PointBiserial, q.Discrimination)
+		out = append(out, q)
+	}
+	return out, total, rows.Err()
+}
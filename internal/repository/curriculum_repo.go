@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/curriculum"
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+)
+
+type CurriculumRepositoryInterface interface {
+	LoadTree(ctx context.Context, gradeID, subjectID *int64, withCounts bool) (*curriculum.Flat, error)
+}
+
+// CurriculumRepository loads the curriculum tree with one query per level,
+// plus one per count, however many nodes the tree has.
+type CurriculumRepository struct {
+	db *sql.DB
+}
+
+func NewCurriculumRepository(db *sql.DB) *CurriculumRepository {
+	return &CurriculumRepository{db: db}
+}
+
+// LoadTree loads the subtree of one subject when subjectID is set, of one
+// grade when gradeID is set, and the whole curriculum otherwise. A subject
+// is returned under its grade so the response always starts at grades.
+func (r *CurriculumRepository) LoadTree(ctx context.Context, gradeID, subjectID *int64, withCounts bool) (*curriculum.Flat, error) {
+	// every query below joins subjects as s, so one filter scopes them all;
+	// trashed rows take their whole subtree out of the tree with them
+	where, arg := "s.deleted_at IS NULL", interface{}(nil)
+	gradesQuery := "SELECT g.id, 0, g.name FROM grades g WHERE g.deleted_at IS NULL"
+	switch {
+	case subjectID != nil:
+		where, arg = "s.deleted_at IS NULL AND s.id = ?", *subjectID
+		gradesQuery = "SELECT g.id, 0, g.name FROM grades g INNER JOIN subjects s ON s.grade_id = g.id WHERE g.deleted_at IS NULL AND " + where
+	case gradeID != nil:
+		where, arg = "s.deleted_at IS NULL AND s.grade_id = ?", *gradeID
+		gradesQuery += " AND g.id = ?"
+	}
+	var args []interface{}
+	if arg != nil {
+		args = []interface{}{arg}
+	}
+
+	var f curriculum.Flat
+	var err error
+	if f.Grades, err = r.rows(ctx, gradesQuery+" ORDER BY g.id", args); err != nil {
+		return nil, err
+	}
+	if f.Subjects, err = r.rows(ctx, `
+		SELECT s.id, s.grade_id, s.name FROM subjects s
+		WHERE `+where+` ORDER BY s.id`, args); err != nil {
+		return nil, err
+	}
+	if f.Lessons, err = r.rows(ctx, `
+		SELECT l.id, l.subject_id, l.name FROM lessons l
+			INNER JOIN subjects s ON s.id = l.subject_id
+		WHERE l.deleted_at IS NULL AND `+where+` ORDER BY l.id`, args); err != nil {
+		return nil, err
+	}
+	if f.Topics, err = r.rows(ctx, `
+		SELECT t.id, t.lesson_id, t.name FROM topics t
+			INNER JOIN lessons l ON l.id = t.lesson_id
+			INNER JOIN subjects s ON s.id = l.subject_id
+		WHERE t.deleted_at IS NULL AND l.deleted_at IS NULL AND `+where+` ORDER BY t.created_at, t.id`, args); err != nil {
+		return nil, err
+	}
+	if f.Subtopics, err = r.rows(ctx, `
+		SELECT st.id, st.topic_id, st.name FROM subtopics st
+			INNER JOIN topics t ON t.id = st.topic_id
+			INNER JOIN lessons l ON l.id = t.lesson_id
+			INNER JOIN subjects s ON s.id = l.subject_id
+		WHERE st.deleted_at IS NULL AND t.deleted_at IS NULL AND l.deleted_at IS NULL AND `+where+`
+		ORDER BY st.created_at, st.id`, args); err != nil {
+		return nil, err
+	}
+	if !withCounts {
+		return &f, nil
+	}
+
+	// only published content under live lessons, topics and subtopics counts
+	countArgs := append(append([]interface{}{}, args...), editorial.StatusPublished)
+	for _, c := range []struct {
+		table string
+		dest  *[]curriculum.CountRow
+	}{{"questions", &f.Questions}, {"smart_notes", &f.SmartNotes}} {
+		counts, err := r.counts(ctx, `
+			SELECT x.lesson_id, x.topic_id, x.subtopic_id, COUNT(*) FROM `+c.table+` x
+				INNER JOIN lessons l ON l.id = x.lesson_id
+				INNER JOIN subjects s ON s.id = l.subject_id
+			WHERE `+where+` AND x.status = ? AND l.deleted_at IS NULL AND `+liveParents("x")+`
+			GROUP BY x.lesson_id, x.topic_id, x.subtopic_id`, countArgs)
+		if err != nil {
+			return nil, err
+		}
+		*c.dest = counts
+	}
+	return &f, nil
+}
+
+func (r *CurriculumRepository) rows(ctx context.Context, query string, args []interface{}) ([]curriculum.Row, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]curriculum.Row, 0)
+	for rows.Next() {
+		var row curriculum.Row
+		if err := rows.Scan(&row.ID, &row.ParentID, &row.Name); err != nil {
+			return nil, err
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
+
+func (r *CurriculumRepository) counts(ctx context.Context, query string, args []interface{}) ([]curriculum.CountRow, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]curriculum.CountRow, 0)
+	for rows.Next() {
+		var row curriculum.CountRow
+		if err := rows.Scan(&row.LessonID, &row.TopicID, &row.SubtopicID, &row.N); err != nil {
+			return nil, err
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+func TestTreeCountsOnlyLivePublishedQuestions(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	menu := NewMenuConfigRepository(db)
+	questions := NewQuestionRepository(db)
+
+	grade, lesson := testLesson(t, menu)
+	topic, err := menu.CreateTopic(ctx, TopicUpsert{LessonID: lesson.ID, Name: "Adding"})
+	require.NoError(t, err)
+	topicID := int(topic.ID)
+	for _, q := range []struct {
+		topicID *int
+		status  string
+	}{{nil, editorial.StatusPublished}, {nil, editorial.StatusDraft}, {&topicID, editorial.StatusPublished}} {
+		id, err := questions.Create(ctx, &models.Question{
+			GradeID: int(grade.ID), LessonID: int(lesson.ID), TopicID: q.topicID, Question: "1/2 + 1/2 = ?", CorrectAnswer: "1",
+		})
+		require.NoError(t, err)
+		_, err = db.ExecContext(ctx, "UPDATE questions SET status = ? WHERE id = ?", q.status, id)
+		require.NoError(t, err)
+	}
+	require.NoError(t, menu.DeleteTopic(ctx, topic.ID, true))
+
+	gradeID := grade.ID
+	flat, err := NewCurriculumRepository(db).LoadTree(ctx, &gradeID, nil, true)
+	require.NoError(t, err)
+	require.Len(t, flat.Questions, 1)
+	assert.Nil(t, flat.Questions[0].TopicID)
+	assert.Equal(t, 1, flat.Questions[0].N)
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+var ErrEditorialContentNotFound = errors.New("editorial: content not found")
+
+// editorialTables maps each kind of reviewed content to its table.
+var editorialTables = map[string]string{
+	editorial.ContentQuestion:  "questions",
+	editorial.ContentSmartNote: "smart_notes",
+}
+
+type EditorialRepository interface {
+	// Status returns the current status of a question or smart note.
+	Status(ctx context.Context, contentType string, contentID int) (string, error)
+	// Transition moves content to another status if allow accepts the
+	// status it is in, and records the change with who made it.
+	Transition(ctx context.Context, contentType string, contentID int, to string, comment *string, userID *int, allow func(from string) error) (*models.EditorialEvent, error)
+	Comment(ctx context.Context, contentType string, contentID int, comment string, userID *int) (*models.EditorialEvent, error)
+	// History lists status changes and comments, oldest first.
+	History(ctx context.Context, contentType string, contentID int) ([]models.EditorialEvent, error)
+}
+
+type editorialRepository struct {
+	db *sql.DB
+}
+
+func NewEditorialRepository(db *sql.DB) EditorialRepository {
+	return &editorialRepository{db: db}
+}
+
+func (r *editorialRepository) Status(ctx context.Context, contentType string, contentID int) (string, error) {
+	var status string
+	err := r.db.QueryRowContext(ctx, "SELECT status FROM "+editorialTables[contentType]+" WHERE id = ?", contentID).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrEditorialContentNotFound
+	}
+	return status, err
+}
+
+func (r *editorialRepository) Transition(ctx context.Context, contentType string, contentID int, to string, comment *string, userID *int, allow func(from string) error) (*models.EditorialEvent, error) {
+	table := editorialTables[contentType]
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var from string
+	if err := tx.QueryRowContext(ctx, "SELECT status FROM "+table+" WHERE id = ? FOR UPDATE", contentID).Scan(&from); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrEditorialContentNotFound
+		}
+		return nil, err
+	}
+	if err := allow(from); err != nil {
+		return nil, err
+	}
+	before, err := snapshotRow(ctx, tx, table, int64(contentID))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE "+table+" SET status = ? WHERE id = ?", to, contentID); err != nil {
+		return nil, err
+	}
+	if err := recordRowChange(ctx, tx, models.AuditUpdate, contentType, table, int64(contentID), before); err != nil {
+		return nil, err
+	}
+	id, err := insertEditorialEvent(ctx, tx, contentType, contentID, &from, &to, comment, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.event(ctx, id)
+}
+
+func (r *editorialRepository) Comment(ctx context.Context, contentType string, contentID int, comment string, userID *int) (*models.EditorialEvent, error) {
+	if _, err := r.Status(ctx, contentType, contentID); err != nil {
+		return nil, err
+	}
+	id, err := insertEditorialEvent(ctx, r.db, contentType, contentID, nil, nil, &comment, userID)
+	if err != nil {
+		return nil, err
+	}
+	return r.event(ctx, id)
+}
+
+const editorialEventColumns = `id, content_type, content_id, from_status, to_status, comment, user_id,
+	DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at`
+
+func scanEditorialEvent(row interface{ Scan(...interface{}) error }) (models.EditorialEvent, error) {
+	var e models.EditorialEvent
+	err := row.Scan(&e.ID, &e.ContentType, &e.ContentID, &e.FromStatus, &e.ToStatus, &e.Comment, &e.UserID, &e.CreatedAt)
+	return e, err
+}
+
+func (r *editorialRepository) History(ctx context.Context, contentType string, contentID int) ([]models.EditorialEvent, error) {
+	if _, err := r.Status(ctx, contentType, contentID); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, "SELECT "+editorialEventColumns+` FROM editorial_events
+		WHERE content_type = ? AND content_id = ? ORDER BY id`, contentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]models.EditorialEvent, 0)
+	for rows.Next() {
+		e, err := scanEditorialEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (r *editorialRepository) event(ctx context.Context, id int64) (*models.EditorialEvent, error) {
+	e, err := scanEditorialEvent(r.db.QueryRowContext(ctx, "SELECT "+editorialEventColumns+" FROM editorial_events WHERE id = ?", id))
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// insertEditorialEvent takes a *sql.DB or, for status changes, the *sql.Tx
+// making the change.
+func insertEditorialEvent(ctx context.Context, db interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}, contentType string, contentID int, from, to, comment *string, userID *int) (int64, error) {
+	res, err := db.ExecContext(ctx, `
+		INSERT INTO editorial_events (content_type, content_id, from_status, to_status, comment, user_id)
+		VALUES (?, ?, ?, ?, ?, ?)`, contentType, contentID, from, to, comment, userID)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+var ErrExamBlueprintNotFound = errors.New("exam: blueprint not found")
+
+type ExamRepository interface {
+	ListBlueprints(ctx context.Context, page, pageSize int) ([]models.ExamBlueprint, int, error)
+	GetBlueprint(ctx context.Context, id int) (*models.ExamBlueprint, error)
+	CreateBlueprint(ctx context.Context, bp *models.ExamBlueprint) (int64, error)
+	UpdateBlueprint(ctx context.Context, bp *models.ExamBlueprint) error
+	DeleteBlueprint(ctx context.Context, id int) error
+}
+
+type examRepository struct {
+	db *sql.DB
+}
+
+func NewExamRepository(db *sql.DB) ExamRepository {
+	return &examRepository{db: db}
+}
+
+const blueprintSelect = `
+	SELECT id, name, grade_id, total_questions, duration_minutes, late_policy, created_by,
+	       DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at
+	FROM exam_blueprints`
+
+func scanBlueprint(s interface{ Scan(...interface{}) error }) (models.ExamBlueprint, error) {
+	var bp models.ExamBlueprint
+	err := s.Scan(&bp.ID, &bp.Name, &bp.GradeID, &bp.TotalQuestions, &bp.DurationMinutes, &bp.LatePolicy,
+		&bp.CreatedBy, &bp.CreatedAt)
+	return bp, err
+}
+
+// ListBlueprints returns the newest blueprints first, with their sections.
+func (r *examRepository) ListBlueprints(ctx context.Context, page, pageSize int) ([]models.ExamBlueprint, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM exam_blueprints").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, blueprintSelect+" ORDER BY id DESC LIMIT ? OFFSET ?",
+		pageSize, offsetFromPage(page, pageSize))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	blueprints := make([]models.ExamBlueprint, 0)
+	for rows.Next() {
+		bp, err := scanBlueprint(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		blueprints = append(blueprints, bp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadSections(ctx, blueprints); err != nil {
+		return nil, 0, err
+	}
+	return blueprints, total, nil
+}
+
+func (r *examRepository) GetBlueprint(ctx context.Context, id int) (*models.ExamBlueprint, error) {
+	bp, err := scanBlueprint(r.db.QueryRowContext(ctx, blueprintSelect+" WHERE id = ?", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrExamBlueprintNotFound
+		}
+		return nil, err
+	}
+	blueprints := []models.ExamBlueprint{bp}
+	if err := r.loadSections(ctx, blueprints); err != nil {
+		return nil, err
+	}
+	return &blueprints[0], nil
+}
+
+// loadSections fills in the sections of all blueprints with one query.
+func (r *examRepository) loadSections(ctx context.Context, blueprints []models.ExamBlueprint) error {
+	if len(blueprints) == 0 {
+		return nil
+	}
+	byID := make(map[int]*models.ExamBlueprint, len(blueprints))
+	args := make([]interface{}, 0, len(blueprints))
+	for i := range blueprints {
+		blueprints[i].Sections = make([]models.ExamSection, 0)
+		byID[blueprints[i].ID] = &blueprints[i]
+		args = append(args, blueprints[i].ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT blueprint_id, name, lesson_id, topic_id, subtopic_id, COALESCE(difficulty, ''), question_count, percent
+		FROM exam_blueprint_sections
+		WHERE blueprint_id IN (?`+strings.Repeat(", ?", len(args)-1)+`)
+		ORDER BY blueprint_id, position`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var blueprintID int
+		var s models.ExamSection
+		if err := rows.Scan(&blueprintID, &s.Name, &s.LessonID, &s.TopicID, &s.SubtopicID, &s.Difficulty, &s.Count, &s.Percent); err != nil {
+			return err
+		}
+		if bp, ok := byID[blueprintID]; ok {
+			bp.Sections = append(bp.Sections, s)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *examRepository) CreateBlueprint(ctx context.Context, bp *models.ExamBlueprint) (int64, error) {
+	return auditedChange(ctx, r.db, ErrExamBlueprintNotFound, models.AuditCreate, models.EntityExamBlueprint, "exam_blueprints", 0, func(tx *sql.Tx) (int64, error) {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO exam_blueprints (name, grade_id, total_questions, duration_minutes, late_policy, created_by)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			strings.TrimSpace(bp.Name), bp.GradeID, bp.TotalQuestions, bp.DurationMinutes, bp.LatePolicy, bp.CreatedBy)
+		if err != nil {
+			return 0, err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return 0, err
+		}
+		return id, insertSections(ctx, tx, id, bp.Sections)
+	})
+}
+
+// UpdateBlueprint replaces the blueprint and all of its sections.
+func (r *examRepository) UpdateBlueprint(ctx context.Context, bp *models.ExamBlueprint) error {
+	_, err := auditedChange(ctx, r.db, ErrExamBlueprintNotFound, models.AuditUpdate, models.EntityExamBlueprint, "exam_blueprints", int64(bp.ID), func(tx *sql.Tx) (int64, error) {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE exam_blueprints
+			SET name = ?, grade_id = ?, total_questions = ?, duration_minutes = ?, late_policy = ?
+			WHERE id = ?`,
+			strings.TrimSpace(bp.Name), bp.GradeID, bp.TotalQuestions, bp.DurationMinutes, bp.LatePolicy, bp.ID); err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM exam_blueprint_sections WHERE blueprint_id = ?", bp.ID); err != nil {
+			return 0, err
+		}
+		return int64(bp.ID), insertSections(ctx, tx, int64(bp.ID), bp.Sections)
+	})
+	return err
+}
+
+func (r *examRepository) DeleteBlueprint(ctx context.Context, id int) error {
+	_, err := auditedChange(ctx, r.db, ErrExamBlueprintNotFound, models.AuditDelete, models.EntityExamBlueprint, "exam_blueprints", int64(id), func(tx *sql.Tx) (int64, error) {
+		_, err := tx.ExecContext(ctx, "DELETE FROM exam_blueprints WHERE id = ?", id)
+		return int64(id), err
+	})
+	return err
+}
+
+func insertSections(ctx context.Context, tx *sql.Tx, blueprintID int64, sections []models.ExamSection) error {
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO exam_blueprint_sections (blueprint_id, position, name, lesson_id, topic_id, subtopic_id, difficulty, question_count, percent)
+		VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, s := range sections {
+		if _, err := stmt.ExecContext(ctx, blueprintID, i+1, strings.TrimSpace(s.Name),
+			s.LessonID, s.TopicID, s.SubtopicID, s.Difficulty, s.Count, s.Percent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
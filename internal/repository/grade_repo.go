@@ -15,7 +15,7 @@ func NewGradeRepository(db *sql.DB) *GradeRepository {
 }
 
 func (r *GradeRepository) GetAllGrades() ([]models.Grade, error) {
-	rows, err := r.DB.Query("SELECT id, grade FROM grades")
+	rows, err := r.DB.Query("SELECT id, grade FROM grades WHERE deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
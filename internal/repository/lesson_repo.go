@@ -24,7 +24,7 @@ func NewLessonRepository(db *sql.DB) LessonRepositoryInterface {
 }
 
 func (r *LessonRepository) GetLessonsBySubject(subjectId int) ([]Lesson, error) {
-	rows, err := r.DB.Query("SELECT id, name, image_url FROM lessons WHERE subject_id = ?", subjectId)
+	rows, err := r.DB.Query("SELECT id, name, image_url FROM lessons WHERE subject_id = ? AND deleted_at IS NULL", subjectId)
 	if err != nil {
 		return nil, fmt.Errorf("could not fetch lessons: %w", err)
 	}
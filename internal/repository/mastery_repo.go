@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+	"github.com/tharindulakmal/sl-edu-service/internal/mastery"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+type MasteryRepository interface {
+	// PracticeCandidates lists the published questions of a lesson with their
+	// subtopic.
+	PracticeCandidates(ctx context.Context, lessonID int) ([]mastery.Candidate, error)
+	// LessonMastery returns the student's estimate for every subtopic of a
+	// lesson, using the model's prior for subtopics not practised yet.
+	LessonMastery(ctx context.Context, studentID, lessonID int) ([]models.SubtopicMastery, error)
+	// RecentQuestions returns the questions the student answered last, newest first.
+	RecentQuestions(ctx context.Context, studentID, limit int) ([]int, error)
+	// RecordAnswers logs graded answers and updates the mastery of their
+	// subtopics, returning the updated estimates.
+	RecordAnswers(ctx context.Context, studentID int, answers []models.GradedAnswer) ([]models.SubtopicMastery, error)
+}
+
+type masteryRepository struct {
+	db    *sql.DB
+	model mastery.Model
+}
+
+func NewMasteryRepository(db *sql.DB, model mastery.Model) MasteryRepository {
+	return &masteryRepository{db: db, model: model}
+}
+
+func (r *masteryRepository) PracticeCandidates(ctx context.Context, lessonID int) ([]mastery.Candidate, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, subtopic_id FROM questions
+		WHERE lesson_id = ? AND status = ? AND `+liveParents("questions"), lessonID, editorial.StatusPublished)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []mastery.Candidate
+	for rows.Next() {
+		var c mastery.Candidate
+		if err := rows.Scan(&c.QuestionID, &c.SubtopicID); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (r *masteryRepository) LessonMastery(ctx context.Context, studentID, lessonID int) ([]models.SubtopicMastery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT st.id, st.name, sm.p_known, COALESCE(sm.attempts, 0), COALESCE(sm.correct, 0)
+		FROM subtopics st
+			INNER JOIN topics t ON t.id = st.topic_id
+			LEFT JOIN student_mastery sm ON sm.subtopic_id = st.id AND sm.student_id = ?
+		WHERE t.lesson_id = ? AND st.deleted_at IS NULL AND t.deleted_at IS NULL
+		ORDER BY st.id`, studentID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.SubtopicMastery, 0)
+	for rows.Next() {
+		var m models.SubtopicMastery
+		var pKnown sql.NullFloat64
+		if err := rows.Scan(&m.SubtopicID, &m.SubtopicName, &pKnown, &m.Attempts, &m.Correct); err != nil {
+			return nil, err
+		}
+		m.PKnown = r.model.PInit
+		if pKnown.Valid {
+			m.PKnown = pKnown.Float64
+		}
+		m.Mastered = m.PKnown >= mastery.Mastered
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func (r *masteryRepository) RecentQuestions(ctx context.Context, studentID, limit int) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT question_id FROM student_answers
+		WHERE student_id = ?
+		ORDER BY id DESC LIMIT ?`, studentID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// RecordAnswers applies the answers in order inside one transaction, locking
+// each mastery row so concurrent submissions do not lose updates. Answers to
+// questions that no longer exist are skipped.
+func (r *masteryRepository) RecordAnswers(ctx context.Context, studentID int, answers []models.GradedAnswer) ([]models.SubtopicMastery, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	updated := map[int]*models.SubtopicMastery{}
+	var order []int
+	for _, a := range answers {
+		var subtopicID *int
+		err := tx.QueryRowContext(ctx, `SELECT subtopic_id FROM questions WHERE id = ?`, a.QuestionID).Scan(&subtopicID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO student_answers (student_id, question_id, subtopic_id, is_correct)
+			VALUES (?, ?, ?, ?)`, studentID, a.QuestionID, subtopicID, a.Correct); err != nil {
+			return nil, err
+		}
+		if subtopicID == nil {
+			continue
+		}
+
+		m := models.SubtopicMastery{SubtopicID: *subtopicID, PKnown: r.model.PInit}
+		err = tx.QueryRowContext(ctx, `
+			SELECT p_known, attempts, correct FROM student_mastery
+			WHERE student_id = ? AND subtopic_id = ? FOR UPDATE`, studentID, *subtopicID,
+		).Scan(&m.PKnown, &m.Attempts, &m.Correct)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		m.PKnown = r.model.Update(m.PKnown, a.Correct)
+		m.Attempts++
+		if a.Correct {
+			m.Correct++
+		}
+		m.Mastered = m.PKnown >= mastery.Mastered
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO student_mastery (student_id, subtopic_id, p_known, attempts, correct)
+			VALUES (?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE p_known = VALUES(p_known), attempts = VALUES(attempts), correct = VALUES(correct)`,
+			studentID, m.SubtopicID, m.PKnown, m.Attempts, m.Correct); err != nil {
+			return nil, err
+		}
+
+		if _, ok := updated[m.SubtopicID]; !ok {
+			order = append(order, m.SubtopicID)
+		}
+		updated[m.SubtopicID] = &m
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	out := make([]models.SubtopicMastery, 0, len(order))
+	for _, id := range order {
+		out = append(out, *updated[id])
+	}
+	return out, nil
+}
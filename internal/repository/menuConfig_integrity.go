@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// MissingParentError is returned when an upsert points at a parent that does
+// not exist or is in the trash. Field is the JSON name of the parent id.
+type MissingParentError struct {
+	Field  string
+	Entity string
+}
+
+func (e *MissingParentError) Error() string {
+	return fmt.Sprintf("menuconfig: %s not found", e.Entity)
+}
+
+// DuplicateError is returned when an upsert or restore collides with another
+// live row on one of the uq_* unique indexes. Field is the JSON name of the
+// colliding field.
+type DuplicateError struct {
+	Key   string
+	Field string
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("menuconfig: %s already exists", e.Field)
+}
+
+// uniqueKeyFields maps the unique indexes of the menu config tables to the
+// request field a clash on them is reported against. The other columns of
+// the composite keys are parent ids, so the clash is always on the name
+// within that parent.
+var uniqueKeyFields = map[string]string{
+	"uq_grades_name":          "name",
+	"uq_subjects_grade_name":  "name",
+	"uq_lessons_subject_name": "name",
+	"uq_topics_lesson_name":   "name",
+	"uq_subtopics_topic_name": "name",
+	"uq_years_value":          "value",
+	"uq_past_papers_paper":    "paperNumber",
+}
+
+// duplicateKeyName picks the index out of a MySQL 1062 message, which is
+// "for key 'table.uq_name'" on MySQL 8 and "for key 'uq_name'" before.
+var duplicateKeyName = regexp.MustCompile(`for key '(?:[^'.]*\.)?([^']+)'`)
+
+// asDuplicate turns a duplicate-key error on one of the known unique indexes
+// into a *DuplicateError and returns any other error unchanged.
+func asDuplicate(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != 1062 {
+		return err
+	}
+	m := duplicateKeyName.FindStringSubmatch(mysqlErr.Message)
+	if m == nil {
+		return err
+	}
+	field, ok := uniqueKeyFields[m[1]]
+	if !ok {
+		return err
+	}
+	return &DuplicateError{Key: m[1], Field: field}
+}
+
+// requireParent checks inside tx that a live parent row exists, holding a
+// shared lock on it so it can't be trashed before the transaction commits.
+func requireParent(ctx context.Context, tx *sql.Tx, table string, id int64, field, entity string) error {
+	query := "SELECT 1 FROM " + table + " WHERE id = ?"
+	if _, ok := trashTables[table]; ok {
+		query += " AND deleted_at IS NULL"
+	}
+	var exists int
+	err := tx.QueryRowContext(ctx, query+" LOCK IN SHARE MODE", id).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &MissingParentError{Field: field, Entity: entity}
+	}
+	return err
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsDuplicateMapsUniqueIndexesToFields(t *testing.T) {
+	err := asDuplicate(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '3-Algebra' for key 'topics.uq_topics_lesson_name'"})
+	var dup *DuplicateError
+	if assert.ErrorAs(t, err, &dup) {
+		assert.Equal(t, "uq_topics_lesson_name", dup.Key)
+		assert.Equal(t, "name", dup.Field)
+	}
+
+	// MySQL 5.7 leaves the table off the key name
+	err = asDuplicate(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '2019' for key 'uq_years_value'"})
+	if assert.ErrorAs(t, err, &dup) {
+		assert.Equal(t, "value", dup.Field)
+	}
+}
+
+func TestAsDuplicateLeavesOtherErrorsAlone(t *testing.T) {
+	unknownKey := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'x' for key 'users.uq_users_email'"}
+	assert.Same(t, unknownKey, asDuplicate(unknownKey))
+
+	other := &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}
+	assert.Same(t, other, asDuplicate(other))
+
+	plain := errors.New("boom")
+	assert.Equal(t, plain, asDuplicate(plain))
+	assert.NoError(t, asDuplicate(nil))
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	menuconfigmodels "github.com/tharindulakmal/sl-edu-service/internal/models/menuconfig"
+)
+
+// ErrMenuConfigAmbiguous is returned by the name lookups when more than one
+// row matches, e.g. two subjects in a grade each having a lesson of that name.
+var ErrMenuConfigAmbiguous = errors.New("menuconfig: name matches more than one row")
+
+func (r *MenuConfigRepository) FindGradeByName(ctx context.Context, name string) (*Grade, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM grades WHERE name = ? AND deleted_at IS NULL LIMIT 2",
+		strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grades := make([]Grade, 0, 1)
+	for rows.Next() {
+		var g Grade
+		if err := rows.Scan(&g.ID, &g.Name, &g.CreatedAt); err != nil {
+			return nil, err
+		}
+		grades = append(grades, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return single(grades)
+}
+
+// FindLessonByName looks a lesson up by name among all subjects of a grade.
+func (r *MenuConfigRepository) FindLessonByName(ctx context.Context, gradeID int64, name string) (*menuconfigmodels.Lesson, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT l.id, l.subject_id, l.name, DATE_FORMAT(l.created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at
+		FROM lessons l
+			INNER JOIN subjects s ON s.id = l.subject_id
+		WHERE s.grade_id = ? AND l.name = ? AND l.deleted_at IS NULL AND s.deleted_at IS NULL
+		LIMIT 2`, gradeID, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lessons := make([]menuconfigmodels.Lesson, 0, 1)
+	for rows.Next() {
+		var l menuconfigmodels.Lesson
+		if err := rows.Scan(&l.ID, &l.SubjectID, &l.Name, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		lessons = append(lessons, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return single(lessons)
+}
+
+func (r *MenuConfigRepository) FindTopicByName(ctx context.Context, lessonID int64, name string) (*Topic, error) {
+	var t Topic
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id, lesson_id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM topics WHERE lesson_id = ? AND name = ? AND deleted_at IS NULL",
+		lessonID, strings.TrimSpace(name),
+	).Scan(&t.ID, &t.LessonID, &t.Name, &t.CreatedAt)
+	if err != nil {
+		return nil, notFoundOr(err)
+	}
+	return &t, nil
+}
+
+func (r *MenuConfigRepository) FindSubtopicByName(ctx context.Context, topicID int64, name string) (*Subtopic, error) {
+	var s Subtopic
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id, topic_id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM subtopics WHERE topic_id = ? AND name = ? AND deleted_at IS NULL",
+		topicID, strings.TrimSpace(name),
+	).Scan(&s.ID, &s.TopicID, &s.Name, &s.CreatedAt)
+	if err != nil {
+		return nil, notFoundOr(err)
+	}
+	return &s, nil
+}
+
+func single[T any](items []T) (*T, error) {
+	switch len(items) {
+	case 0:
+		return nil, ErrMenuConfigNotFound
+	case 1:
+		return &items[0], nil
+	default:
+		return nil, ErrMenuConfigAmbiguous
+	}
+}
+
+func notFoundOr(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrMenuConfigNotFound
+	}
+	return err
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	menuconfigmodels "github.com/tharindulakmal/sl-edu-service/internal/models/menuconfig"
+)
+
+type (
+	PastPaper       = menuconfigmodels.PastPaper
+	PastPaperUpsert = menuconfigmodels.PastPaperUpsert
+	PastPaperFilter = menuconfigmodels.PastPaperFilter
+)
+
+// PastPaperRepository is the read side of past papers used by the public API.
+type PastPaperRepository interface {
+	ListPastPapers(ctx context.Context, filter PastPaperFilter, page, pageSize int) ([]PastPaper, int, error)
+	GetPastPaper(ctx context.Context, id int64) (*PastPaper, error)
+}
+
+const pastPaperSelect = `
+	SELECT p.id, p.exam_type, p.year_id, y.value, p.subject_id, p.paper_number, p.name, p.duration_minutes,
+	       (SELECT COUNT(*) FROM questions q WHERE q.paper_id = p.id AND q.status = '` + editorial.StatusPublished + `'),
+	       DATE_FORMAT(p.created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at
+	FROM past_papers p
+		INNER JOIN years y ON y.id = p.year_id`
+
+func scanPastPaper(s interface{ Scan(...interface{}) error }) (PastPaper, error) {
+	var p PastPaper
+	err := s.Scan(&p.ID, &p.ExamType, &p.YearID, &p.Year, &p.SubjectID, &p.PaperNumber, &p.Name,
+		&p.DurationMinutes, &p.QuestionCount, &p.CreatedAt)
+	return p, err
+}
+
+// ListPastPapers returns the newest sittings first.
+func (r *MenuConfigRepository) ListPastPapers(ctx context.Context, filter PastPaperFilter, page, pageSize int) ([]PastPaper, int, error) {
+	filters := make([]string, 0)
+	args := make([]interface{}, 0)
+	if filter.ExamType != "" {
+		filters = append(filters, "p.exam_type = ?")
+		args = append(args, filter.ExamType)
+	}
+	if filter.YearID != 0 {
+		filters = append(filters, "p.year_id = ?")
+		args = append(args, filter.YearID)
+	}
+	if filter.SubjectID != 0 {
+		filters = append(filters, "p.subject_id = ?")
+		args = append(args, filter.SubjectID)
+	}
+	where := ""
+	if len(filters) > 0 {
+		where = " WHERE " + strings.Join(filters, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM past_papers p"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, pastPaperSelect+where+`
+		ORDER BY y.value DESC, p.exam_type, p.subject_id, p.paper_number
+		LIMIT ? OFFSET ?`, append(args, pageSize, offsetFromPage(page, pageSize))...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	papers := make([]PastPaper, 0)
+	for rows.Next() {
+		p, err := scanPastPaper(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		papers = append(papers, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return papers, total, nil
+}
+
+func (r *MenuConfigRepository) GetPastPaper(ctx context.Context, id int64) (*PastPaper, error) {
+	p, err := scanPastPaper(r.db.QueryRowContext(ctx, pastPaperSelect+" WHERE p.id = ?", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMenuConfigNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *MenuConfigRepository) CreatePastPaper(ctx context.Context, input PastPaperUpsert) (*PastPaper, error) {
+	id, err := r.audited(ctx, models.AuditCreate, "past_paper", "past_papers", 0, func(tx *sql.Tx) (int64, error) {
+		if err := requireParent(ctx, tx, "years", input.YearID, "yearId", "year"); err != nil {
+			return 0, err
+		}
+		if input.SubjectID != nil {
+			if err := requireParent(ctx, tx, "subjects", *input.SubjectID, "subjectId", "subject"); err != nil {
+				return 0, err
+			}
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO past_papers (exam_type, year_id, subject_id, paper_number, name, duration_minutes)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			input.ExamType, input.YearID, input.SubjectID, input.PaperNumber, strings.TrimSpace(input.Name), input.DurationMinutes)
+		if err != nil {
+			return 0, err
+		}
+		return res.LastInsertId()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetPastPaper(ctx, id)
+}
+
+// UpdatePastPaper also moves the paper's questions to the new year so they
+// keep matching the paper they belong to.
+func (r *MenuConfigRepository) UpdatePastPaper(ctx context.Context, id int64, input PastPaperUpsert) (*PastPaper, error) {
+	_, err := r.audited(ctx, models.AuditUpdate, "past_paper", "past_papers", id, func(tx *sql.Tx) (int64, error) {
+		if err := requireParent(ctx, tx, "years", input.YearID, "yearId", "year"); err != nil {
+			return 0, err
+		}
+		if input.SubjectID != nil {
+			if err := requireParent(ctx, tx, "subjects", *input.SubjectID, "subjectId", "subject"); err != nil {
+				return 0, err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE past_papers
+			SET exam_type = ?, year_id = ?, subject_id = ?, paper_number = ?, name = ?, duration_minutes = ?
+			WHERE id = ?`,
+			input.ExamType, input.YearID, input.SubjectID, input.PaperNumber, strings.TrimSpace(input.Name), input.DurationMinutes, id); err != nil {
+			return 0, err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE questions SET year_id = ? WHERE paper_id = ?", input.YearID, id)
+		return id, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetPastPaper(ctx, id)
+}
+
+// DeletePastPaper removes the paper; its questions stay in the bank, still
+// tagged with the year.
+func (r *MenuConfigRepository) DeletePastPaper(ctx context.Context, id int64) error {
+	_, err := r.audited(ctx, models.AuditDelete, "past_paper", "past_papers", id, func(tx *sql.Tx) (int64, error) {
+		if _, err := tx.ExecContext(ctx, "UPDATE questions SET question_number = NULL WHERE paper_id = ?", id); err != nil {
+			return 0, err
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM past_papers WHERE id = ?", id)
+		return id, err
+	})
+	return err
+}
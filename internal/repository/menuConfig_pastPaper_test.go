@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	menuconfigmodels "github.com/tharindulakmal/sl-edu-service/internal/models/menuconfig"
+)
+
+func TestPastPaperQuestions(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	menu := NewMenuConfigRepository(db)
+	questions := NewQuestionRepository(db)
+
+	// years far from real ones, fresh on every run
+	value := 10000 + int(time.Now().UnixNano()%1000000)*2
+	year, err := menu.CreateYear(ctx, YearUpsert{Value: value})
+	require.NoError(t, err)
+	nextYear, err := menu.CreateYear(ctx, YearUpsert{Value: value + 1})
+	require.NoError(t, err)
+	paper, err := menu.CreatePastPaper(ctx, PastPaperUpsert{ExamType: menuconfigmodels.ExamTypeOL, YearID: year.ID, PaperNumber: 2, Name: "Maths II"})
+	require.NoError(t, err)
+
+	grade, lesson := testLesson(t, menu)
+	paperID := int(paper.ID)
+	first, second := 1, 2
+	_, err = questions.CreateMany(ctx, []models.Question{
+		{GradeID: int(grade.ID), LessonID: int(lesson.ID), PaperID: &paperID, QuestionNumber: &second, Question: "Q2", CorrectAnswer: "b"},
+		{GradeID: int(grade.ID), LessonID: int(lesson.ID), PaperID: &paperID, QuestionNumber: &first, Question: "Q1", CorrectAnswer: "a"},
+	})
+	require.NoError(t, err)
+	_, err = db.Exec("UPDATE questions SET status = ? WHERE paper_id = ?", editorial.StatusPublished, paperID)
+	require.NoError(t, err)
+
+	// a paper reads in its printed order, and its questions take its year
+	list, err := questions.GetList(map[string]interface{}{"paperId": paperID}, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "Q1", list[0].Question)
+	assert.Equal(t, "Q2", list[1].Question)
+	assert.Equal(t, int(year.ID), *list[0].YearID)
+
+	count, err := questions.Count(map[string]interface{}{"lessonId": int(lesson.ID), "yearId": int(year.ID), "paper": 2})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	count, err = questions.Count(map[string]interface{}{"lessonId": int(lesson.ID), "paper": 1})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	// moving the paper to another year takes its questions along
+	_, err = menu.UpdatePastPaper(ctx, paper.ID, PastPaperUpsert{ExamType: menuconfigmodels.ExamTypeOL, YearID: nextYear.ID, PaperNumber: 2, Name: "Maths II"})
+	require.NoError(t, err)
+	count, err = questions.Count(map[string]interface{}{"lessonId": int(lesson.ID), "yearId": int(year.ID)})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	count, err = questions.Count(map[string]interface{}{"lessonId": int(lesson.ID), "yearId": int(nextYear.ID)})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	papers, total, err := menu.ListPastPapers(ctx, PastPaperFilter{YearID: nextYear.ID}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, 2, papers[0].QuestionCount)
+}
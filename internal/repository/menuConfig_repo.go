@@ -4,9 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"fmt"
 	"strings"
 
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
 	menuconfigmodels "github.com/tharindulakmal/sl-edu-service/internal/models/menuconfig"
 )
 
@@ -40,7 +40,7 @@ func NewMenuConfigRepository(db *sql.DB) *MenuConfigRepository {
 func (r *MenuConfigRepository) ListGrades(ctx context.Context, search string, page, pageSize int) ([]Grade, int, error) {
 	baseQuery := "SELECT id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM grades"
 	countQuery := "SELECT COUNT(*) FROM grades"
-	filters := make([]string, 0)
+	filters := []string{"deleted_at IS NULL"}
 	args := make([]interface{}, 0)
 	countArgs := make([]interface{}, 0)
 	if search != "" {
@@ -48,11 +48,9 @@ func (r *MenuConfigRepository) ListGrades(ctx context.Context, search string, pa
 		args = append(args, search)
 		countArgs = append(countArgs, search)
 	}
-	if len(filters) > 0 {
-		where := " WHERE " + strings.Join(filters, " AND ")
-		baseQuery += where
-		countQuery += where
-	}
+	where := " WHERE " + strings.Join(filters, " AND ")
+	baseQuery += where
+	countQuery += where
 	baseQuery += " ORDER BY id DESC LIMIT ? OFFSET ?"
 	args = append(args, pageSize, offsetFromPage(page, pageSize))
 
@@ -89,7 +87,7 @@ func (r *MenuConfigRepository) ListGrades(ctx context.Context, search string, pa
 }
 
 func (r *MenuConfigRepository) GetGrade(ctx context.Context, id int64) (*Grade, error) {
-	stmt, err := r.db.PrepareContext(ctx, "SELECT id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM grades WHERE id = ?")
+	stmt, err := r.db.PrepareContext(ctx, "SELECT id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM grades WHERE id = ? AND deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -106,17 +104,13 @@ func (r *MenuConfigRepository) GetGrade(ctx context.Context, id int64) (*Grade,
 }
 
 func (r *MenuConfigRepository) CreateGrade(ctx context.Context, input GradeUpsert) (*Grade, error) {
-	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO grades (name) VALUES (?)")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, strings.TrimSpace(input.Name))
-	if err != nil {
-		return nil, err
-	}
-	id, err := res.LastInsertId()
+	id, err := r.audited(ctx, models.AuditCreate, "grade", "grades", 0, func(tx *sql.Tx) (int64, error) {
+		res, err := tx.ExecContext(ctx, "INSERT INTO grades (name) VALUES (?)", strings.TrimSpace(input.Name))
+		if err != nil {
+			return 0, err
+		}
+		return res.LastInsertId()
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -124,51 +118,28 @@ func (r *MenuConfigRepository) CreateGrade(ctx context.Context, input GradeUpser
 }
 
 func (r *MenuConfigRepository) UpdateGrade(ctx context.Context, id int64, input GradeUpsert) (*Grade, error) {
-	stmt, err := r.db.PrepareContext(ctx, "UPDATE grades SET name = ? WHERE id = ?")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, strings.TrimSpace(input.Name), id)
+	_, err := r.audited(ctx, models.AuditUpdate, "grade", "grades", id, func(tx *sql.Tx) (int64, error) {
+		_, err := tx.ExecContext(ctx, "UPDATE grades SET name = ? WHERE id = ?", strings.TrimSpace(input.Name), id)
+		return id, err
+	})
 	if err != nil {
 		return nil, err
 	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return nil, err
-	}
-	if affected == 0 {
-		return nil, ErrMenuConfigNotFound
-	}
 	return r.GetGrade(ctx, id)
 }
 
-func (r *MenuConfigRepository) DeleteGrade(ctx context.Context, id int64) error {
-	stmt, err := r.db.PrepareContext(ctx, "DELETE FROM grades WHERE id = ?")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
+func (r *MenuConfigRepository) DeleteGrade(ctx context.Context, id int64, cascade bool) error {
+	return r.trash(ctx, "grades", id, cascade)
+}
 
-	res, err := stmt.ExecContext(ctx, id)
-	if err != nil {
-		return err
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return ErrMenuConfigNotFound
-	}
-	return nil
+func (r *MenuConfigRepository) RestoreGrade(ctx context.Context, id int64) error {
+	return r.restore(ctx, "grades", id)
 }
 
 func (r *MenuConfigRepository) ListSubjects(ctx context.Context, gradeID *int64, search string, page, pageSize int) ([]Subject, int, error) {
 	baseQuery := "SELECT id, grade_id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM subjects"
 	countQuery := "SELECT COUNT(*) FROM subjects"
-	filters := make([]string, 0)
+	filters := []string{"deleted_at IS NULL"}
 	args := make([]interface{}, 0)
 	countArgs := make([]interface{}, 0)
 
@@ -182,11 +153,9 @@ func (r *MenuConfigRepository) ListSubjects(ctx context.Context, gradeID *int64,
 		args = append(args, search)
 		countArgs = append(countArgs, search)
 	}
-	if len(filters) > 0 {
-		where := " WHERE " + strings.Join(filters, " AND ")
-		baseQuery += where
-		countQuery += where
-	}
+	where := " WHERE " + strings.Join(filters, " AND ")
+	baseQuery += where
+	countQuery += where
 	baseQuery += " ORDER BY id DESC LIMIT ? OFFSET ?"
 	args = append(args, pageSize, offsetFromPage(page, pageSize))
 
@@ -223,7 +192,7 @@ func (r *MenuConfigRepository) ListSubjects(ctx context.Context, gradeID *int64,
 }
 
 func (r *MenuConfigRepository) GetSubject(ctx context.Context, id int64) (*Subject, error) {
-	stmt, err := r.db.PrepareContext(ctx, "SELECT id, grade_id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM subjects WHERE id = ?")
+	stmt, err := r.db.PrepareContext(ctx, "SELECT id, grade_id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM subjects WHERE id = ? AND deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -240,17 +209,16 @@ func (r *MenuConfigRepository) GetSubject(ctx context.Context, id int64) (*Subje
 }
 
 func (r *MenuConfigRepository) CreateSubject(ctx context.Context, input SubjectUpsert) (*Subject, error) {
-	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO subjects (grade_id, name) VALUES (?, ?)")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, input.GradeID, strings.TrimSpace(input.Name))
-	if err != nil {
-		return nil, err
-	}
-	id, err := res.LastInsertId()
+	id, err := r.audited(ctx, models.AuditCreate, "subject", "subjects", 0, func(tx *sql.Tx) (int64, error) {
+		if err := requireParent(ctx, tx, "grades", input.GradeID, "gradeId", "grade"); err != nil {
+			return 0, err
+		}
+		res, err := tx.ExecContext(ctx, "INSERT INTO subjects (grade_id, name) VALUES (?, ?)", input.GradeID, strings.TrimSpace(input.Name))
+		if err != nil {
+			return 0, err
+		}
+		return res.LastInsertId()
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -258,51 +226,31 @@ func (r *MenuConfigRepository) CreateSubject(ctx context.Context, input SubjectU
 }
 
 func (r *MenuConfigRepository) UpdateSubject(ctx context.Context, id int64, input SubjectUpsert) (*Subject, error) {
-	stmt, err := r.db.PrepareContext(ctx, "UPDATE subjects SET grade_id = ?, name = ? WHERE id = ?")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, input.GradeID, strings.TrimSpace(input.Name), id)
-	if err != nil {
-		return nil, err
-	}
-	affected, err := res.RowsAffected()
+	_, err := r.audited(ctx, models.AuditUpdate, "subject", "subjects", id, func(tx *sql.Tx) (int64, error) {
+		if err := requireParent(ctx, tx, "grades", input.GradeID, "gradeId", "grade"); err != nil {
+			return 0, err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE subjects SET grade_id = ?, name = ? WHERE id = ?", input.GradeID, strings.TrimSpace(input.Name), id)
+		return id, err
+	})
 	if err != nil {
 		return nil, err
 	}
-	if affected == 0 {
-		return nil, ErrMenuConfigNotFound
-	}
 	return r.GetSubject(ctx, id)
 }
 
-func (r *MenuConfigRepository) DeleteSubject(ctx context.Context, id int64) error {
-	stmt, err := r.db.PrepareContext(ctx, "DELETE FROM subjects WHERE id = ?")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
+func (r *MenuConfigRepository) DeleteSubject(ctx context.Context, id int64, cascade bool) error {
+	return r.trash(ctx, "subjects", id, cascade)
+}
 
-	res, err := stmt.ExecContext(ctx, id)
-	if err != nil {
-		return err
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return ErrMenuConfigNotFound
-	}
-	return nil
+func (r *MenuConfigRepository) RestoreSubject(ctx context.Context, id int64) error {
+	return r.restore(ctx, "subjects", id)
 }
 
 func (r *MenuConfigRepository) ListLessons(ctx context.Context, subjectID *int64, search string, page, pageSize int) ([]menuconfigmodels.Lesson, int, error) {
 	baseQuery := "SELECT id, subject_id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM lessons"
 	countQuery := "SELECT COUNT(*) FROM lessons"
-	filters := make([]string, 0)
+	filters := []string{"deleted_at IS NULL"}
 	args := make([]interface{}, 0)
 	countArgs := make([]interface{}, 0)
 
@@ -316,11 +264,9 @@ func (r *MenuConfigRepository) ListLessons(ctx context.Context, subjectID *int64
 		args = append(args, search)
 		countArgs = append(countArgs, search)
 	}
-	if len(filters) > 0 {
-		where := " WHERE " + strings.Join(filters, " AND ")
-		baseQuery += where
-		countQuery += where
-	}
+	where := " WHERE " + strings.Join(filters, " AND ")
+	baseQuery += where
+	countQuery += where
 	baseQuery += " ORDER BY id DESC LIMIT ? OFFSET ?"
 	args = append(args, pageSize, offsetFromPage(page, pageSize))
 
@@ -357,7 +303,7 @@ func (r *MenuConfigRepository) ListLessons(ctx context.Context, subjectID *int64
 }
 
 func (r *MenuConfigRepository) GetLesson(ctx context.Context, id int64) (*menuconfigmodels.Lesson, error) {
-	stmt, err := r.db.PrepareContext(ctx, "SELECT id, subject_id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM lessons WHERE id = ?")
+	stmt, err := r.db.PrepareContext(ctx, "SELECT id, subject_id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM lessons WHERE id = ? AND deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -374,17 +320,16 @@ func (r *MenuConfigRepository) GetLesson(ctx context.Context, id int64) (*menuco
 }
 
 func (r *MenuConfigRepository) CreateLesson(ctx context.Context, input menuconfigmodels.LessonUpsert) (*menuconfigmodels.Lesson, error) {
-	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO lessons (subject_id, name) VALUES (?, ?)")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, input.SubjectID, strings.TrimSpace(input.Name))
-	if err != nil {
-		return nil, err
-	}
-	id, err := res.LastInsertId()
+	id, err := r.audited(ctx, models.AuditCreate, "lesson", "lessons", 0, func(tx *sql.Tx) (int64, error) {
+		if err := requireParent(ctx, tx, "subjects", input.SubjectID, "subjectId", "subject"); err != nil {
+			return 0, err
+		}
+		res, err := tx.ExecContext(ctx, "INSERT INTO lessons (subject_id, name) VALUES (?, ?)", input.SubjectID, strings.TrimSpace(input.Name))
+		if err != nil {
+			return 0, err
+		}
+		return res.LastInsertId()
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -392,51 +337,31 @@ func (r *MenuConfigRepository) CreateLesson(ctx context.Context, input menuconfi
 }
 
 func (r *MenuConfigRepository) UpdateLesson(ctx context.Context, id int64, input menuconfigmodels.LessonUpsert) (*menuconfigmodels.Lesson, error) {
-	stmt, err := r.db.PrepareContext(ctx, "UPDATE lessons SET subject_id = ?, name = ? WHERE id = ?")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, input.SubjectID, strings.TrimSpace(input.Name), id)
-	if err != nil {
-		return nil, err
-	}
-	affected, err := res.RowsAffected()
+	_, err := r.audited(ctx, models.AuditUpdate, "lesson", "lessons", id, func(tx *sql.Tx) (int64, error) {
+		if err := requireParent(ctx, tx, "subjects", input.SubjectID, "subjectId", "subject"); err != nil {
+			return 0, err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE lessons SET subject_id = ?, name = ? WHERE id = ?", input.SubjectID, strings.TrimSpace(input.Name), id)
+		return id, err
+	})
 	if err != nil {
 		return nil, err
 	}
-	if affected == 0 {
-		return nil, ErrMenuConfigNotFound
-	}
 	return r.GetLesson(ctx, id)
 }
 
-func (r *MenuConfigRepository) DeleteLesson(ctx context.Context, id int64) error {
-	stmt, err := r.db.PrepareContext(ctx, "DELETE FROM lessons WHERE id = ?")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
+func (r *MenuConfigRepository) DeleteLesson(ctx context.Context, id int64, cascade bool) error {
+	return r.trash(ctx, "lessons", id, cascade)
+}
 
-	res, err := stmt.ExecContext(ctx, id)
-	if err != nil {
-		return err
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return ErrMenuConfigNotFound
-	}
-	return nil
+func (r *MenuConfigRepository) RestoreLesson(ctx context.Context, id int64) error {
+	return r.restore(ctx, "lessons", id)
 }
 
 func (r *MenuConfigRepository) ListTopics(ctx context.Context, lessonID *int64, search string, page, pageSize int) ([]Topic, int, error) {
 	baseQuery := "SELECT id, lesson_id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM topics"
 	countQuery := "SELECT COUNT(*) FROM topics"
-	filters := make([]string, 0)
+	filters := []string{"deleted_at IS NULL"}
 	args := make([]interface{}, 0)
 	countArgs := make([]interface{}, 0)
 
@@ -450,11 +375,9 @@ func (r *MenuConfigRepository) ListTopics(ctx context.Context, lessonID *int64,
 		args = append(args, search)
 		countArgs = append(countArgs, search)
 	}
-	if len(filters) > 0 {
-		where := " WHERE " + strings.Join(filters, " AND ")
-		baseQuery += where
-		countQuery += where
-	}
+	where := " WHERE " + strings.Join(filters, " AND ")
+	baseQuery += where
+	countQuery += where
 	baseQuery += " ORDER BY id DESC LIMIT ? OFFSET ?"
 	args = append(args, pageSize, offsetFromPage(page, pageSize))
 
@@ -491,7 +414,7 @@ func (r *MenuConfigRepository) ListTopics(ctx context.Context, lessonID *int64,
 }
 
 func (r *MenuConfigRepository) GetTopic(ctx context.Context, id int64) (*Topic, error) {
-	stmt, err := r.db.PrepareContext(ctx, "SELECT id, lesson_id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM topics WHERE id = ?")
+	stmt, err := r.db.PrepareContext(ctx, "SELECT id, lesson_id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM topics WHERE id = ? AND deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -508,17 +431,16 @@ func (r *MenuConfigRepository) GetTopic(ctx context.Context, id int64) (*Topic,
 }
 
 func (r *MenuConfigRepository) CreateTopic(ctx context.Context, input TopicUpsert) (*Topic, error) {
-	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO topics (lesson_id, name) VALUES (?, ?)")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, input.LessonID, strings.TrimSpace(input.Name))
-	if err != nil {
-		return nil, err
-	}
-	id, err := res.LastInsertId()
+	id, err := r.audited(ctx, models.AuditCreate, "topic", "topics", 0, func(tx *sql.Tx) (int64, error) {
+		if err := requireParent(ctx, tx, "lessons", input.LessonID, "lessonId", "lesson"); err != nil {
+			return 0, err
+		}
+		res, err := tx.ExecContext(ctx, "INSERT INTO topics (lesson_id, name) VALUES (?, ?)", input.LessonID, strings.TrimSpace(input.Name))
+		if err != nil {
+			return 0, err
+		}
+		return res.LastInsertId()
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -526,51 +448,31 @@ func (r *MenuConfigRepository) CreateTopic(ctx context.Context, input TopicUpser
 }
 
 func (r *MenuConfigRepository) UpdateTopic(ctx context.Context, id int64, input TopicUpsert) (*Topic, error) {
-	stmt, err := r.db.PrepareContext(ctx, "UPDATE topics SET lesson_id = ?, name = ? WHERE id = ?")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, input.LessonID, strings.TrimSpace(input.Name), id)
-	if err != nil {
-		return nil, err
-	}
-	affected, err := res.RowsAffected()
+	_, err := r.audited(ctx, models.AuditUpdate, "topic", "topics", id, func(tx *sql.Tx) (int64, error) {
+		if err := requireParent(ctx, tx, "lessons", input.LessonID, "lessonId", "lesson"); err != nil {
+			return 0, err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE topics SET lesson_id = ?, name = ? WHERE id = ?", input.LessonID, strings.TrimSpace(input.Name), id)
+		return id, err
+	})
 	if err != nil {
 		return nil, err
 	}
-	if affected == 0 {
-		return nil, ErrMenuConfigNotFound
-	}
 	return r.GetTopic(ctx, id)
 }
 
-func (r *MenuConfigRepository) DeleteTopic(ctx context.Context, id int64) error {
-	stmt, err := r.db.PrepareContext(ctx, "DELETE FROM topics WHERE id = ?")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
+func (r *MenuConfigRepository) DeleteTopic(ctx context.Context, id int64, cascade bool) error {
+	return r.trash(ctx, "topics", id, cascade)
+}
 
-	res, err := stmt.ExecContext(ctx, id)
-	if err != nil {
-		return err
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return ErrMenuConfigNotFound
-	}
-	return nil
+func (r *MenuConfigRepository) RestoreTopic(ctx context.Context, id int64) error {
+	return r.restore(ctx, "topics", id)
 }
 
 func (r *MenuConfigRepository) ListSubtopics(ctx context.Context, topicID *int64, search string, page, pageSize int) ([]Subtopic, int, error) {
 	baseQuery := "SELECT id, topic_id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM subtopics"
 	countQuery := "SELECT COUNT(*) FROM subtopics"
-	filters := make([]string, 0)
+	filters := []string{"deleted_at IS NULL"}
 	args := make([]interface{}, 0)
 	countArgs := make([]interface{}, 0)
 
@@ -584,11 +486,9 @@ func (r *MenuConfigRepository) ListSubtopics(ctx context.Context, topicID *int64
 		args = append(args, search)
 		countArgs = append(countArgs, search)
 	}
-	if len(filters) > 0 {
-		where := " WHERE " + strings.Join(filters, " AND ")
-		baseQuery += where
-		countQuery += where
-	}
+	where := " WHERE " + strings.Join(filters, " AND ")
+	baseQuery += where
+	countQuery += where
 	baseQuery += " ORDER BY id DESC LIMIT ? OFFSET ?"
 	args = append(args, pageSize, offsetFromPage(page, pageSize))
 
@@ -625,7 +525,7 @@ func (r *MenuConfigRepository) ListSubtopics(ctx context.Context, topicID *int64
 }
 
 func (r *MenuConfigRepository) GetSubtopic(ctx context.Context, id int64) (*Subtopic, error) {
-	stmt, err := r.db.PrepareContext(ctx, "SELECT id, topic_id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM subtopics WHERE id = ?")
+	stmt, err := r.db.PrepareContext(ctx, "SELECT id, topic_id, name, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM subtopics WHERE id = ? AND deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -642,17 +542,16 @@ func (r *MenuConfigRepository) GetSubtopic(ctx context.Context, id int64) (*Subt
 }
 
 func (r *MenuConfigRepository) CreateSubtopic(ctx context.Context, input SubtopicUpsert) (*Subtopic, error) {
-	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO subtopics (topic_id, name) VALUES (?, ?)")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, input.TopicID, strings.TrimSpace(input.Name))
-	if err != nil {
-		return nil, err
-	}
-	id, err := res.LastInsertId()
+	id, err := r.audited(ctx, models.AuditCreate, "subtopic", "subtopics", 0, func(tx *sql.Tx) (int64, error) {
+		if err := requireParent(ctx, tx, "topics", input.TopicID, "topicId", "topic"); err != nil {
+			return 0, err
+		}
+		res, err := tx.ExecContext(ctx, "INSERT INTO subtopics (topic_id, name) VALUES (?, ?)", input.TopicID, strings.TrimSpace(input.Name))
+		if err != nil {
+			return 0, err
+		}
+		return res.LastInsertId()
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -660,51 +559,31 @@ func (r *MenuConfigRepository) CreateSubtopic(ctx context.Context, input Subtopi
 }
 
 func (r *MenuConfigRepository) UpdateSubtopic(ctx context.Context, id int64, input SubtopicUpsert) (*Subtopic, error) {
-	stmt, err := r.db.PrepareContext(ctx, "UPDATE subtopics SET topic_id = ?, name = ? WHERE id = ?")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, input.TopicID, strings.TrimSpace(input.Name), id)
-	if err != nil {
-		return nil, err
-	}
-	affected, err := res.RowsAffected()
+	_, err := r.audited(ctx, models.AuditUpdate, "subtopic", "subtopics", id, func(tx *sql.Tx) (int64, error) {
+		if err := requireParent(ctx, tx, "topics", input.TopicID, "topicId", "topic"); err != nil {
+			return 0, err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE subtopics SET topic_id = ?, name = ? WHERE id = ?", input.TopicID, strings.TrimSpace(input.Name), id)
+		return id, err
+	})
 	if err != nil {
 		return nil, err
 	}
-	if affected == 0 {
-		return nil, ErrMenuConfigNotFound
-	}
 	return r.GetSubtopic(ctx, id)
 }
 
-func (r *MenuConfigRepository) DeleteSubtopic(ctx context.Context, id int64) error {
-	stmt, err := r.db.PrepareContext(ctx, "DELETE FROM subtopics WHERE id = ?")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
+func (r *MenuConfigRepository) DeleteSubtopic(ctx context.Context, id int64, cascade bool) error {
+	return r.trash(ctx, "subtopics", id, cascade)
+}
 
-	res, err := stmt.ExecContext(ctx, id)
-	if err != nil {
-		return err
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return ErrMenuConfigNotFound
-	}
-	return nil
+func (r *MenuConfigRepository) RestoreSubtopic(ctx context.Context, id int64) error {
+	return r.restore(ctx, "subtopics", id)
 }
 
 func (r *MenuConfigRepository) ListTutors(ctx context.Context, search string, page, pageSize int) ([]Tutor, int, error) {
 	baseQuery := "SELECT id, name, email, phone, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM tutors"
 	countQuery := "SELECT COUNT(*) FROM tutors"
-	filters := make([]string, 0)
+	filters := []string{"deleted_at IS NULL"}
 	args := make([]interface{}, 0)
 	countArgs := make([]interface{}, 0)
 
@@ -713,11 +592,9 @@ func (r *MenuConfigRepository) ListTutors(ctx context.Context, search string, pa
 		args = append(args, search)
 		countArgs = append(countArgs, search)
 	}
-	if len(filters) > 0 {
-		where := " WHERE " + strings.Join(filters, " AND ")
-		baseQuery += where
-		countQuery += where
-	}
+	where := " WHERE " + strings.Join(filters, " AND ")
+	baseQuery += where
+	countQuery += where
 	baseQuery += " ORDER BY id DESC LIMIT ? OFFSET ?"
 	args = append(args, pageSize, offsetFromPage(page, pageSize))
 
@@ -754,7 +631,7 @@ func (r *MenuConfigRepository) ListTutors(ctx context.Context, search string, pa
 }
 
 func (r *MenuConfigRepository) GetTutor(ctx context.Context, id int64) (*Tutor, error) {
-	stmt, err := r.db.PrepareContext(ctx, "SELECT id, name, email, phone, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM tutors WHERE id = ?")
+	stmt, err := r.db.PrepareContext(ctx, "SELECT id, name, email, phone, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM tutors WHERE id = ? AND deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -771,17 +648,13 @@ func (r *MenuConfigRepository) GetTutor(ctx context.Context, id int64) (*Tutor,
 }
 
 func (r *MenuConfigRepository) CreateTutor(ctx context.Context, input TutorUpsert) (*Tutor, error) {
-	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO tutors (name, email, phone) VALUES (?, ?, ?)")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, strings.TrimSpace(input.Name), input.Email, input.Phone)
-	if err != nil {
-		return nil, err
-	}
-	id, err := res.LastInsertId()
+	id, err := r.audited(ctx, models.AuditCreate, "tutor", "tutors", 0, func(tx *sql.Tx) (int64, error) {
+		res, err := tx.ExecContext(ctx, "INSERT INTO tutors (name, email, phone) VALUES (?, ?, ?)", strings.TrimSpace(input.Name), input.Email, input.Phone)
+		if err != nil {
+			return 0, err
+		}
+		return res.LastInsertId()
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -789,51 +662,28 @@ func (r *MenuConfigRepository) CreateTutor(ctx context.Context, input TutorUpser
 }
 
 func (r *MenuConfigRepository) UpdateTutor(ctx context.Context, id int64, input TutorUpsert) (*Tutor, error) {
-	stmt, err := r.db.PrepareContext(ctx, "UPDATE tutors SET name = ?, email = ?, phone = ? WHERE id = ?")
+	_, err := r.audited(ctx, models.AuditUpdate, "tutor", "tutors", id, func(tx *sql.Tx) (int64, error) {
+		_, err := tx.ExecContext(ctx, "UPDATE tutors SET name = ?, email = ?, phone = ? WHERE id = ?", strings.TrimSpace(input.Name), input.Email, input.Phone, id)
+		return id, err
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, strings.TrimSpace(input.Name), input.Email, input.Phone, id)
-	if err != nil {
-		return nil, err
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return nil, err
-	}
-	if affected == 0 {
-		return nil, ErrMenuConfigNotFound
-	}
 	return r.GetTutor(ctx, id)
 }
 
-func (r *MenuConfigRepository) DeleteTutor(ctx context.Context, id int64) error {
-	stmt, err := r.db.PrepareContext(ctx, "DELETE FROM tutors WHERE id = ?")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
+func (r *MenuConfigRepository) DeleteTutor(ctx context.Context, id int64, cascade bool) error {
+	return r.trash(ctx, "tutors", id, cascade)
+}
 
-	res, err := stmt.ExecContext(ctx, id)
-	if err != nil {
-		return err
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return ErrMenuConfigNotFound
-	}
-	return nil
+func (r *MenuConfigRepository) RestoreTutor(ctx context.Context, id int64) error {
+	return r.restore(ctx, "tutors", id)
 }
 
 func (r *MenuConfigRepository) ListYears(ctx context.Context, search string, page, pageSize int) ([]Year, int, error) {
 	baseQuery := "SELECT id, value, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM years"
 	countQuery := "SELECT COUNT(*) FROM years"
-	filters := make([]string, 0)
+	filters := []string{"deleted_at IS NULL"}
 	args := make([]interface{}, 0)
 	countArgs := make([]interface{}, 0)
 
@@ -842,11 +692,9 @@ func (r *MenuConfigRepository) ListYears(ctx context.Context, search string, pag
 		args = append(args, search)
 		countArgs = append(countArgs, search)
 	}
-	if len(filters) > 0 {
-		where := " WHERE " + strings.Join(filters, " AND ")
-		baseQuery += where
-		countQuery += where
-	}
+	where := " WHERE " + strings.Join(filters, " AND ")
+	baseQuery += where
+	countQuery += where
 	baseQuery += " ORDER BY id DESC LIMIT ? OFFSET ?"
 	args = append(args, pageSize, offsetFromPage(page, pageSize))
 
@@ -883,7 +731,7 @@ func (r *MenuConfigRepository) ListYears(ctx context.Context, search string, pag
 }
 
 func (r *MenuConfigRepository) GetYear(ctx context.Context, id int64) (*Year, error) {
-	stmt, err := r.db.PrepareContext(ctx, "SELECT id, value, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM years WHERE id = ?")
+	stmt, err := r.db.PrepareContext(ctx, "SELECT id, value, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM years WHERE id = ? AND deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -900,17 +748,13 @@ func (r *MenuConfigRepository) GetYear(ctx context.Context, id int64) (*Year, er
 }
 
 func (r *MenuConfigRepository) CreateYear(ctx context.Context, input YearUpsert) (*Year, error) {
-	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO years (value) VALUES (?)")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, input.Value)
-	if err != nil {
-		return nil, err
-	}
-	id, err := res.LastInsertId()
+	id, err := r.audited(ctx, models.AuditCreate, "year", "years", 0, func(tx *sql.Tx) (int64, error) {
+		res, err := tx.ExecContext(ctx, "INSERT INTO years (value) VALUES (?)", input.Value)
+		if err != nil {
+			return 0, err
+		}
+		return res.LastInsertId()
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -918,51 +762,28 @@ func (r *MenuConfigRepository) CreateYear(ctx context.Context, input YearUpsert)
 }
 
 func (r *MenuConfigRepository) UpdateYear(ctx context.Context, id int64, input YearUpsert) (*Year, error) {
-	stmt, err := r.db.PrepareContext(ctx, "UPDATE years SET value = ? WHERE id = ?")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, input.Value, id)
+	_, err := r.audited(ctx, models.AuditUpdate, "year", "years", id, func(tx *sql.Tx) (int64, error) {
+		_, err := tx.ExecContext(ctx, "UPDATE years SET value = ? WHERE id = ?", input.Value, id)
+		return id, err
+	})
 	if err != nil {
 		return nil, err
 	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return nil, err
-	}
-	if affected == 0 {
-		return nil, ErrMenuConfigNotFound
-	}
 	return r.GetYear(ctx, id)
 }
 
-func (r *MenuConfigRepository) DeleteYear(ctx context.Context, id int64) error {
-	stmt, err := r.db.PrepareContext(ctx, "DELETE FROM years WHERE id = ?")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
+func (r *MenuConfigRepository) DeleteYear(ctx context.Context, id int64, cascade bool) error {
+	return r.trash(ctx, "years", id, cascade)
+}
 
-	res, err := stmt.ExecContext(ctx, id)
-	if err != nil {
-		return err
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return ErrMenuConfigNotFound
-	}
-	return nil
+func (r *MenuConfigRepository) RestoreYear(ctx context.Context, id int64) error {
+	return r.restore(ctx, "years", id)
 }
 
 func (r *MenuConfigRepository) ListTutorials(ctx context.Context, search string, page, pageSize int) ([]Tutorial, int, error) {
 	baseQuery := "SELECT id, name, url, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM tutorials"
 	countQuery := "SELECT COUNT(*) FROM tutorials"
-	filters := make([]string, 0)
+	filters := []string{"deleted_at IS NULL"}
 	args := make([]interface{}, 0)
 	countArgs := make([]interface{}, 0)
 
@@ -971,11 +792,9 @@ func (r *MenuConfigRepository) ListTutorials(ctx context.Context, search string,
 		args = append(args, search)
 		countArgs = append(countArgs, search)
 	}
-	if len(filters) > 0 {
-		where := " WHERE " + strings.Join(filters, " AND ")
-		baseQuery += where
-		countQuery += where
-	}
+	where := " WHERE " + strings.Join(filters, " AND ")
+	baseQuery += where
+	countQuery += where
 	baseQuery += " ORDER BY id DESC LIMIT ? OFFSET ?"
 	args = append(args, pageSize, offsetFromPage(page, pageSize))
 
@@ -1012,7 +831,7 @@ func (r *MenuConfigRepository) ListTutorials(ctx context.Context, search string,
 }
 
 func (r *MenuConfigRepository) GetTutorial(ctx context.Context, id int64) (*Tutorial, error) {
-	stmt, err := r.db.PrepareContext(ctx, "SELECT id, name, url, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM tutorials WHERE id = ?")
+	stmt, err := r.db.PrepareContext(ctx, "SELECT id, name, url, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at FROM tutorials WHERE id = ? AND deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -1029,17 +848,13 @@ func (r *MenuConfigRepository) GetTutorial(ctx context.Context, id int64) (*Tuto
 }
 
 func (r *MenuConfigRepository) CreateTutorial(ctx context.Context, input TutorialUpsert) (*Tutorial, error) {
-	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO tutorials (name, url) VALUES (?, ?)")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, strings.TrimSpace(input.Name), input.URL)
-	if err != nil {
-		return nil, err
-	}
-	id, err := res.LastInsertId()
+	id, err := r.audited(ctx, models.AuditCreate, "tutorial", "tutorials", 0, func(tx *sql.Tx) (int64, error) {
+		res, err := tx.ExecContext(ctx, "INSERT INTO tutorials (name, url) VALUES (?, ?)", strings.TrimSpace(input.Name), input.URL)
+		if err != nil {
+			return 0, err
+		}
+		return res.LastInsertId()
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -1047,45 +862,39 @@ func (r *MenuConfigRepository) CreateTutorial(ctx context.Context, input Tutoria
 }
 
 func (r *MenuConfigRepository) UpdateTutorial(ctx context.Context, id int64, input TutorialUpsert) (*Tutorial, error) {
-	stmt, err := r.db.PrepareContext(ctx, "UPDATE tutorials SET name = ?, url = ? WHERE id = ?")
+	_, err := r.audited(ctx, models.AuditUpdate, "tutorial", "tutorials", id, func(tx *sql.Tx) (int64, error) {
+		_, err := tx.ExecContext(ctx, "UPDATE tutorials SET name = ?, url = ? WHERE id = ?", strings.TrimSpace(input.Name), input.URL, id)
+		return id, err
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer stmt.Close()
-
-	res, err := stmt.ExecContext(ctx, strings.TrimSpace(input.Name), input.URL, id)
-	if err != nil {
-		return nil, err
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return nil, err
-	}
-	if affected == 0 {
-		return nil, ErrMenuConfigNotFound
-	}
 	return r.GetTutorial(ctx, id)
 }
 
-func (r *MenuConfigRepository) DeleteTutorial(ctx context.Context, id int64) error {
-	stmt, err := r.db.PrepareContext(ctx, "DELETE FROM tutorials WHERE id = ?")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
+func (r *MenuConfigRepository) DeleteTutorial(ctx context.Context, id int64, cascade bool) error {
+	return r.trash(ctx, "tutorials", id, cascade)
+}
 
-	res, err := stmt.ExecContext(ctx, id)
-	if err != nil {
-		return err
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return ErrMenuConfigNotFound
+func (r *MenuConfigRepository) RestoreTutorial(ctx context.Context, id int64) error {
+	return r.restore(ctx, "tutorials", id)
+}
+
+// audited runs a change to one of the menu config tables together with its
+// audit log entry. Rows in the trash can't be changed, and clashes on the
+// unique indexes come back as a *DuplicateError.
+func (r *MenuConfigRepository) audited(ctx context.Context, action, entity, table string, id int64, change func(tx *sql.Tx) (int64, error)) (int64, error) {
+	if _, ok := trashTables[table]; ok && id != 0 {
+		apply := change
+		change = func(tx *sql.Tx) (int64, error) {
+			if err := requireLive(ctx, tx, table, id); err != nil {
+				return 0, err
+			}
+			return apply(tx)
+		}
 	}
-	return nil
+	id, err := auditedChange(ctx, r.db, ErrMenuConfigNotFound, action, entity, table, id, change)
+	return id, asDuplicate(err)
 }
 
 func (r *MenuConfigRepository) count(ctx context.Context, query string, args ...interface{}) (int, error) {
@@ -1111,21 +920,3 @@ func offsetFromPage(page, pageSize int) int {
 	}
 	return (page - 1) * pageSize
 }
-
-func (r *MenuConfigRepository) CheckParentExists(ctx context.Context, table string, id int64) (bool, error) {
-	query := fmt.Sprintf("SELECT 1 FROM %s WHERE id = ? LIMIT 1", table)
-	stmt, err := r.db.PrepareContext(ctx, query)
-	if err != nil {
-		return false, err
-	}
-	defer stmt.Close()
-
-	var exists int
-	if err := stmt.QueryRowContext(ctx, id).Scan(&exists); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return false, nil
-		}
-		return false, err
-	}
-	return true, nil
-}
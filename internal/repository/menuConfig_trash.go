@@ -0,0 +1,383 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	menuconfigmodels "github.com/tharindulakmal/sl-edu-service/internal/models/menuconfig"
+)
+
+// ErrMenuConfigParentDeleted is returned when restoring a row whose parent
+// is still in the trash; the parent has to be restored first.
+var ErrMenuConfigParentDeleted = errors.New("menuconfig: parent is in the trash")
+
+// ErrMenuConfigUnknownEntity is returned by ListTrash for an entity that has
+// no trash.
+var ErrMenuConfigUnknownEntity = errors.New("menuconfig: unknown entity")
+
+// DependentsError is returned when deleting a row that live rows still point
+// at and the caller did not ask to cascade. Dependents counts them by table,
+// over the whole subtree the delete would trash.
+type DependentsError struct {
+	Dependents map[string]int
+}
+
+func (e *DependentsError) Error() string {
+	tables := make([]string, 0, len(e.Dependents))
+	for table := range e.Dependents {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for i, table := range tables {
+		tables[i] = fmt.Sprintf("%d %s", e.Dependents[table], table)
+	}
+	return "menuconfig: still referenced by " + strings.Join(tables, ", ")
+}
+
+// trashRef is a column of table pointing at the row being deleted.
+type trashRef struct {
+	table  string
+	column string
+}
+
+// trashTable describes how a soft-deletable menu config table hangs
+// together. Children are menu config rows trashed and restored along with
+// their parent; refs are content that keeps pointing at a trashed row and
+// comes back with it.
+type trashTable struct {
+	entity   string
+	name     string
+	parent   *trashRef
+	children []trashRef
+	refs     []trashRef
+}
+
+var trashTables = map[string]trashTable{
+	"grades": {
+		entity:   models.EntityGrade,
+		name:     "name",
+		children: []trashRef{{"subjects", "grade_id"}},
+		refs:     []trashRef{{"questions", "grade_id"}},
+	},
+	"subjects": {
+		entity:   models.EntitySubject,
+		name:     "name",
+		parent:   &trashRef{"grades", "grade_id"},
+		children: []trashRef{{"lessons", "subject_id"}},
+		refs:     []trashRef{{"past_papers", "subject_id"}},
+	},
+	"lessons": {
+		entity:   models.EntityLesson,
+		name:     "name",
+		parent:   &trashRef{"subjects", "subject_id"},
+		children: []trashRef{{"topics", "lesson_id"}},
+		refs:     []trashRef{{"questions", "lesson_id"}, {"smart_notes", "lesson_id"}},
+	},
+	"topics": {
+		entity:   models.EntityTopic,
+		name:     "name",
+		parent:   &trashRef{"lessons", "lesson_id"},
+		children: []trashRef{{"subtopics", "topic_id"}},
+		refs:     []trashRef{{"questions", "topic_id"}, {"smart_notes", "topic_id"}},
+	},
+	"subtopics": {
+		entity: models.EntitySubtopic,
+		name:   "name",
+		parent: &trashRef{"topics", "topic_id"},
+		refs:   []trashRef{{"questions", "subtopic_id"}, {"smart_notes", "subtopic_id"}},
+	},
+	"tutors": {
+		entity: "tutor",
+		name:   "name",
+		refs:   []trashRef{{"questions", "tutor_id"}, {"users", "tutor_id"}},
+	},
+	"years": {
+		entity: "year",
+		name:   "CAST(value AS CHAR)",
+		refs:   []trashRef{{"questions", "year_id"}, {"past_papers", "year_id"}},
+	},
+	"tutorials": {
+		entity: "tutorial",
+		name:   "name",
+		refs:   []trashRef{{"questions", "tute_id"}},
+	},
+}
+
+// contentParents are the curriculum columns questions and smart notes both
+// hang from. Trashing a grade or subject trashes its lessons too, so those
+// need no column of their own.
+var contentParents = []trashRef{{"lessons", "lesson_id"}, {"topics", "topic_id"}, {"subtopics", "subtopic_id"}}
+
+// liveParents is a condition on the question or smart note aliased as alias
+// that holds while none of its parents is in the trash.
+func liveParents(alias string) string {
+	conds := make([]string, 0, len(contentParents))
+	for _, p := range contentParents {
+		conds = append(conds, fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s p WHERE p.id = %s.%s AND p.deleted_at IS NOT NULL)", p.table, alias, p.column))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// trashOrder fixes the order of the trash listing's union.
+var trashOrder = []string{"grades", "subjects", "lessons", "topics", "subtopics", "tutors", "years", "tutorials"}
+
+// deletedAtFormat renders deleted_at the way MySQL reads it back, so the
+// stamp shared by a cascade can be matched whether or not the connection
+// parses times.
+const deletedAtFormat = "'%Y-%m-%d %H:%i:%s'"
+
+// trashedRows are the ids of one table a delete or restore touches.
+type trashedRows struct {
+	table string
+	ids   []int64
+}
+
+// trash soft-deletes a row together with its live descendants, all stamped
+// with the same deleted_at so a restore can tell them apart from rows
+// trashed earlier. Unless cascade is set, a row that anything still points
+// at is left alone and a *DependentsError reports what does.
+func (r *MenuConfigRepository) trash(ctx context.Context, table string, id int64, cascade bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := requireLive(ctx, tx, table, id); err != nil {
+		return err
+	}
+	subtree, dependents, err := liveSubtree(ctx, tx, table, id)
+	if err != nil {
+		return err
+	}
+	if len(dependents) > 0 && !cascade {
+		return &DependentsError{Dependents: dependents}
+	}
+
+	var now string
+	if err := tx.QueryRowContext(ctx, "SELECT DATE_FORMAT(CURRENT_TIMESTAMP, "+deletedAtFormat+")").Scan(&now); err != nil {
+		return err
+	}
+	for _, rows := range subtree {
+		if err := setDeletedAt(ctx, tx, models.AuditDelete, rows, now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// restore brings a trashed row back together with the descendants that
+// were trashed with it. A row whose name a live row has taken since comes
+// back as a *DuplicateError.
+func (r *MenuConfigRepository) restore(ctx context.Context, table string, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var deletedAt sql.NullString
+	err = tx.QueryRowContext(ctx, "SELECT DATE_FORMAT(deleted_at, "+deletedAtFormat+") FROM "+table+" WHERE id = ? FOR UPDATE", id).Scan(&deletedAt)
+	if err != nil || !deletedAt.Valid {
+		if err == nil || errors.Is(err, sql.ErrNoRows) {
+			return ErrMenuConfigNotFound
+		}
+		return err
+	}
+
+	if parent := trashTables[table].parent; parent != nil {
+		var parentDeleted bool
+		err := tx.QueryRowContext(ctx, fmt.Sprintf(
+			"SELECT p.deleted_at IS NOT NULL FROM %s t INNER JOIN %s p ON p.id = t.%s WHERE t.id = ?",
+			table, parent.table, parent.column), id).Scan(&parentDeleted)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if parentDeleted {
+			return ErrMenuConfigParentDeleted
+		}
+	}
+
+	subtree := []trashedRows{{table: table, ids: []int64{id}}}
+	for i := 0; i < len(subtree); i++ {
+		level := subtree[i]
+		if err := setDeletedAt(ctx, tx, models.AuditRestore, level, deletedAt.String); err != nil {
+			return asDuplicate(err)
+		}
+		for _, child := range trashTables[level.table].children {
+			ids, err := childIDs(ctx, tx, child, level.ids, "deleted_at = ?", deletedAt.String)
+			if err != nil {
+				return err
+			}
+			if len(ids) > 0 {
+				subtree = append(subtree, trashedRows{table: child.table, ids: ids})
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// liveSubtree returns the row and its live descendants level by level, and
+// counts everything pointing at them by table. A question tagged with both
+// a lesson and one of its topics is counted once.
+func liveSubtree(ctx context.Context, tx *sql.Tx, table string, id int64) ([]trashedRows, map[string]int, error) {
+	dependents := make(map[string]int)
+	referencing := make(map[string]map[int64]bool)
+	subtree := []trashedRows{{table: table, ids: []int64{id}}}
+	for i := 0; i < len(subtree); i++ {
+		level := subtree[i]
+		desc := trashTables[level.table]
+		for _, ref := range desc.refs {
+			ids, err := refIDs(ctx, tx, ref, level.ids)
+			if err != nil {
+				return nil, nil, err
+			}
+			if referencing[ref.table] == nil {
+				referencing[ref.table] = make(map[int64]bool)
+			}
+			for _, id := range ids {
+				if !referencing[ref.table][id] {
+					referencing[ref.table][id] = true
+					dependents[ref.table]++
+				}
+			}
+		}
+		for _, child := range desc.children {
+			ids, err := childIDs(ctx, tx, child, level.ids, "deleted_at IS NULL")
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(ids) > 0 {
+				dependents[child.table] += len(ids)
+				subtree = append(subtree, trashedRows{table: child.table, ids: ids})
+			}
+		}
+	}
+	return subtree, dependents, nil
+}
+
+// setDeletedAt moves rows into the trash (AuditDelete) or back out of it
+// (AuditRestore), auditing each one.
+func setDeletedAt(ctx context.Context, tx *sql.Tx, action string, rows trashedRows, at string) error {
+	var value interface{}
+	if action == models.AuditDelete {
+		value = at
+	}
+	entity := trashTables[rows.table].entity
+	for _, id := range rows.ids {
+		before, err := snapshotRow(ctx, tx, rows.table, id)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE "+rows.table+" SET deleted_at = ? WHERE id = ?", value, id); err != nil {
+			return err
+		}
+		if err := recordRowChange(ctx, tx, action, entity, rows.table, id, before); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// childIDs locks and returns the rows of child under any of parentIDs that
+// also match cond.
+func childIDs(ctx context.Context, tx *sql.Tx, child trashRef, parentIDs []int64, cond string, condArgs ...interface{}) ([]int64, error) {
+	in, args := idList(parentIDs)
+	query := fmt.Sprintf("SELECT id FROM %s WHERE %s IN (%s) AND %s FOR UPDATE", child.table, child.column, in, cond)
+	return selectIDs(ctx, tx, query, append(args, condArgs...)...)
+}
+
+// refIDs returns the rows of ref.table pointing at any of ids.
+func refIDs(ctx context.Context, tx *sql.Tx, ref trashRef, ids []int64) ([]int64, error) {
+	in, args := idList(ids)
+	return selectIDs(ctx, tx, fmt.Sprintf("SELECT id FROM %s WHERE %s IN (%s)", ref.table, ref.column, in), args...)
+}
+
+func selectIDs(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func idList(ids []int64) (string, []interface{}) {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return strings.TrimSuffix(strings.Repeat("?,", len(ids)), ","), args
+}
+
+// requireLive locks a soft-deletable row, reporting a missing or trashed
+// one as not found.
+func requireLive(ctx context.Context, tx *sql.Tx, table string, id int64) error {
+	var live bool
+	err := tx.QueryRowContext(ctx, "SELECT deleted_at IS NULL FROM "+table+" WHERE id = ? FOR UPDATE", id).Scan(&live)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !live) {
+		return ErrMenuConfigNotFound
+	}
+	return err
+}
+
+// ListTrash returns trashed rows, most recently deleted first, optionally
+// only those of one entity.
+func (r *MenuConfigRepository) ListTrash(ctx context.Context, entity string, page, pageSize int) ([]menuconfigmodels.TrashItem, int, error) {
+	selects := make([]string, 0, len(trashOrder))
+	for _, table := range trashOrder {
+		desc := trashTables[table]
+		if entity != "" && entity != desc.entity {
+			continue
+		}
+		selects = append(selects, fmt.Sprintf(
+			"SELECT '%s' AS entity, id, %s AS name, deleted_at FROM %s WHERE deleted_at IS NOT NULL",
+			desc.entity, desc.name, table))
+	}
+	if len(selects) == 0 {
+		return nil, 0, ErrMenuConfigUnknownEntity
+	}
+	union := strings.Join(selects, " UNION ALL ")
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT entity, id, name, DATE_FORMAT(deleted_at, '%Y-%m-%dT%H:%i:%sZ') AS deleted_at
+		FROM (`+union+`) t
+		ORDER BY t.deleted_at DESC, entity, id DESC
+		LIMIT ? OFFSET ?`, pageSize, offsetFromPage(page, pageSize))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]menuconfigmodels.TrashItem, 0)
+	for rows.Next() {
+		var item menuconfigmodels.TrashItem
+		if err := rows.Scan(&item.Entity, &item.ID, &item.Name, &item.DeletedAt); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.count(ctx, "SELECT COUNT(*) FROM ("+union+") t")
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
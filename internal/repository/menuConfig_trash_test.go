@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashTablesLinkParentsAndChildren(t *testing.T) {
+	for table, desc := range trashTables {
+		for _, child := range desc.children {
+			childDesc, ok := trashTables[child.table]
+			if assert.True(t, ok, "%s has child %s without a trash", table, child.table) &&
+				assert.NotNil(t, childDesc.parent, "%s has no parent", child.table) {
+				assert.Equal(t, trashRef{table, child.column}, *childDesc.parent)
+			}
+		}
+	}
+	assert.Len(t, trashOrder, len(trashTables))
+}
+
+func TestDependentsErrorListsTablesByName(t *testing.T) {
+	err := &DependentsError{Dependents: map[string]int{"topics": 2, "questions": 14, "smart_notes": 1}}
+	assert.Equal(t, "menuconfig: still referenced by 14 questions, 1 smart_notes, 2 topics", err.Error())
+}
+
+func TestTrashedNameCanBeReused(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	menu := NewMenuConfigRepository(db)
+
+	name := fmt.Sprintf("Grade %d", time.Now().UnixNano())
+	old, err := menu.CreateGrade(ctx, GradeUpsert{Name: name})
+	require.NoError(t, err)
+	require.NoError(t, menu.DeleteGrade(ctx, old.ID, false))
+
+	_, err = menu.CreateGrade(ctx, GradeUpsert{Name: name})
+	require.NoError(t, err)
+
+	// the name is taken again, so the trashed grade can't come back as is
+	var dup *DuplicateError
+	assert.ErrorAs(t, menu.RestoreGrade(ctx, old.ID), &dup)
+}
@@ -1,85 +1,99 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
+	"github.com/tharindulakmal/sl-edu-service/internal/dedupe"
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+	"github.com/tharindulakmal/sl-edu-service/internal/irt"
 	"github.com/tharindulakmal/sl-edu-service/internal/models"
 )
 
+var ErrQuestionNotFound = errors.New("question: not found")
+
 type QuestionRepository interface {
 	GetByID(id int) (*models.Question, error)
 	GetList(filters map[string]interface{}, page, pageSize int) ([]models.Question, error)
-	Create(q *models.Question) (int64, error)
-	Update(q *models.Question) error
-	Delete(id int) error
+	Create(ctx context.Context, q *models.Question) (int64, error)
+	CreateMany(ctx context.Context, qs []models.Question) ([]int64, error)
+	Update(ctx context.Context, q *models.Question) error
+	// UpdateAsDraft saves q and, unless it is a draft already, sends it back
+	// to draft in the same transaction, recording the status change with the
+	// comment and who made it. q.Status is set to the question's new status.
+	UpdateAsDraft(ctx context.Context, q *models.Question, comment string, userID *int) error
+	Delete(ctx context.Context, id int) error
 	Count(filters map[string]interface{}) (int, error)
+	DuplicateCandidates(ctx context.Context, lessonID int) ([]dedupe.Item, error)
 }
 
 type questionRepository struct {
 	db *sql.DB
 }
 
+// questionYearExpr takes the year from the question's paper when it has one,
+// so the two cannot disagree. It binds the paper id, then the year id.
+const questionYearExpr = "COALESCE((SELECT year_id FROM past_papers WHERE id = ?), ?)"
+
 func NewQuestionRepository(db *sql.DB) QuestionRepository {
 	return &questionRepository{db: db}
 }
 
 func (r *questionRepository) GetByID(id int) (*models.Question, error) {
 	query := `SELECT id, grade_id, lesson_id, topic_id, subtopic_id, tutor_id, tute_id,
+					 year_id, paper_id, question_number,
 					 question, question_img_url, correct_answer, theory, solution,
-					 other_answers, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') as created_at
+					 other_answers, question_type, answer_spec, status, DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') as created_at,
+					 response_count, p_value, point_biserial, difficulty, discrimination
 			  FROM questions WHERE id = ?`
 
 	row := r.db.QueryRow(query, id)
 
 	var q models.Question
 	if err := row.Scan(&q.ID, &q.GradeID, &q.LessonID, &q.TopicID, &q.SubtopicID, &q.TutorID, &q.TuteID,
+		&q.YearID, &q.PaperID, &q.QuestionNumber,
 		&q.Question, &q.QuestionImg, &q.CorrectAnswer, &q.Theory, &q.Solution,
-		&q.OtherAnswers, &q.CreatedAt); err != nil {
+		&q.OtherAnswers, &q.Type, &q.Answer, &q.Status, &q.CreatedAt,
+		&q.ResponseCount, &q.PValue, &q.PointBiserial, &q.Difficulty, &q.Discrimination); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrQuestionNotFound
+		}
 		return nil, err
 	}
 	return &q, nil
 }
 
 func (r *questionRepository) GetList(filters map[string]interface{}, page, pageSize int) ([]models.Question, error) {
-	where := "1=1"
-	args := []interface{}{}
-
-	if gradeId, ok := filters["gradeId"]; ok {
-		where += " AND grade_id = ?"
-		args = append(args, gradeId)
+	where, args := questionWhere(filters)
+	order := "id ASC"
+	if _, ok := filters["paperId"]; ok {
+		// a single paper reads in its printed order
+		order = "question_number IS NULL, question_number ASC, id ASC"
 	}
-	if lessonId, ok := filters["lessonId"]; ok {
-		where += " AND lesson_id = ?"
-		args = append(args, lessonId)
-	}
-	if topicId, ok := filters["topicId"]; ok {
-		where += " AND topic_id = ?"
-		args = append(args, topicId)
-	}
-	if subtopicId, ok := filters["subtopicId"]; ok {
-		where += " AND subtopic_id = ?"
-		args = append(args, subtopicId)
-	}
-	if tutorId, ok := filters["tutorId"]; ok {
-		where += " AND tutor_id = ?"
-		args = append(args, tutorId)
-	}
-	if tuteId, ok := filters["tuteId"]; ok {
-		where += " AND tute_id = ?"
-		args = append(args, tuteId)
+	switch filters["sort"] {
+	case "difficulty":
+		order = "difficulty IS NULL, difficulty ASC, id ASC"
+	case "-difficulty":
+		order = "difficulty IS NULL, difficulty DESC, id ASC"
+	case "random":
+		// a fresh sample of the whole filtered pool on every call
+		order = "RAND()"
 	}
 
 	offset := (page - 1) * pageSize
 	query := fmt.Sprintf(`
 		SELECT id, grade_id, lesson_id, topic_id, subtopic_id, tutor_id, tute_id,
+		       year_id, paper_id, question_number,
 		       question, question_img_url, correct_answer, theory, solution,
-		       other_answers,
-		       DATE_FORMAT(created_at, '%%Y-%%m-%%dT%%H:%%i:%%sZ') as created_at
+		       other_answers, question_type, answer_spec, status,
+		       DATE_FORMAT(created_at, '%%Y-%%m-%%dT%%H:%%i:%%sZ') as created_at,
+		       response_count, p_value, point_biserial, difficulty, discrimination
 		FROM questions
 		WHERE %s
-		ORDER BY id ASC
-		LIMIT ? OFFSET ?`, where)
+		ORDER BY %s
+		LIMIT ? OFFSET ?`, where, order)
 
 	args = append(args, pageSize, offset)
 
@@ -93,8 +107,10 @@ func (r *questionRepository) GetList(filters map[string]interface{}, page, pageS
 	for rows.Next() {
 		var q models.Question
 		if err := rows.Scan(&q.ID, &q.GradeID, &q.LessonID, &q.TopicID, &q.SubtopicID, &q.TutorID, &q.TuteID,
+			&q.YearID, &q.PaperID, &q.QuestionNumber,
 			&q.Question, &q.QuestionImg, &q.CorrectAnswer, &q.Theory, &q.Solution,
-			&q.OtherAnswers, &q.CreatedAt); err != nil {
+			&q.OtherAnswers, &q.Type, &q.Answer, &q.Status, &q.CreatedAt,
+			&q.ResponseCount, &q.PValue, &q.PointBiserial, &q.Difficulty, &q.Discrimination); err != nil {
 			return nil, err
 		}
 		questions = append(questions, q)
@@ -102,77 +118,221 @@ func (r *questionRepository) GetList(filters map[string]interface{}, page, pageS
 	return questions, nil
 }
 
-func (r *questionRepository) Create(q *models.Question) (int64, error) {
-	query := `
+func (r *questionRepository) Create(ctx context.Context, q *models.Question) (int64, error) {
+	ids, err := r.CreateMany(ctx, []models.Question{*q})
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// CreateMany inserts all questions in a single transaction; if any insert
+// fails none of them are kept. Each insert is recorded in the audit log.
+func (r *questionRepository) CreateMany(ctx context.Context, qs []models.Question) ([]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
 		INSERT INTO questions (
 			lesson_id, grade_id, topic_id, subtopic_id, tutor_id, tute_id,
-			question, question_img_url, correct_answer, theory, solution, other_answers
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-
-	res, err := r.db.Exec(query,
-		q.LessonID, q.GradeID, q.TopicID, q.SubtopicID, q.TutorID, q.TuteID,
-		q.Question, q.QuestionImg, q.CorrectAnswer, q.Theory, q.Solution, q.OtherAnswers,
-	)
+			year_id, paper_id, question_number,
+			question, question_img_url, correct_answer, theory, solution, other_answers,
+			question_type, answer_spec
+		) VALUES (?, ?, ?, ?, ?, ?, `+questionYearExpr+`, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return res.LastInsertId()
+	defer stmt.Close()
+
+	ids := make([]int64, 0, len(qs))
+	for _, q := range qs {
+		res, err := stmt.ExecContext(ctx,
+			q.LessonID, q.GradeID, q.TopicID, q.SubtopicID, q.TutorID, q.TuteID,
+			q.PaperID, q.YearID, q.PaperID, q.QuestionNumber,
+			q.Question, q.QuestionImg, q.CorrectAnswer, q.Theory, q.Solution, q.OtherAnswers,
+			questionType(q.Type), q.Answer,
+		)
+		if err != nil {
+			return nil, err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		if err := recordRowChange(ctx, tx, models.AuditCreate, models.EntityQuestion, "questions", id, nil); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ids, nil
 }
 
-func (r *questionRepository) Update(q *models.Question) error {
-	query := `
+const updateQuestionQuery = `
 		UPDATE questions
 		SET lesson_id=?, grade_id=?, topic_id=?, subtopic_id=?, tutor_id=?, tute_id=?,
-		    question=?, question_img_url=?, correct_answer=?, theory=?, solution=?, other_answers=?
+		    year_id=` + questionYearExpr + `, paper_id=?, question_number=?,
+		    question=?, question_img_url=?, correct_answer=?, theory=?, solution=?, other_answers=?,
+		    question_type=?, answer_spec=?
 		WHERE id=?`
-	_, err := r.db.Exec(query,
+
+func updateQuestion(ctx context.Context, tx *sql.Tx, q *models.Question) error {
+	_, err := tx.ExecContext(ctx, updateQuestionQuery,
 		q.LessonID, q.GradeID, q.TopicID, q.SubtopicID, q.TutorID, q.TuteID,
+		q.PaperID, q.YearID, q.PaperID, q.QuestionNumber,
 		q.Question, q.QuestionImg, q.CorrectAnswer, q.Theory, q.Solution, q.OtherAnswers,
+		questionType(q.Type), q.Answer,
 		q.ID,
 	)
 	return err
 }
 
-func (r *questionRepository) Delete(id int) error {
-	_, err := r.db.Exec("DELETE FROM questions WHERE id = ?", id)
+func (r *questionRepository) Update(ctx context.Context, q *models.Question) error {
+	_, err := auditedChange(ctx, r.db, ErrQuestionNotFound, models.AuditUpdate, models.EntityQuestion, "questions", int64(q.ID), func(tx *sql.Tx) (int64, error) {
+		return int64(q.ID), updateQuestion(ctx, tx, q)
+	})
+	return err
+}
+
+func (r *questionRepository) UpdateAsDraft(ctx context.Context, q *models.Question, comment string, userID *int) error {
+	_, err := auditedChange(ctx, r.db, ErrQuestionNotFound, models.AuditUpdate, models.EntityQuestion, "questions", int64(q.ID), func(tx *sql.Tx) (int64, error) {
+		var from string
+		if err := tx.QueryRowContext(ctx, "SELECT status FROM questions WHERE id = ? FOR UPDATE", q.ID).Scan(&from); err != nil {
+			return 0, err
+		}
+		if err := updateQuestion(ctx, tx, q); err != nil {
+			return 0, err
+		}
+		q.Status = from
+		if from == editorial.StatusDraft {
+			return int64(q.ID), nil
+		}
+		to := editorial.StatusDraft
+		if _, err := tx.ExecContext(ctx, "UPDATE questions SET status = ? WHERE id = ?", to, q.ID); err != nil {
+			return 0, err
+		}
+		if _, err := insertEditorialEvent(ctx, tx, editorial.ContentQuestion, q.ID, &from, &to, &comment, userID); err != nil {
+			return 0, err
+		}
+		q.Status = to
+		return int64(q.ID), nil
+	})
+	return err
+}
+
+func (r *questionRepository) Delete(ctx context.Context, id int) error {
+	_, err := auditedChange(ctx, r.db, ErrQuestionNotFound, models.AuditDelete, models.EntityQuestion, "questions", int64(id), func(tx *sql.Tx) (int64, error) {
+		_, err := tx.ExecContext(ctx, "DELETE FROM questions WHERE id = ?", id)
+		return int64(id), err
+	})
 	return err
 }
 
 func (r *questionRepository) Count(filters map[string]interface{}) (int, error) {
-	where := "1=1"
-	args := []interface{}{}
+	where, args := questionWhere(filters)
 
-	if gradeId, ok := filters["gradeId"]; ok {
-		where += " AND grade_id = ?"
-		args = append(args, gradeId)
-	}
-	if lessonId, ok := filters["lessonId"]; ok {
-		where += " AND lesson_id = ?"
-		args = append(args, lessonId)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM questions WHERE %s", where)
+
+	var count int
+	if err := r.db.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, err
 	}
-	if topicId, ok := filters["topicId"]; ok {
-		where += " AND topic_id = ?"
-		args = append(args, topicId)
+	return count, nil
+}
+
+// DuplicateCandidates loads the text and answers of every question in a
+// lesson, or of the whole bank when lessonID is 0, for duplicate checks.
+func (r *questionRepository) DuplicateCandidates(ctx context.Context, lessonID int) ([]dedupe.Item, error) {
+	query := "SELECT id, lesson_id, question, correct_answer, other_answers FROM questions"
+	var args []interface{}
+	if lessonID != 0 {
+		query += " WHERE lesson_id = ?"
+		args = append(args, lessonID)
 	}
-	if subtopicId, ok := filters["subtopicId"]; ok {
-		where += " AND subtopic_id = ?"
-		args = append(args, subtopicId)
+	rows, err := r.db.QueryContext(ctx, query+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
 	}
-	if tutorId, ok := filters["tutorId"]; ok {
-		where += " AND tutor_id = ?"
-		args = append(args, tutorId)
+	defer rows.Close()
+
+	var items []dedupe.Item
+	for rows.Next() {
+		var q models.Question
+		if err := rows.Scan(&q.ID, &q.LessonID, &q.Question, &q.CorrectAnswer, &q.OtherAnswers); err != nil {
+			return nil, err
+		}
+		items = append(items, dedupe.ItemOf(q))
 	}
-	if tuteId, ok := filters["tuteId"]; ok {
-		where += " AND tute_id = ?"
-		args = append(args, tuteId)
+	return items, rows.Err()
+}
+
+// questionFilterColumns maps the filter keys accepted by GetList and Count to
+// the columns they match.
+var questionFilterColumns = []struct{ key, column string }{
+	{"gradeId", "grade_id"},
+	{"lessonId", "lesson_id"},
+	{"topicId", "topic_id"},
+	{"subtopicId", "subtopic_id"},
+	{"tutorId", "tutor_id"},
+	{"tuteId", "tute_id"},
+	{"yearId", "year_id"},
+	{"paperId", "paper_id"},
+	{"type", "question_type"},
+}
+
+// questionType stores questions without a type, such as imported ones, as
+// single choice.
+func questionType(t string) string {
+	if t == "" {
+		return models.QuestionTypeSingleChoice
 	}
+	return t
+}
 
-	query := fmt.Sprintf("SELECT COUNT(*) FROM questions WHERE %s", where)
+// AnyStatus as the "status" filter lists questions whatever their review
+// status. Without a status filter only published questions are listed.
+const AnyStatus = "any"
 
-	var count int
-	if err := r.db.QueryRow(query, args...).Scan(&count); err != nil {
-		return 0, err
+func questionWhere(filters map[string]interface{}) (string, []interface{}) {
+	where := "1=1"
+	args := []interface{}{}
+	switch status, ok := filters["status"]; {
+	case !ok:
+		where += " AND status = ?"
+		args = append(args, editorial.StatusPublished)
+	case status != AnyStatus:
+		where += " AND status = ?"
+		args = append(args, status)
 	}
-	return count, nil
+	where += " AND " + liveParents("questions")
+	for _, f := range questionFilterColumns {
+		if v, ok := filters[f.key]; ok {
+			where += " AND " + f.column + " = ?"
+			args = append(args, v)
+		}
+	}
+	// difficulty is a band of calibrated questions
+	switch filters["difficulty"] {
+	case irt.BandEasy:
+		where += " AND difficulty < ?"
+		args = append(args, irt.EasyBelow)
+	case irt.BandMedium:
+		where += " AND difficulty BETWEEN ? AND ?"
+		args = append(args, irt.EasyBelow, irt.HardAbove)
+	case irt.BandHard:
+		where += " AND difficulty > ?"
+		args = append(args, irt.HardAbove)
+	}
+	// paper is the paper number, e.g. paper II of every sitting
+	if paper, ok := filters["paper"]; ok {
+		where += " AND paper_id IN (SELECT id FROM past_papers WHERE paper_number = ?)"
+		args = append(args, paper)
+	}
+	return where, args
 }
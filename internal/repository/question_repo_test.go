@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	migrations "github.com/tharindulakmal/sl-edu-service/db/migrations"
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+	"github.com/tharindulakmal/sl-edu-service/internal/migrate"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	menuconfigmodels "github.com/tharindulakmal/sl-edu-service/internal/models/menuconfig"
+)
+
+// testDB connects to the MySQL database named by TEST_MYSQL_DSN and brings
+// it up to date. Tests that need one are skipped without it. Rows are given
+// unique names, so the database may be reused between runs.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_MYSQL_DSN is not set")
+	}
+	db, err := sql.Open("mysql", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	all, err := migrate.Load(migrations.FS)
+	require.NoError(t, err)
+	_, err = migrate.NewRunner(db, all).Up(context.Background())
+	require.NoError(t, err)
+	return db
+}
+
+// testLesson creates a lesson in a grade of its own.
+func testLesson(t *testing.T, menu *MenuConfigRepository) (*Grade, *menuconfigmodels.Lesson) {
+	t.Helper()
+	ctx := context.Background()
+	grade, err := menu.CreateGrade(ctx, GradeUpsert{Name: fmt.Sprintf("Grade %d", time.Now().UnixNano())})
+	require.NoError(t, err)
+	subject, err := menu.CreateSubject(ctx, SubjectUpsert{GradeID: grade.ID, Name: "Maths"})
+	require.NoError(t, err)
+	lesson, err := menu.CreateLesson(ctx, menuconfigmodels.LessonUpsert{SubjectID: subject.ID, Name: "Fractions"})
+	require.NoError(t, err)
+	return grade, lesson
+}
+
+func TestQuestionWhereSkipsTrashedParents(t *testing.T) {
+	where, _ := questionWhere(map[string]interface{}{})
+	for _, table := range []string{"lessons", "topics", "subtopics"} {
+		assert.Contains(t, where, "FROM "+table+" p WHERE p.id = questions.")
+	}
+}
+
+func TestTrashedLessonHidesItsQuestions(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	menu := NewMenuConfigRepository(db)
+	questions := NewQuestionRepository(db)
+
+	grade, lesson := testLesson(t, menu)
+	_, err := questions.Create(ctx, &models.Question{
+		GradeID: int(grade.ID), LessonID: int(lesson.ID), Question: "1/2 + 1/2 = ?", CorrectAnswer: "1",
+	})
+	require.NoError(t, err)
+
+	filters := map[string]interface{}{"lessonId": int(lesson.ID), "status": AnyStatus}
+	list, err := questions.GetList(filters, 1, 10)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	require.NoError(t, menu.DeleteLesson(ctx, lesson.ID, true))
+
+	list, err = questions.GetList(filters, 1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	count, err := questions.Count(filters)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestUpdateAsDraftSendsPublishedQuestionsBack(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	questions := NewQuestionRepository(db)
+	reviews := NewEditorialRepository(db)
+
+	grade, lesson := testLesson(t, NewMenuConfigRepository(db))
+	q := &models.Question{GradeID: int(grade.ID), LessonID: int(lesson.ID), Question: "1/2 + 1/2 = ?", CorrectAnswer: "1"}
+	id, err := questions.Create(ctx, q)
+	require.NoError(t, err)
+	q.ID = int(id)
+	_, err = reviews.Transition(ctx, editorial.ContentQuestion, q.ID, editorial.StatusPublished, nil, nil,
+		func(string) error { return nil })
+	require.NoError(t, err)
+
+	q.CorrectAnswer = "2/2"
+	require.NoError(t, questions.UpdateAsDraft(ctx, q, "edited by the author", nil))
+	assert.Equal(t, editorial.StatusDraft, q.Status)
+	saved, err := questions.GetByID(q.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "2/2", saved.CorrectAnswer)
+	assert.Equal(t, editorial.StatusDraft, saved.Status)
+
+	// a draft stays a draft without another status change
+	require.NoError(t, questions.UpdateAsDraft(ctx, q, "edited by the author", nil))
+	history, err := reviews.History(ctx, editorial.ContentQuestion, q.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, editorial.StatusDraft, *history[1].ToStatus)
+
+	q.ID = 0
+	assert.ErrorIs(t, questions.UpdateAsDraft(ctx, q, "edited by the author", nil), ErrQuestionNotFound)
+}
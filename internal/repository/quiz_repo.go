@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/quiz"
+)
+
+var (
+	ErrQuizAttemptNotFound  = errors.New("quiz: attempt not found")
+	ErrQuizAttemptSubmitted = errors.New("quiz: attempt already submitted")
+	ErrQuizAttemptExpired   = errors.New("quiz: attempt time limit exceeded")
+)
+
+type QuizRepository interface {
+	CreateAttempt(ctx context.Context, attempt *models.QuizAttempt) (int64, error)
+	GetAttempt(ctx context.Context, id int) (*models.QuizAttempt, error)
+	SubmitAttempt(ctx context.Context, attempt *models.QuizAttempt) error
+}
+
+type quizRepository struct {
+	db *sql.DB
+}
+
+func NewQuizRepository(db *sql.DB) QuizRepository {
+	return &quizRepository{db: db}
+}
+
+// CreateAttempt stores the attempt together with the questions and the
+// option order they were served in.
+func (r *quizRepository) CreateAttempt(ctx context.Context, attempt *models.QuizAttempt) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO quiz_attempts (student_id, grade_id, lesson_id, topic_id, subtopic_id, paper_id, blueprint_id, seed,
+		                           lang, status, total_questions, time_limit_minutes, deadline_at, late_policy)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP + INTERVAL ? MINUTE, ?)`,
+		attempt.StudentID, attempt.GradeID, attempt.LessonID, attempt.TopicID, attempt.SubtopicID, attempt.PaperID,
+		attempt.BlueprintID, attempt.Seed, attempt.Lang, models.QuizStatusInProgress, len(attempt.Questions),
+		attempt.TimeLimit, attempt.TimeLimit, attempt.LatePolicy,
+	)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO quiz_attempt_answers (attempt_id, question_id, position, options)
+		VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	for _, q := range attempt.Questions {
+		if _, err := stmt.ExecContext(ctx, id, q.QuestionID, q.Position, q.Options); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetAttempt loads an attempt with its questions, including the answer key.
+// Callers are responsible for redacting it before it reaches a student.
+func (r *quizRepository) GetAttempt(ctx context.Context, id int) (*models.QuizAttempt, error) {
+	var a models.QuizAttempt
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, student_id, grade_id, lesson_id, topic_id, subtopic_id, paper_id, blueprint_id, seed,
+		       lang, status, score, total_questions,
+		       DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at,
+		       DATE_FORMAT(submitted_at, '%Y-%m-%dT%H:%i:%sZ') AS submitted_at,
+		       time_limit_minutes, DATE_FORMAT(deadline_at, '%Y-%m-%dT%H:%i:%sZ') AS deadline_at, late_policy, late,
+		       COALESCE(CURRENT_TIMESTAMP > deadline_at + INTERVAL ? SECOND, FALSE) AS overdue
+		FROM quiz_attempts WHERE id = ?`, int(quiz.SubmitGrace.Seconds()), id,
+	).Scan(&a.ID, &a.StudentID, &a.GradeID, &a.LessonID, &a.TopicID, &a.SubtopicID, &a.PaperID, &a.BlueprintID, &a.Seed,
+		&a.Lang, &a.Status, &a.Score, &a.TotalQuestions, &a.CreatedAt, &a.SubmittedAt,
+		&a.TimeLimit, &a.DeadlineAt, &a.LatePolicy, &a.Late, &a.Overdue)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrQuizAttemptNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT qa.question_id, qa.position, q.question_type, q.question, q.question_img_url, qa.options,
+		       qa.selected_answer, qa.is_correct, q.correct_answer, q.theory, q.solution, q.answer_spec
+		FROM quiz_attempt_answers qa
+			INNER JOIN questions q ON q.id = qa.question_id
+		WHERE qa.attempt_id = ?
+		ORDER BY qa.position ASC`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	a.Questions = make([]models.QuizQuestion, 0, a.TotalQuestions)
+	for rows.Next() {
+		var q models.QuizQuestion
+		if err := rows.Scan(&q.QuestionID, &q.Position, &q.Type, &q.Question, &q.QuestionImg, &q.Options,
+			&q.SelectedAnswer, &q.IsCorrect, &q.CorrectAnswer, &q.Theory, &q.Solution, &q.Answer); err != nil {
+			return nil, err
+		}
+		q.Prompts = q.Answer.Prompts()
+		a.Questions = append(a.Questions, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// SubmitAttempt persists the graded answers and closes the attempt. An
+// attempt can only be submitted once.
+func (r *quizRepository) SubmitAttempt(ctx context.Context, attempt *models.QuizAttempt) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE quiz_attempts SET status = ?, score = ?, late = ?, submitted_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ?`,
+		models.QuizStatusSubmitted, attempt.Score, attempt.Late, attempt.ID, models.QuizStatusInProgress,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrQuizAttemptSubmitted
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		UPDATE quiz_attempt_answers SET selected_answer = ?, is_correct = ?, answered_at = CURRENT_TIMESTAMP
+		WHERE attempt_id = ? AND question_id = ?`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, q := range attempt.Questions {
+		if _, err := stmt.ExecContext(ctx, q.SelectedAnswer, q.IsCorrect, attempt.ID, q.QuestionID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
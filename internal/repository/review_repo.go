@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/review"
+)
+
+var (
+	ErrReviewCardNotFound = errors.New("review: card not found")
+	ErrReviewItemNotFound = errors.New("review: item not found")
+	ErrReviewTypeUnknown  = errors.New("review: unknown card type")
+)
+
+type ReviewRepository interface {
+	// Add puts an item in the student's deck, returning the existing card
+	// when it is already there.
+	Add(ctx context.Context, studentID int, cardType string, itemID int) (*models.ReviewCard, error)
+	// EnrollMissed adds wrongly answered questions to the deck. Questions
+	// already in it lapse and become due again.
+	EnrollMissed(ctx context.Context, studentID int, questionIDs []int) error
+	// Due lists the cards due before the end of the student's review day.
+	Due(ctx context.Context, studentID, limit int) ([]models.ReviewCard, error)
+	Grade(ctx context.Context, studentID, cardID int, grade review.Grade) (*models.ReviewCard, error)
+	Delete(ctx context.Context, studentID, cardID int) error
+}
+
+type reviewRepository struct {
+	db    *sql.DB
+	sched *review.Scheduler
+}
+
+func NewReviewRepository(db *sql.DB, sched *review.Scheduler) ReviewRepository {
+	return &reviewRepository{db: db, sched: sched}
+}
+
+// the card with both of its sides; cards whose item was deleted, is no
+// longer published or hangs from a trashed lesson, topic or subtopic are
+// skipped until it is back
+var reviewCardSelect = `
+	SELECT rc.id, rc.card_type, rc.item_id, rc.ease_factor, rc.interval_days, rc.repetitions, rc.lapses,
+	       rc.due_at, rc.last_reviewed_at,
+	       COALESCE(q.question, sn.sub_topic_name), COALESCE(q.correct_answer, sn.definition),
+	       COALESCE(q.solution, sn.theory)
+	FROM review_cards rc
+		LEFT JOIN questions q ON rc.card_type = 'question' AND q.id = rc.item_id
+			AND q.status = '` + editorial.StatusPublished + `' AND ` + liveParents("q") + `
+		LEFT JOIN smart_notes sn ON rc.card_type = 'smartnote' AND sn.id = rc.item_id
+			AND sn.status = '` + editorial.StatusPublished + `' AND ` + liveParents("sn") + `
+	WHERE (q.id IS NOT NULL OR sn.id IS NOT NULL)`
+
+func scanReviewCard(s interface{ Scan(...interface{}) error }) (models.ReviewCard, error) {
+	var c models.ReviewCard
+	var due time.Time
+	var reviewed sql.NullTime
+	if err := s.Scan(&c.ID, &c.CardType, &c.ItemID, &c.EaseFactor, &c.IntervalDays, &c.Repetitions, &c.Lapses,
+		&due, &reviewed, &c.Prompt, &c.Answer, &c.Explanation); err != nil {
+		return c, err
+	}
+	c.DueAt = formatReviewTime(due)
+	if reviewed.Valid {
+		t := formatReviewTime(reviewed.Time)
+		c.LastReviewedAt = &t
+	}
+	return c, nil
+}
+
+func formatReviewTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+func (r *reviewRepository) Add(ctx context.Context, studentID int, cardType string, itemID int) (*models.ReviewCard, error) {
+	var table string
+	switch cardType {
+	case models.ReviewCardQuestion:
+		table = "questions"
+	case models.ReviewCardSmartNote:
+		table = "smart_notes"
+	default:
+		return nil, ErrReviewTypeUnknown
+	}
+	// students can only study what is published
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM `+table+` i WHERE i.id = ? AND i.status = ? AND `+liveParents("i")+`)`,
+		itemID, editorial.StatusPublished).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrReviewItemNotFound
+	}
+
+	card := r.sched.New()
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT IGNORE INTO review_cards (student_id, card_type, item_id, ease_factor, interval_days, repetitions, lapses, due_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		studentID, cardType, itemID, card.EaseFactor, card.IntervalDays, card.Repetitions, card.Lapses, card.Due,
+	); err != nil {
+		return nil, err
+	}
+
+	c, err := scanReviewCard(r.db.QueryRowContext(ctx, reviewCardSelect+`
+		AND rc.student_id = ? AND rc.card_type = ? AND rc.item_id = ?`, studentID, cardType, itemID))
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *reviewRepository) EnrollMissed(ctx context.Context, studentID int, questionIDs []int) error {
+	if len(questionIDs) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// like Add, only published questions are enrolled
+	ids := make([]int64, len(questionIDs))
+	for i, id := range questionIDs {
+		ids[i] = int64(id)
+	}
+	in, args := idList(ids)
+	studiable, err := selectIDs(ctx, tx, `SELECT q.id FROM questions q WHERE q.id IN (`+in+`) AND q.status = ? AND `+liveParents("q"),
+		append(args, editorial.StatusPublished)...)
+	if err != nil {
+		return err
+	}
+	enroll := make(map[int]bool, len(studiable))
+	for _, id := range studiable {
+		enroll[int(id)] = true
+	}
+
+	for _, id := range questionIDs {
+		if !enroll[id] {
+			continue
+		}
+		cardID, card, err := r.lockCard(ctx, tx, `student_id = ? AND card_type = ? AND item_id = ?`,
+			studentID, models.ReviewCardQuestion, id)
+		switch {
+		case errors.Is(err, ErrReviewCardNotFound):
+			card = r.sched.New()
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO review_cards (student_id, card_type, item_id, ease_factor, interval_days, repetitions, lapses, due_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+				studentID, models.ReviewCardQuestion, id, card.EaseFactor, card.IntervalDays, card.Repetitions, card.Lapses, card.Due)
+		case err == nil:
+			err = r.saveCard(ctx, tx, cardID, r.sched.Lapse(card))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *reviewRepository) Due(ctx context.Context, studentID, limit int) ([]models.ReviewCard, error) {
+	rows, err := r.db.QueryContext(ctx, reviewCardSelect+`
+		AND rc.student_id = ? AND rc.due_at < ?
+		ORDER BY rc.due_at, rc.id
+		LIMIT ?`, studentID, r.sched.DueBy(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.ReviewCard, 0)
+	for rows.Next() {
+		c, err := scanReviewCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (r *reviewRepository) Grade(ctx context.Context, studentID, cardID int, grade review.Grade) (*models.ReviewCard, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, card, err := r.lockCard(ctx, tx, `id = ? AND student_id = ?`, cardID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	card, err = r.sched.Review(card, grade)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.saveCard(ctx, tx, cardID, card); err != nil {
+		return nil, err
+	}
+
+	c, err := scanReviewCard(tx.QueryRowContext(ctx, reviewCardSelect+` AND rc.id = ?`, cardID))
+	if errors.Is(err, sql.ErrNoRows) {
+		// the item was withdrawn; leave the card as it was
+		return nil, ErrReviewCardNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *reviewRepository) Delete(ctx context.Context, studentID, cardID int) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM review_cards WHERE id = ? AND student_id = ?`, cardID, studentID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrReviewCardNotFound
+	}
+	return nil
+}
+
+func (r *reviewRepository) lockCard(ctx context.Context, tx *sql.Tx, where string, args ...interface{}) (int, review.Card, error) {
+	var id int
+	var c review.Card
+	var reviewed sql.NullTime
+	err := tx.QueryRowContext(ctx, `
+		SELECT id, ease_factor, interval_days, repetitions, lapses, due_at, last_reviewed_at
+		FROM review_cards WHERE `+where+` FOR UPDATE`, args...,
+	).Scan(&id, &c.EaseFactor, &c.IntervalDays, &c.Repetitions, &c.Lapses, &c.Due, &reviewed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, c, ErrReviewCardNotFound
+	}
+	if err != nil {
+		return 0, c, err
+	}
+	if reviewed.Valid {
+		c.LastReviewed = &reviewed.Time
+	}
+	return id, c, nil
+}
+
+func (r *reviewRepository) saveCard(ctx context.Context, tx *sql.Tx, id int, c review.Card) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE review_cards
+		SET ease_factor = ?, interval_days = ?, repetitions = ?, lapses = ?, due_at = ?, last_reviewed_at = ?
+		WHERE id = ?`,
+		c.EaseFactor, c.IntervalDays, c.Repetitions, c.Lapses, c.Due, c.LastReviewed, id)
+	return err
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/review"
+)
+
+func TestReviewDeckDropsWithdrawnQuestions(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	questions := NewQuestionRepository(db)
+	reviews := NewReviewRepository(db, review.NewScheduler(review.SystemClock, review.SriLanka))
+
+	grade, lesson := testLesson(t, NewMenuConfigRepository(db))
+	ids, err := questions.CreateMany(ctx, []models.Question{
+		{GradeID: int(grade.ID), LessonID: int(lesson.ID), Question: "1/2 + 1/2 = ?", CorrectAnswer: "1"},
+		{GradeID: int(grade.ID), LessonID: int(lesson.ID), Question: "1/2 - 1/2 = ?", CorrectAnswer: "0"},
+	})
+	require.NoError(t, err)
+	published, draft := int(ids[0]), int(ids[1])
+	_, err = db.Exec("UPDATE questions SET status = ? WHERE id = ?", editorial.StatusPublished, published)
+	require.NoError(t, err)
+
+	// a deck of its own, so earlier runs don't show up in it
+	student := int(time.Now().UnixNano() % 1e9)
+	require.NoError(t, reviews.EnrollMissed(ctx, student, []int{published, draft}))
+	due, err := reviews.Due(ctx, student, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, published, due[0].ItemID)
+	cardID := due[0].ID
+
+	_, err = reviews.Add(ctx, student, models.ReviewCardQuestion, draft)
+	assert.ErrorIs(t, err, ErrReviewItemNotFound)
+
+	// a retired question takes its answer out of the deck with it
+	_, err = db.Exec("UPDATE questions SET status = ? WHERE id = ?", editorial.StatusRetired, published)
+	require.NoError(t, err)
+	due, err = reviews.Due(ctx, student, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	_, err = reviews.Grade(ctx, student, cardID, review.GradePass)
+	assert.ErrorIs(t, err, ErrReviewCardNotFound)
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/search"
+)
+
+// searchSource describes how one hit type is searched. from must join the
+// curriculum path using the aliases g, s, l, t and st; crumbs lists which of
+// them the type has. visible, when set, limits hits to content that is
+// served publicly. The tr* fields are the same columns on the translation
+// table, aliased tr.
+type searchSource struct {
+	from    string
+	id      string
+	title   string
+	body    string
+	match   string
+	crumbs  []string
+	visible string
+
+	trTable string
+	trKey   string
+	trTitle string
+	trBody  string
+	trMatch string
+}
+
+const (
+	lessonPath = " LEFT JOIN subjects s ON s.id = l.subject_id LEFT JOIN grades g ON g.id = s.grade_id"
+	topicPath  = " LEFT JOIN lessons l ON l.id = t.lesson_id" + lessonPath
+
+	// nothing under a trashed part of the curriculum is served
+	liveLesson   = "l.deleted_at IS NULL AND s.deleted_at IS NULL AND g.deleted_at IS NULL"
+	liveTopic    = "t.deleted_at IS NULL AND " + liveLesson
+	liveSubtopic = "st.deleted_at IS NULL AND " + liveTopic
+)
+
+var searchSources = map[string]searchSource{
+	models.EntityLesson: {
+		from:    "FROM lessons l" + lessonPath,
+		id:      "l.id",
+		title:   "l.name",
+		body:    "NULL",
+		match:   "l.name",
+		crumbs:  []string{"g", "s", "l"},
+		visible: liveLesson,
+		trTable: "lesson_translations", trKey: "lesson_id",
+		trTitle: "tr.name", trBody: "NULL", trMatch: "tr.name",
+	},
+	models.EntityTopic: {
+		from:    "FROM topics t" + topicPath,
+		id:      "t.id",
+		title:   "t.name",
+		body:    "NULL",
+		match:   "t.name",
+		crumbs:  []string{"g", "s", "l", "t"},
+		visible: liveTopic,
+		trTable: "topic_translations", trKey: "topic_id",
+		trTitle: "tr.name", trBody: "NULL", trMatch: "tr.name",
+	},
+	models.EntitySubtopic: {
+		from:    "FROM subtopics st LEFT JOIN topics t ON t.id = st.topic_id" + topicPath,
+		id:      "st.id",
+		title:   "st.name",
+		body:    "NULL",
+		match:   "st.name",
+		crumbs:  []string{"g", "s", "l", "t", "st"},
+		visible: liveSubtopic,
+		trTable: "subtopic_translations", trKey: "subtopic_id",
+		trTitle: "tr.name", trBody: "NULL", trMatch: "tr.name",
+	},
+	models.EntitySmartNote: {
+		from: "FROM smart_notes sn LEFT JOIN lessons l ON l.id = sn.lesson_id" + lessonPath +
+			" LEFT JOIN topics t ON t.id = sn.topic_id LEFT JOIN subtopics st ON st.id = sn.subtopic_id",
+		id:      "sn.id",
+		title:   "sn.sub_topic_name",
+		body:    "CONCAT_WS('\\n', sn.definition, sn.theory, sn.example)",
+		match:   "sn.sub_topic_name, sn.definition, sn.theory, sn.example",
+		crumbs:  []string{"g", "s", "l", "t", "st"},
+		visible: "sn.status = '" + editorial.StatusPublished + "' AND " + liveSubtopic,
+		trTable: "smart_note_translations", trKey: "smart_note_id",
+		trTitle: "tr.sub_topic_name",
+		trBody:  "CONCAT_WS('\\n', tr.definition, tr.theory, tr.example)",
+		trMatch: "tr.sub_topic_name, tr.definition, tr.theory, tr.example",
+	},
+	models.EntityQuestion: {
+		from: "FROM questions q LEFT JOIN lessons l ON l.id = q.lesson_id" + lessonPath +
+			" LEFT JOIN topics t ON t.id = q.topic_id LEFT JOIN subtopics st ON st.id = q.subtopic_id",
+		id:      "q.id",
+		title:   "q.question",
+		body:    "NULL",
+		match:   "q.question",
+		crumbs:  []string{"g", "s", "l", "t", "st"},
+		visible: "q.status = '" + editorial.StatusPublished + "' AND " + liveSubtopic,
+		trTable: "question_translations", trKey: "question_id",
+		trTitle: "tr.question", trBody: "NULL", trMatch: "tr.question",
+	},
+}
+
+// crumbTypes maps the path aliases to their breadcrumb types, in path order.
+var crumbTypes = []struct{ alias, typ string }{
+	{"g", models.EntityGrade},
+	{"s", models.EntitySubject},
+	{"l", models.EntityLesson},
+	{"t", models.EntityTopic},
+	{"st", models.EntitySubtopic},
+}
+
+// SearchRepository implements search.Index with MySQL FULLTEXT indexes in
+// natural language mode.
+type SearchRepository struct {
+	db *sql.DB
+}
+
+func NewSearchRepository(db *sql.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+func (r *SearchRepository) Search(ctx context.Context, q search.Query) ([]models.SearchHit, error) {
+	terms := search.Terms(q.Text)
+	if len(terms) == 0 {
+		return []models.SearchHit{}, nil
+	}
+	// every source returns enough rows to fill the page on its own
+	perSource := q.Offset + q.Limit
+
+	var hits []models.SearchHit
+	for _, typ := range search.Types {
+		if !q.Wants(typ) {
+			continue
+		}
+		src := searchSources[typ]
+		found, err := r.searchSource(ctx, typ, src, q.Text, "", perSource, terms)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, found...)
+
+		if q.Lang != "" && q.Lang != i18n.Base {
+			found, err := r.searchSource(ctx, typ, src, q.Text, q.Lang, perSource, terms)
+			if err != nil {
+				return nil, err
+			}
+			hits = append(hits, found...)
+		}
+	}
+	return search.Merge(hits, q.Offset, q.Limit), nil
+}
+
+// searchSource runs one FULLTEXT query, against the base text when lang is
+// empty and against the lang translations otherwise.
+func (r *SearchRepository) searchSource(ctx context.Context, typ string, src searchSource, text, lang string, limit int, terms []string) ([]models.SearchHit, error) {
+	title, body, match, from := src.title, src.body, src.match, src.from
+	args := []interface{}{text}
+	if lang != "" {
+		title, body, match = src.trTitle, src.trBody, src.trMatch
+		from += fmt.Sprintf(" INNER JOIN %s tr ON tr.%s = %s AND tr.lang = ?", src.trTable, src.trKey, src.id)
+		args = append(args, lang)
+	}
+	args = append(args, text, limit)
+
+	against := "MATCH(" + match + ") AGAINST (? IN NATURAL LANGUAGE MODE)"
+	where := against
+	if src.visible != "" {
+		where += " AND " + src.visible
+	}
+	query := fmt.Sprintf("SELECT %s, %s, %s, %s AS score, %s %s WHERE %s ORDER BY score DESC LIMIT ?",
+		src.id, title, body, against, crumbColumns(src.crumbs), from, where)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hits := make([]models.SearchHit, 0)
+	for rows.Next() {
+		var (
+			hit      = models.SearchHit{Type: typ}
+			bodyText sql.NullString
+			ids      [5]sql.NullInt64
+			names    [5]sql.NullString
+		)
+		dest := []interface{}{&hit.ID, &hit.Title, &bodyText, &hit.Score}
+		for i := range crumbTypes {
+			dest = append(dest, &ids[i], &names[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		hit.Snippet = search.BestSnippet(terms, bodyText.String, hit.Title)
+		hit.Breadcrumb = make([]models.SearchCrumb, 0, len(crumbTypes))
+		for i, c := range crumbTypes {
+			if ids[i].Valid {
+				hit.Breadcrumb = append(hit.Breadcrumb, models.SearchCrumb{Type: c.typ, ID: ids[i].Int64, Name: names[i].String})
+			}
+		}
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+func crumbColumns(aliases []string) string {
+	has := map[string]bool{}
+	for _, a := range aliases {
+		has[a] = true
+	}
+	cols := make([]string, 0, len(crumbTypes))
+	for _, c := range crumbTypes {
+		if has[c.alias] {
+			cols = append(cols, c.alias+".id, "+c.alias+".name")
+		} else {
+			cols = append(cols, "NULL, NULL")
+		}
+	}
+	return strings.Join(cols, ", ")
+}
@@ -0,0 +1,378 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+var (
+	ErrSmartNoteNotFound         = errors.New("smartnote: not found")
+	ErrSmartNoteRevisionNotFound = errors.New("smartnote: revision not found")
+	ErrSmartNoteInvalidScope     = errors.New("smartnote: lesson, topic and subtopic do not match")
+)
+
+// SmartNoteAdminRepository backs the smart note authoring endpoints. Every
+// change to an existing note first copies the current row into
+// smart_note_revisions so it can be inspected or restored later, and is
+// recorded in the audit log.
+type SmartNoteAdminRepository struct {
+	db *sql.DB
+}
+
+func NewSmartNoteAdminRepository(db *sql.DB) *SmartNoteAdminRepository {
+	return &SmartNoteAdminRepository{db: db}
+}
+
+const smartNoteColumns = `id, lesson_id, topic_id, subtopic_id, sub_topic_name, image_def_url, definition, theory,
+	image_theory_url, example, image_example_url, is_default, status, revision, updated_by,
+	DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at,
+	DATE_FORMAT(updated_at, '%Y-%m-%dT%H:%i:%sZ') AS updated_at`
+
+func scanSmartNote(row interface{ Scan(...interface{}) error }) (*models.SmartNoteRecord, error) {
+	var n models.SmartNoteRecord
+	if err := row.Scan(&n.ID, &n.LessonID, &n.TopicID, &n.SubtopicID, &n.SubTopicName, &n.ImageDefUrl,
+		&n.Definition, &n.Theory, &n.ImageTheoryUrl, &n.Example, &n.ImageExampleUrl, &n.IsDefault,
+		&n.Status, &n.Revision, &n.UpdatedBy, &n.CreatedAt, &n.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSmartNoteNotFound
+		}
+		return nil, err
+	}
+	return &n, nil
+}
+
+// List returns notes in every review status unless status is given.
+func (r *SmartNoteAdminRepository) List(ctx context.Context, lessonID, topicID, subtopicID *int64, status string, page, pageSize int) ([]models.SmartNoteRecord, int, error) {
+	filters := make([]string, 0)
+	args := make([]interface{}, 0)
+	if lessonID != nil {
+		filters = append(filters, "lesson_id = ?")
+		args = append(args, *lessonID)
+	}
+	if topicID != nil {
+		filters = append(filters, "topic_id = ?")
+		args = append(args, *topicID)
+	}
+	if subtopicID != nil {
+		filters = append(filters, "subtopic_id = ?")
+		args = append(args, *subtopicID)
+	}
+	if status != "" {
+		filters = append(filters, "status = ?")
+		args = append(args, status)
+	}
+	where := ""
+	if len(filters) > 0 {
+		where = " WHERE " + strings.Join(filters, " AND ")
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT "+smartNoteColumns+" FROM smart_notes"+where+" ORDER BY id DESC LIMIT ? OFFSET ?",
+		append(args, pageSize, offsetFromPage(page, pageSize))...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	notes := make([]models.SmartNoteRecord, 0)
+	for rows.Next() {
+		n, err := scanSmartNote(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		notes = append(notes, *n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM smart_notes"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return notes, total, nil
+}
+
+func (r *SmartNoteAdminRepository) Get(ctx context.Context, id int64) (*models.SmartNoteRecord, error) {
+	return scanSmartNote(r.db.QueryRowContext(ctx, "SELECT "+smartNoteColumns+" FROM smart_notes WHERE id = ?", id))
+}
+
+func (r *SmartNoteAdminRepository) Create(ctx context.Context, input models.SmartNoteUpsert, editorID *int64) (*models.SmartNoteRecord, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := checkSmartNoteScope(ctx, tx, input.LessonID, input.TopicID, input.SubtopicID); err != nil {
+		return nil, err
+	}
+	if input.IsDefault {
+		if err := clearLessonDefault(ctx, tx, input.LessonID, 0, editorID); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO smart_notes (lesson_id, topic_id, subtopic_id, sub_topic_name, image_def_url, definition, theory,
+		                         image_theory_url, example, image_example_url, is_default, updated_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		input.LessonID, input.TopicID, input.SubtopicID, strings.TrimSpace(input.SubTopicName), input.ImageDefUrl,
+		input.Definition, input.Theory, input.ImageTheoryUrl, input.Example, input.ImageExampleUrl,
+		input.IsDefault, editorID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	if err := recordRowChange(ctx, tx, models.AuditCreate, models.EntitySmartNote, "smart_notes", id, nil); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *SmartNoteAdminRepository) Update(ctx context.Context, id int64, input models.SmartNoteUpsert, editorID *int64) (*models.SmartNoteRecord, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockSmartNote(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	if err := checkSmartNoteScope(ctx, tx, input.LessonID, input.TopicID, input.SubtopicID); err != nil {
+		return nil, err
+	}
+	if input.IsDefault {
+		if err := clearLessonDefault(ctx, tx, input.LessonID, id, editorID); err != nil {
+			return nil, err
+		}
+	}
+	if err := applySmartNote(ctx, tx, id, input, editorID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *SmartNoteAdminRepository) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := snapshotRow(ctx, tx, "smart_notes", id)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return ErrSmartNoteNotFound
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM smart_notes WHERE id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM smart_note_revisions WHERE smart_note_id = ?", id); err != nil {
+		return err
+	}
+	if err := recordRowChange(ctx, tx, models.AuditDelete, models.EntitySmartNote, "smart_notes", id, before); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListRevisions returns the stored prior versions of a note, newest first.
+func (r *SmartNoteAdminRepository) ListRevisions(ctx context.Context, id int64) ([]models.SmartNoteRevision, error) {
+	if _, err := r.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, smart_note_id, revision, lesson_id, topic_id, subtopic_id, sub_topic_name, image_def_url,
+		       definition, theory, image_theory_url, example, image_example_url, is_default, edited_by,
+		       DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at
+		FROM smart_note_revisions
+		WHERE smart_note_id = ?
+		ORDER BY revision DESC`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]models.SmartNoteRevision, 0)
+	for rows.Next() {
+		var rev models.SmartNoteRevision
+		if err := rows.Scan(&rev.ID, &rev.SmartNoteID, &rev.Revision, &rev.LessonID, &rev.TopicID, &rev.SubtopicID,
+			&rev.SubTopicName, &rev.ImageDefUrl, &rev.Definition, &rev.Theory, &rev.ImageTheoryUrl, &rev.Example,
+			&rev.ImageExampleUrl, &rev.IsDefault, &rev.EditedBy, &rev.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+// Rollback restores the content of an earlier revision. The restore is itself
+// a new revision, so the version being replaced stays in the history.
+func (r *SmartNoteAdminRepository) Rollback(ctx context.Context, id int64, revision int, editorID *int64) (*models.SmartNoteRecord, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockSmartNote(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	var input models.SmartNoteUpsert
+	err = tx.QueryRowContext(ctx, `
+		SELECT lesson_id, topic_id, subtopic_id, sub_topic_name, image_def_url, definition, theory,
+		       image_theory_url, example, image_example_url, is_default
+		FROM smart_note_revisions
+		WHERE smart_note_id = ? AND revision = ?`, id, revision,
+	).Scan(&input.LessonID, &input.TopicID, &input.SubtopicID, &input.SubTopicName, &input.ImageDefUrl,
+		&input.Definition, &input.Theory, &input.ImageTheoryUrl, &input.Example, &input.ImageExampleUrl, &input.IsDefault)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSmartNoteRevisionNotFound
+		}
+		return nil, err
+	}
+	// the revision's topic or subtopic may have been trashed or moved since
+	if err := checkSmartNoteScope(ctx, tx, input.LessonID, input.TopicID, input.SubtopicID); err != nil {
+		return nil, err
+	}
+
+	if input.IsDefault {
+		if err := clearLessonDefault(ctx, tx, input.LessonID, id, editorID); err != nil {
+			return nil, err
+		}
+	}
+	if err := applySmartNote(ctx, tx, id, input, editorID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, id)
+}
+
+func lockSmartNote(ctx context.Context, tx *sql.Tx, id int64) error {
+	var exists int
+	if err := tx.QueryRowContext(ctx, "SELECT 1 FROM smart_notes WHERE id = ? FOR UPDATE", id).Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSmartNoteNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// snapshotSmartNote copies the current version of a note into the revision
+// history. It must run before the note row is modified.
+func snapshotSmartNote(ctx context.Context, tx *sql.Tx, id int64) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO smart_note_revisions (smart_note_id, revision, lesson_id, topic_id, subtopic_id, sub_topic_name,
+		                                  image_def_url, definition, theory, image_theory_url, example,
+		                                  image_example_url, is_default, edited_by)
+		SELECT id, revision, lesson_id, topic_id, subtopic_id, sub_topic_name, image_def_url, definition, theory,
+		       image_theory_url, example, image_example_url, is_default, updated_by
+		FROM smart_notes WHERE id = ?`, id)
+	return err
+}
+
+func applySmartNote(ctx context.Context, tx *sql.Tx, id int64, input models.SmartNoteUpsert, editorID *int64) error {
+	if err := snapshotSmartNote(ctx, tx, id); err != nil {
+		return err
+	}
+	before, err := snapshotRow(ctx, tx, "smart_notes", id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE smart_notes
+		SET lesson_id = ?, topic_id = ?, subtopic_id = ?, sub_topic_name = ?, image_def_url = ?, definition = ?,
+		    theory = ?, image_theory_url = ?, example = ?, image_example_url = ?, is_default = ?,
+		    revision = revision + 1, updated_by = ?
+		WHERE id = ?`,
+		input.LessonID, input.TopicID, input.SubtopicID, strings.TrimSpace(input.SubTopicName), input.ImageDefUrl,
+		input.Definition, input.Theory, input.ImageTheoryUrl, input.Example, input.ImageExampleUrl, input.IsDefault,
+		editorID, id,
+	)
+	if err != nil {
+		return err
+	}
+	return recordRowChange(ctx, tx, models.AuditUpdate, models.EntitySmartNote, "smart_notes", id, before)
+}
+
+// clearLessonDefault unsets the current default note of a lesson (other than
+// keepID) so a new default can take its place. The demoted note gets a
+// revision like any other edit.
+func clearLessonDefault(ctx context.Context, tx *sql.Tx, lessonID, keepID int64, editorID *int64) error {
+	var currentID int64
+	err := tx.QueryRowContext(ctx,
+		"SELECT id FROM smart_notes WHERE lesson_id = ? AND is_default = TRUE AND id <> ? FOR UPDATE",
+		lessonID, keepID,
+	).Scan(&currentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := snapshotSmartNote(ctx, tx, currentID); err != nil {
+		return err
+	}
+	before, err := snapshotRow(ctx, tx, "smart_notes", currentID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		"UPDATE smart_notes SET is_default = FALSE, revision = revision + 1, updated_by = ? WHERE id = ?",
+		editorID, currentID,
+	)
+	if err != nil {
+		return err
+	}
+	return recordRowChange(ctx, tx, models.AuditUpdate, models.EntitySmartNote, "smart_notes", currentID, before)
+}
+
+// checkSmartNoteScope verifies the lesson exists and that the topic and
+// subtopic, when given, belong to it. A subtopic is only checked together
+// with its topic.
+func checkSmartNoteScope(ctx context.Context, tx *sql.Tx, lessonID int64, topicID, subtopicID *int64) error {
+	var exists int
+	query := "SELECT 1 FROM lessons l"
+	args := make([]interface{}, 0, 3)
+	if topicID != nil {
+		query += " INNER JOIN topics t ON t.lesson_id = l.id AND t.id = ? AND t.deleted_at IS NULL"
+		args = append(args, *topicID)
+		if subtopicID != nil {
+			query += " INNER JOIN subtopics st ON st.topic_id = t.id AND st.id = ? AND st.deleted_at IS NULL"
+			args = append(args, *subtopicID)
+		}
+	}
+	query += " WHERE l.id = ? AND l.deleted_at IS NULL LIMIT 1"
+	args = append(args, lessonID)
+
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSmartNoteInvalidScope
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+func TestSmartNoteRevisionsAndRollback(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	menu := NewMenuConfigRepository(db)
+	notes := NewSmartNoteAdminRepository(db)
+
+	_, lesson := testLesson(t, menu)
+	topic, err := menu.CreateTopic(ctx, TopicUpsert{LessonID: lesson.ID, Name: "Adding fractions"})
+	require.NoError(t, err)
+
+	input := models.SmartNoteUpsert{LessonID: lesson.ID, TopicID: &topic.ID, SubTopicName: "v1"}
+	note, err := notes.Create(ctx, input, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, note.Revision)
+
+	input.SubTopicName = "v2"
+	_, err = notes.Update(ctx, note.ID, input, nil)
+	require.NoError(t, err)
+	input.SubTopicName = "v3"
+	note, err = notes.Update(ctx, note.ID, input, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, note.Revision)
+
+	revisions, err := notes.ListRevisions(ctx, note.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, 2, revisions[0].Revision)
+	assert.Equal(t, "v2", revisions[0].SubTopicName)
+	assert.Equal(t, 1, revisions[1].Revision)
+
+	// a rollback is a new revision, keeping the version it replaces
+	note, err = notes.Rollback(ctx, note.ID, 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", note.SubTopicName)
+	assert.Equal(t, 4, note.Revision)
+	revisions, err = notes.ListRevisions(ctx, note.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "v3", revisions[0].SubTopicName)
+
+	_, err = notes.Rollback(ctx, note.ID, 9, nil)
+	assert.ErrorIs(t, err, ErrSmartNoteRevisionNotFound)
+
+	// the revisions point at a topic that is now in the trash
+	require.NoError(t, menu.DeleteTopic(ctx, topic.ID, true))
+	_, err = notes.Rollback(ctx, note.ID, 2, nil)
+	assert.ErrorIs(t, err, ErrSmartNoteInvalidScope)
+}
+
+func TestNewDefaultSmartNoteDemotesTheOld(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	notes := NewSmartNoteAdminRepository(db)
+	_, lesson := testLesson(t, NewMenuConfigRepository(db))
+
+	first, err := notes.Create(ctx, models.SmartNoteUpsert{LessonID: lesson.ID, SubTopicName: "first", IsDefault: true}, nil)
+	require.NoError(t, err)
+	second, err := notes.Create(ctx, models.SmartNoteUpsert{LessonID: lesson.ID, SubTopicName: "second", IsDefault: true}, nil)
+	require.NoError(t, err)
+	assert.True(t, second.IsDefault)
+
+	// the demotion is recorded as an edit of the old default
+	first, err = notes.Get(ctx, first.ID)
+	require.NoError(t, err)
+	assert.False(t, first.IsDefault)
+	assert.Equal(t, 2, first.Revision)
+	revisions, err := notes.ListRevisions(ctx, first.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.True(t, revisions[0].IsDefault)
+}
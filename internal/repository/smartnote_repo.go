@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
 	"github.com/tharindulakmal/sl-edu-service/internal/models"
 )
 
@@ -21,15 +22,22 @@ func NewSmartNoteRepository(db *sql.DB) SmartNoteRepositoryInterface {
 func (r *SmartNoteRepository) GetSmartNote(gradeID, subjectID, lessonID int, topicID, subID *int) (models.SmartNote, error) {
 	var sn models.SmartNote
 	query := `
-		SELECT sn.sub_topic_name, sn.image_def_url, sn.definition, sn.theory,
+		SELECT sn.id, sn.sub_topic_name, sn.image_def_url, sn.definition, sn.theory,
 		       sn.image_theory_url, sn.example, sn.image_example_url
 		FROM smart_notes sn
 			INNER JOIN lessons l ON sn.lesson_id = l.id
 			INNER JOIN subjects s ON l.subject_id = s.id
+			LEFT JOIN topics t ON sn.topic_id = t.id
+			LEFT JOIN subtopics st ON sn.subtopic_id = st.id
 		WHERE sn.lesson_id = ?
 		  AND l.subject_id = ?
-		  AND s.grade_id = ?`
-	args := []interface{}{lessonID, subjectID, gradeID}
+		  AND s.grade_id = ?
+		  AND sn.status = ?
+		  AND l.deleted_at IS NULL
+		  AND s.deleted_at IS NULL
+		  AND t.deleted_at IS NULL
+		  AND st.deleted_at IS NULL`
+	args := []interface{}{lessonID, subjectID, gradeID, editorial.StatusPublished}
 	if topicID != nil {
 		query += " AND sn.topic_id = ?"
 		args = append(args, *topicID)
@@ -41,6 +49,7 @@ func (r *SmartNoteRepository) GetSmartNote(gradeID, subjectID, lessonID int, top
 	query += " ORDER BY sn.id LIMIT 1"
 
 	err := r.DB.QueryRow(query, args...).Scan(
+		&sn.ID,
 		&sn.SubTopicName,
 		&sn.ImageDefUrl,
 		&sn.Definition,
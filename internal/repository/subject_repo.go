@@ -15,7 +15,7 @@ func NewSubjectRepository(db *sql.DB) *SubjectRepository {
 }
 
 func (r *SubjectRepository) GetSubjectsByGradeID(gradeID int) ([]models.Subject, error) {
-	rows, err := r.DB.Query("SELECT id, name FROM subjects WHERE grade_id = ?", gradeID)
+	rows, err := r.DB.Query("SELECT id, name FROM subjects WHERE grade_id = ? AND deleted_at IS NULL", gradeID)
 	if err != nil {
 		return nil, err
 	}
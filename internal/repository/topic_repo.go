@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 
+	"github.com/tharindulakmal/sl-edu-service/internal/editorial"
 	"github.com/tharindulakmal/sl-edu-service/internal/models"
 )
 
@@ -21,7 +22,7 @@ func NewTopicRepository(db *sql.DB) TopicRepositoryInterface {
 
 func (r *TopicRepository) GetTopicsByLesson(lessonID int) ([]models.Topic, error) {
 	// fetch topics -- schema now stores topic label in `name`
-	rows, err := r.DB.Query("SELECT id, name FROM topics WHERE lesson_id = ? ORDER BY created_at", lessonID)
+	rows, err := r.DB.Query("SELECT id, name FROM topics WHERE lesson_id = ? AND deleted_at IS NULL ORDER BY created_at", lessonID)
 	if err != nil {
 		return nil, err
 	}
@@ -33,25 +34,38 @@ func (r *TopicRepository) GetTopicsByLesson(lessonID int) ([]models.Topic, error
 		if err := rows.Scan(&t.TopicID, &t.TopicName); err != nil {
 			return nil, err
 		}
+		topics = append(topics, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-		// fetch subtopics for this topic
-		subRows, err := r.DB.Query("SELECT id, topic_id, name FROM subtopics WHERE topic_id = ? ORDER BY created_at", t.TopicID)
-		if err != nil {
-			return nil, err
-		}
+	// fetch the subtopics of all topics at once
+	subRows, err := r.DB.Query(`
+		SELECT st.id, st.topic_id, st.name
+		FROM subtopics st
+			INNER JOIN topics t ON t.id = st.topic_id
+		WHERE t.lesson_id = ? AND st.deleted_at IS NULL AND t.deleted_at IS NULL
+		ORDER BY st.created_at`, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	defer subRows.Close()
 
-		var subs []models.SubTopic
-		for subRows.Next() {
-			var s models.SubTopic
-			if err := subRows.Scan(&s.SubTopicID, &s.TopicID, &s.SubTopicName); err != nil {
-				return nil, err
-			}
-			subs = append(subs, s)
+	subsByTopic := make(map[int][]models.SubTopic)
+	for subRows.Next() {
+		var s models.SubTopic
+		if err := subRows.Scan(&s.SubTopicID, &s.TopicID, &s.SubTopicName); err != nil {
+			return nil, err
 		}
-		subRows.Close()
+		subsByTopic[s.TopicID] = append(subsByTopic[s.TopicID], s)
+	}
+	if err := subRows.Err(); err != nil {
+		return nil, err
+	}
 
-		t.SubTopicList = subs
-		topics = append(topics, t)
+	for i := range topics {
+		topics[i].SubTopicList = subsByTopic[topics[i].TopicID]
 	}
 
 	return topics, nil
@@ -60,11 +74,11 @@ func (r *TopicRepository) GetTopicsByLesson(lessonID int) ([]models.Topic, error
 func (r *TopicRepository) GetDefaultSmartNote(lessonID int) (models.SmartNote, error) {
 	var sn models.SmartNote
 	err := r.DB.QueryRow(`
-        SELECT sub_topic_name, image_def_url, definition, theory, image_theory_url, example, image_example_url
+        SELECT id, sub_topic_name, image_def_url, definition, theory, image_theory_url, example, image_example_url
         FROM smart_notes
-        WHERE lesson_id = ? AND is_default = TRUE LIMIT 1
-    `, lessonID).Scan(
-		&sn.SubTopicName, &sn.ImageDefUrl, &sn.Definition, &sn.Theory,
+        WHERE lesson_id = ? AND is_default = TRUE AND status = ? LIMIT 1
+    `, lessonID, editorial.StatusPublished).Scan(
+		&sn.ID, &sn.SubTopicName, &sn.ImageDefUrl, &sn.Definition, &sn.Theory,
 		&sn.ImageTheoryUrl, &sn.Example, &sn.ImageExampleUrl,
 	)
 	return sn, err
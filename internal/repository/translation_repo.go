@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+var (
+	ErrTranslationNotFound       = errors.New("translation: not found")
+	ErrTranslationEntityNotFound = errors.New("translation: entity not found")
+	ErrTranslationEntityUnknown  = errors.New("translation: unknown entity")
+)
+
+// translationTable describes where the translations of one entity live.
+// fields returns pointers into a TranslationFields in the order of columns.
+type translationTable struct {
+	table   string
+	key     string
+	source  string
+	label   string
+	columns []string
+	fields  func(f *models.TranslationFields) []interface{}
+}
+
+var translationTables = map[string]translationTable{
+	models.EntityGrade:    nameTranslationTable("grade_translations", "grade_id", "grades"),
+	models.EntitySubject:  nameTranslationTable("subject_translations", "subject_id", "subjects"),
+	models.EntityLesson:   nameTranslationTable("lesson_translations", "lesson_id", "lessons"),
+	models.EntityTopic:    nameTranslationTable("topic_translations", "topic_id", "topics"),
+	models.EntitySubtopic: nameTranslationTable("subtopic_translations", "subtopic_id", "subtopics"),
+	models.EntitySmartNote: {
+		table:   "smart_note_translations",
+		key:     "smart_note_id",
+		source:  "smart_notes",
+		label:   "sub_topic_name",
+		columns: []string{"sub_topic_name", "definition", "theory", "example"},
+		fields: func(f *models.TranslationFields) []interface{} {
+			return []interface{}{&f.SubTopicName, &f.Definition, &f.Theory, &f.Example}
+		},
+	},
+	models.EntityQuestion: {
+		table:   "question_translations",
+		key:     "question_id",
+		source:  "questions",
+		label:   "question",
+		columns: []string{"question", "correct_answer", "other_answers", "answer_spec", "theory", "solution"},
+		fields: func(f *models.TranslationFields) []interface{} {
+			return []interface{}{&f.Question, &f.CorrectAnswer, &f.OtherAnswers, &f.Answer, &f.Theory, &f.Solution}
+		},
+	},
+}
+
+func nameTranslationTable(table, key, source string) translationTable {
+	return translationTable{
+		table:   table,
+		key:     key,
+		source:  source,
+		label:   "name",
+		columns: []string{"name"},
+		fields: func(f *models.TranslationFields) []interface{} {
+			return []interface{}{&f.Name}
+		},
+	}
+}
+
+// TranslationRepository stores per-language text for curriculum entities,
+// smart notes and questions. It implements i18n.Store.
+type TranslationRepository struct {
+	db *sql.DB
+}
+
+func NewTranslationRepository(db *sql.DB) *TranslationRepository {
+	return &TranslationRepository{db: db}
+}
+
+func lookupTranslationTable(entity string) (translationTable, error) {
+	t, ok := translationTables[entity]
+	if !ok {
+		return translationTable{}, ErrTranslationEntityUnknown
+	}
+	return t, nil
+}
+
+// Translations returns the lang translations of the given entity ids.
+func (r *TranslationRepository) Translations(ctx context.Context, entity, lang string, ids []int64) (map[int64]models.TranslationFields, error) {
+	t, err := lookupTranslationTable(entity)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int64]models.TranslationFields, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, 0, len(ids)+1)
+	args = append(args, lang)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	query := "SELECT " + t.key + ", " + strings.Join(t.columns, ", ") + " FROM " + t.table +
+		" WHERE lang = ? AND " + t.key + " IN (" + placeholders + ")"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var f models.TranslationFields
+		if err := rows.Scan(append([]interface{}{&id}, t.fields(&f)...)...); err != nil {
+			return nil, err
+		}
+		out[id] = f
+	}
+	return out, rows.Err()
+}
+
+func (r *TranslationRepository) selectTranslations(t translationTable) string {
+	return "SELECT " + t.key + ", lang, " + strings.Join(t.columns, ", ") +
+		", updated_by, DATE_FORMAT(updated_at, '%Y-%m-%dT%H:%i:%sZ') AS updated_at FROM " + t.table
+}
+
+func scanTranslation(row interface{ Scan(...interface{}) error }, entity string, t translationTable) (*models.Translation, error) {
+	tr := models.Translation{Entity: entity}
+	dest := append([]interface{}{&tr.EntityID, &tr.Lang}, t.fields(&tr.TranslationFields)...)
+	dest = append(dest, &tr.UpdatedBy, &tr.UpdatedAt)
+	if err := row.Scan(dest...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTranslationNotFound
+		}
+		return nil, err
+	}
+	return &tr, nil
+}
+
+// List returns every translation of one entity.
+func (r *TranslationRepository) List(ctx context.Context, entity string, id int64) ([]models.Translation, error) {
+	t, err := lookupTranslationTable(entity)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkEntity(ctx, t, id); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, r.selectTranslations(t)+" WHERE "+t.key+" = ? ORDER BY lang", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := make([]models.Translation, 0)
+	for rows.Next() {
+		tr, err := scanTranslation(rows, entity, t)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, *tr)
+	}
+	return translations, rows.Err()
+}
+
+func (r *TranslationRepository) Get(ctx context.Context, entity string, id int64, lang string) (*models.Translation, error) {
+	t, err := lookupTranslationTable(entity)
+	if err != nil {
+		return nil, err
+	}
+	row := r.db.QueryRowContext(ctx, r.selectTranslations(t)+" WHERE "+t.key+" = ? AND lang = ?", id, lang)
+	return scanTranslation(row, entity, t)
+}
+
+// Upsert creates or replaces the translation of tr.EntityID in tr.Lang.
+func (r *TranslationRepository) Upsert(ctx context.Context, tr models.Translation) (*models.Translation, error) {
+	t, err := lookupTranslationTable(tr.Entity)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkEntity(ctx, t, tr.EntityID); err != nil {
+		return nil, err
+	}
+
+	columns := append([]string{t.key, "lang"}, t.columns...)
+	columns = append(columns, "updated_by")
+	args := []interface{}{tr.EntityID, tr.Lang}
+	for _, f := range t.fields(&tr.TranslationFields) {
+		args = append(args, translationValue(f))
+	}
+	args = append(args, tr.UpdatedBy)
+
+	updates := make([]string, 0, len(columns)-2)
+	for _, col := range columns[2:] {
+		updates = append(updates, col+" = VALUES("+col+")")
+	}
+
+	query := "INSERT INTO " + t.table + " (" + strings.Join(columns, ", ") + ") VALUES (" +
+		strings.TrimSuffix(strings.Repeat("?,", len(columns)), ",") + ") ON DUPLICATE KEY UPDATE " +
+		strings.Join(updates, ", ")
+	err = r.auditedChange(ctx, t, tr.Entity, tr.EntityID, tr.Lang, false, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, tr.Entity, tr.EntityID, tr.Lang)
+}
+
+func (r *TranslationRepository) Delete(ctx context.Context, entity string, id int64, lang string) error {
+	t, err := lookupTranslationTable(entity)
+	if err != nil {
+		return err
+	}
+	return r.auditedChange(ctx, t, entity, id, lang, true, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "DELETE FROM "+t.table+" WHERE "+t.key+" = ? AND lang = ?", id, lang)
+		return err
+	})
+}
+
+// auditedChange runs change to the lang translation of id together with its
+// audit log entry, the way the package-level auditedChange does for tables
+// keyed by id. The entry is recorded against the translated entity as
+// "<entity>_translation"; a delete of a missing translation is reported as
+// ErrTranslationNotFound.
+func (r *TranslationRepository) auditedChange(ctx context.Context, t translationTable, entity string, id int64, lang string, deleting bool, change func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	where := t.key + " = ? AND lang = ?"
+	before, err := snapshotWhere(ctx, tx, t.table, where, id, lang)
+	if err != nil {
+		return err
+	}
+	action := models.AuditUpdate
+	switch {
+	case deleting && before == nil:
+		return ErrTranslationNotFound
+	case deleting:
+		action = models.AuditDelete
+	case before == nil:
+		action = models.AuditCreate
+	}
+	if err := change(tx); err != nil {
+		return err
+	}
+	var after json.RawMessage
+	if !deleting {
+		if after, err = snapshotWhere(ctx, tx, t.table, where, id, lang); err != nil {
+			return err
+		}
+	}
+	if err := recordAudit(ctx, tx, action, entity+"_translation", id, before, after); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListMissing pages through the entities that have no lang translation yet.
+func (r *TranslationRepository) ListMissing(ctx context.Context, entity, lang string, page, pageSize int) ([]models.MissingTranslation, int, error) {
+	t, err := lookupTranslationTable(entity)
+	if err != nil {
+		return nil, 0, err
+	}
+	from := " FROM " + t.source + " e WHERE NOT EXISTS (SELECT 1 FROM " + t.table + " t WHERE t." + t.key + " = e.id AND t.lang = ?)"
+
+	rows, err := r.db.QueryContext(ctx, "SELECT e.id, e."+t.label+from+" ORDER BY e.id LIMIT ? OFFSET ?",
+		lang, pageSize, offsetFromPage(page, pageSize))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	missing := make([]models.MissingTranslation, 0)
+	for rows.Next() {
+		m := models.MissingTranslation{Entity: entity}
+		if err := rows.Scan(&m.EntityID, &m.BaseText); err != nil {
+			return nil, 0, err
+		}
+		missing = append(missing, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*)"+from, lang).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return missing, total, nil
+}
+
+func (r *TranslationRepository) checkEntity(ctx context.Context, t translationTable, id int64) error {
+	var exists int
+	err := r.db.QueryRowContext(ctx, "SELECT 1 FROM "+t.source+" WHERE id = ? LIMIT 1", id).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTranslationEntityNotFound
+	}
+	return err
+}
+
+// translationValue turns a field pointer from translationTable.fields into a
+// query argument.
+func translationValue(field interface{}) interface{} {
+	switch v := field.(type) {
+	case **string:
+		return *v
+	case *models.StringArray:
+		return *v
+	case **models.AnswerSpec:
+		return *v
+	}
+	return field
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user: not found")
+	ErrUserEmailExists = errors.New("user: email already registered")
+)
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	List(ctx context.Context, page, pageSize int) ([]models.User, int, error)
+	Create(ctx context.Context, u *models.User) (int64, error)
+	Update(ctx context.Context, u *models.User) error
+	CountByRole(ctx context.Context, role string) (int, error)
+}
+
+type userRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+const userColumns = `id, email, name, password_hash, role, tutor_id,
+	DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ') AS created_at`
+
+func scanUser(row interface{ Scan(...interface{}) error }) (*models.User, error) {
+	var u models.User
+	if err := row.Scan(&u.ID, &u.Email, &u.Name, &u.PasswordHash, &u.Role, &u.TutorID, &u.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *userRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
+	return scanUser(r.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE id = ?", id))
+}
+
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return scanUser(r.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE email = ?", normalizeEmail(email)))
+}
+
+func (r *userRepository) List(ctx context.Context, page, pageSize int) ([]models.User, int, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+userColumns+" FROM users ORDER BY id DESC LIMIT ? OFFSET ?",
+		pageSize, offsetFromPage(page, pageSize))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, *u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (r *userRepository) Create(ctx context.Context, u *models.User) (int64, error) {
+	id, err := auditedChange(ctx, r.db, ErrUserNotFound, models.AuditCreate, models.EntityUser, "users", 0, func(tx *sql.Tx) (int64, error) {
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO users (email, name, password_hash, role, tutor_id) VALUES (?, ?, ?, ?, ?)",
+			normalizeEmail(u.Email), strings.TrimSpace(u.Name), u.PasswordHash, u.Role, u.TutorID,
+		)
+		if err != nil {
+			return 0, err
+		}
+		return res.LastInsertId()
+	})
+	if isDuplicateKey(err) {
+		return 0, ErrUserEmailExists
+	}
+	return id, err
+}
+
+func (r *userRepository) Update(ctx context.Context, u *models.User) error {
+	_, err := auditedChange(ctx, r.db, ErrUserNotFound, models.AuditUpdate, models.EntityUser, "users", int64(u.ID), func(tx *sql.Tx) (int64, error) {
+		_, err := tx.ExecContext(ctx,
+			"UPDATE users SET email = ?, name = ?, password_hash = ?, role = ?, tutor_id = ? WHERE id = ?",
+			normalizeEmail(u.Email), strings.TrimSpace(u.Name), u.PasswordHash, u.Role, u.TutorID, u.ID,
+		)
+		return int64(u.ID), err
+	})
+	if isDuplicateKey(err) {
+		return ErrUserEmailExists
+	}
+	return err
+}
+
+func (r *userRepository) CountByRole(ctx context.Context, role string) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE role = ?", role).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// isDuplicateKey reports whether err is a MySQL unique-key violation.
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
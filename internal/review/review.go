@@ -0,0 +1,125 @@
+// Package review schedules spaced-repetition cards with the SM-2 algorithm.
+// It has no database or HTTP concerns; time comes from an injectable Clock
+// so schedules can be tested deterministically.
+package review
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Grade is how well a student recalled a card, from 0 (complete blackout)
+// to 5 (perfect recall). Grades below GradePass count as a lapse.
+type Grade int
+
+const (
+	GradeBlackout Grade = 0
+	GradePass     Grade = 3
+	GradePerfect  Grade = 5
+)
+
+var ErrInvalidGrade = errors.New("review: grade must be between 0 and 5")
+
+const (
+	initialEase = 2.5
+	minEase     = 1.3
+)
+
+// Card is the scheduling state of one review card.
+type Card struct {
+	EaseFactor   float64
+	IntervalDays int
+	Repetitions  int
+	Lapses       int
+	Due          time.Time
+	LastReviewed *time.Time
+}
+
+// Clock tells the scheduler what time it is.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// SystemClock reads the wall clock.
+var SystemClock Clock = systemClock{}
+
+// SriLanka is the time zone review days are counted in by default.
+var SriLanka = time.FixedZone("Asia/Colombo", 5*60*60+30*60)
+
+type Scheduler struct {
+	clock Clock
+	loc   *time.Location
+}
+
+// NewScheduler returns a scheduler that reads the time from clock and
+// starts a new review day at midnight in loc.
+func NewScheduler(clock Clock, loc *time.Location) *Scheduler {
+	return &Scheduler{clock: clock, loc: loc}
+}
+
+// Now is the current time in UTC, as stored on cards.
+func (s *Scheduler) Now() time.Time {
+	return s.clock.Now().UTC()
+}
+
+// DueBy is the end of the current review day: every card due before it
+// belongs in today's queue.
+func (s *Scheduler) DueBy() time.Time {
+	now := s.clock.Now().In(s.loc)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.loc)
+	return midnight.AddDate(0, 0, 1).UTC()
+}
+
+// New returns a card for a freshly enrolled item, due straight away.
+func (s *Scheduler) New() Card {
+	return Card{EaseFactor: initialEase, Due: s.Now()}
+}
+
+// Review applies a recall grade to c. A passing grade grows the interval to
+// 1 day, then 6 days, then by the ease factor; a failing grade starts the
+// card over at 1 day. The ease factor moves with every grade but never
+// drops below 1.3.
+func (s *Scheduler) Review(c Card, g Grade) (Card, error) {
+	if g < GradeBlackout || g > GradePerfect {
+		return c, ErrInvalidGrade
+	}
+	now := s.Now()
+
+	if g >= GradePass {
+		switch c.Repetitions {
+		case 0:
+			c.IntervalDays = 1
+		case 1:
+			c.IntervalDays = 6
+		default:
+			c.IntervalDays = int(math.Round(float64(c.IntervalDays) * c.EaseFactor))
+		}
+		c.Repetitions++
+	} else {
+		c.Repetitions = 0
+		c.IntervalDays = 1
+		c.Lapses++
+	}
+
+	miss := float64(GradePerfect - g)
+	c.EaseFactor = math.Max(minEase, c.EaseFactor+0.1-miss*(0.08+miss*0.02))
+	c.Due = now.AddDate(0, 0, c.IntervalDays)
+	c.LastReviewed = &now
+	return c, nil
+}
+
+// Lapse records that the item behind c was got wrong again outside review,
+// such as in a quiz. The card starts over and is due straight away.
+func (s *Scheduler) Lapse(c Card) Card {
+	lastReviewed := c.LastReviewed
+	c, _ = s.Review(c, GradeBlackout)
+	c.IntervalDays = 0
+	c.Due = s.Now()
+	c.LastReviewed = lastReviewed
+	return c
+}
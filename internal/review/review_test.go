@@ -0,0 +1,99 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedClock struct{ t time.Time }
+
+func (c *fixedClock) Now() time.Time { return c.t }
+
+func (c *fixedClock) advance(days int) { c.t = c.t.AddDate(0, 0, days) }
+
+func newTestScheduler() (*Scheduler, *fixedClock) {
+	clock := &fixedClock{t: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)}
+	return NewScheduler(clock, SriLanka), clock
+}
+
+func TestNewCardIsDueNow(t *testing.T) {
+	s, clock := newTestScheduler()
+	c := s.New()
+	assert.Equal(t, clock.t, c.Due)
+	assert.Equal(t, 2.5, c.EaseFactor)
+	assert.Nil(t, c.LastReviewed)
+}
+
+func TestReviewGrowsIntervals(t *testing.T) {
+	s, clock := newTestScheduler()
+	c := s.New()
+
+	var intervals []int
+	for i := 0; i < 4; i++ {
+		var err error
+		c, err = s.Review(c, 4)
+		require.NoError(t, err)
+		intervals = append(intervals, c.IntervalDays)
+		assert.Equal(t, clock.t.AddDate(0, 0, c.IntervalDays), c.Due)
+		clock.advance(c.IntervalDays)
+	}
+	assert.Equal(t, []int{1, 6, 15, 38}, intervals)
+	assert.Equal(t, 4, c.Repetitions)
+	assert.InDelta(t, 2.5, c.EaseFactor, 1e-9, "grade 4 keeps the ease factor")
+}
+
+func TestFailedReviewStartsOver(t *testing.T) {
+	s, clock := newTestScheduler()
+	c := s.New()
+	c, _ = s.Review(c, 5)
+	c, _ = s.Review(c, 5)
+	require.Equal(t, 6, c.IntervalDays)
+
+	c, err := s.Review(c, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, c.Repetitions)
+	assert.Equal(t, 1, c.IntervalDays)
+	assert.Equal(t, 1, c.Lapses)
+	assert.Equal(t, clock.t.AddDate(0, 0, 1), c.Due)
+	assert.InDelta(t, 2.16, c.EaseFactor, 1e-9)
+}
+
+func TestEaseFactorHasAFloor(t *testing.T) {
+	s, _ := newTestScheduler()
+	c := s.New()
+	for i := 0; i < 10; i++ {
+		c, _ = s.Review(c, 0)
+	}
+	assert.Equal(t, 1.3, c.EaseFactor)
+}
+
+func TestReviewRejectsInvalidGrades(t *testing.T) {
+	s, _ := newTestScheduler()
+	_, err := s.Review(s.New(), 6)
+	assert.ErrorIs(t, err, ErrInvalidGrade)
+	_, err = s.Review(s.New(), -1)
+	assert.ErrorIs(t, err, ErrInvalidGrade)
+}
+
+func TestLapseMakesCardDueNow(t *testing.T) {
+	s, clock := newTestScheduler()
+	c, _ := s.Review(s.New(), 5)
+	reviewed := c.LastReviewed
+
+	clock.advance(3)
+	c = s.Lapse(c)
+	assert.Equal(t, clock.t, c.Due)
+	assert.Equal(t, 0, c.Repetitions)
+	assert.Equal(t, 1, c.Lapses)
+	assert.Equal(t, reviewed, c.LastReviewed)
+}
+
+func TestDueByIsLocalMidnight(t *testing.T) {
+	clock := &fixedClock{t: time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)}
+	s := NewScheduler(clock, SriLanka)
+	// 20:00 UTC is already 01:30 on 2 March in Colombo
+	assert.Equal(t, time.Date(2024, 3, 2, 18, 30, 0, 0, time.UTC), s.DueBy())
+}
@@ -1,62 +1,188 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tharindulakmal/sl-edu-service/internal/auth"
+	"github.com/tharindulakmal/sl-edu-service/internal/config"
 	menuhandler "github.com/tharindulakmal/sl-edu-service/internal/handler"
 	"github.com/tharindulakmal/sl-edu-service/internal/handlers"
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/importer"
+	"github.com/tharindulakmal/sl-edu-service/internal/mastery"
+	"github.com/tharindulakmal/sl-edu-service/internal/metrics"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
 	"github.com/tharindulakmal/sl-edu-service/internal/repository"
+	"github.com/tharindulakmal/sl-edu-service/internal/review"
+	"github.com/tharindulakmal/sl-edu-service/internal/worksheet"
 )
 
-func RegisterRoutes(router *gin.Engine, db *sql.DB) {
-	api := router.Group("/api/v1")
+func RegisterRoutes(router *gin.Engine, db *sql.DB, tokens *auth.TokenManager, cfg config.Config) {
+	// routes added before this point, like the health probes, aren't measured
+	router.Use(metrics.Middleware())
+	metrics.Default.Register(metrics.NewDBStats(db))
+	router.GET("/metrics", metrics.Default.Handler)
 
-	admin := api.Group("/admin")
+	api := router.Group("/api/v1", i18n.Middleware())
+	translationRepo := repository.NewTranslationRepository(db)
+
+	userRepo := repository.NewUserRepository(db)
+	authHandler := handlers.NewAuthHandler(userRepo, tokens)
+	authGroup := api.Group("/auth")
+	{
+		authGroup.POST("/register", authHandler.Register)
+		authGroup.POST("/login", authHandler.Login)
+		authGroup.GET("/me", auth.Authenticate(tokens), authHandler.Me)
+	}
+
+	admin := api.Group("/admin", auth.Authenticate(tokens), auth.RequireRole(models.RoleContentEditor))
 	menuhandler.RegisterAdminMenuConfigRoutes(admin, db)
+	menuhandler.RegisterAdminSmartNoteRoutes(admin, db)
+	menuhandler.RegisterAdminTranslationRoutes(admin, db)
+
+	calibrationHandler := handlers.NewCalibrationHandler(repository.NewCalibrationRepository(db))
+	admin.GET("/questions/calibration", calibrationHandler.Report)
+	admin.GET("/audit", handlers.NewAuditHandler(repository.NewAuditRepository(db)).List)
+
+	// account management and server settings are limited to admins
+	users := admin.Group("/users", auth.RequireRole())
+	{
+		users.GET("", authHandler.ListUsers)
+		users.POST("", authHandler.CreateUser)
+		users.PUT("/:id", authHandler.UpdateUser)
+	}
+	admin.GET("/config", auth.RequireRole(), handlers.NewConfigHandler(cfg).Get)
 
 	// Grades
 	gradeRepo := repository.NewGradeRepository(db)
-	gradeHandler := handlers.NewGradeHandler(gradeRepo)
+	gradeHandler := handlers.NewGradeHandler(gradeRepo, translationRepo)
 	grades := api.Group("/grades")
 	{
 		grades.GET("", gradeHandler.GetGrades)
 	}
 
 	subjectRepo := repository.NewSubjectRepository(db)
-	subjectHandler := handlers.NewSubjectHandler(subjectRepo)
+	subjectHandler := handlers.NewSubjectHandler(subjectRepo, translationRepo)
 	subjects := api.Group("/subjects")
 	{
 		subjects.GET("", subjectHandler.GetSubjectsByGrade)
 	}
 
 	lessonRepo := repository.NewLessonRepository(db)
-	lessonHandler := handlers.NewLessonHandler(lessonRepo)
+	lessonHandler := handlers.NewLessonHandler(lessonRepo, translationRepo)
 	lessons := api.Group("/lessons")
 
 	lessons.GET("", lessonHandler.GetLessons)
 
 	topicRepo := repository.NewTopicRepository(db)
-	topicHandler := handlers.NewTopicHandler(topicRepo)
+	topicHandler := handlers.NewTopicHandler(topicRepo, translationRepo)
 
 	api.GET("/tutor/topics", topicHandler.GetTopics)
 
+	curriculumHandler := handlers.NewCurriculumHandler(repository.NewCurriculumRepository(db), translationRepo)
+	api.GET("/curriculum/tree", curriculumHandler.GetTree)
+
 	smartNoteRepo := repository.NewSmartNoteRepository(db)
-	smartNoteHandler := handlers.NewSmartNoteHandler(smartNoteRepo)
+	smartNoteHandler := handlers.NewSmartNoteHandler(smartNoteRepo, translationRepo)
 
 	api.GET("/note/smartnote", smartNoteHandler.GetSmartNote)
 
 	questionRepo := repository.NewQuestionRepository(db)
-	questionHandler := handlers.NewQuestionHandler(questionRepo)
+	questionHandler := handlers.NewQuestionHandler(questionRepo, repository.NewEditorialRepository(db), translationRepo)
+	importHandler := handlers.NewQuestionImportHandler(importer.New(repository.NewMenuConfigRepository(db), questionRepo))
+	worksheetHandler := handlers.NewWorksheetHandler(questionRepo, worksheet.NewHTTPLoader())
+	admin.GET("/questions/duplicates", questionHandler.DuplicateClusters)
+	admin.GET("/questions", questionHandler.GetAllQuestions)
+	admin.GET("/questions/:id", questionHandler.GetAnyQuestionByID)
 
 	question := api.Group("/mcq")
 	{
 		question.GET("/questions/:id", questionHandler.GetQuestionByID)
 		question.GET("/questions", questionHandler.GetQuestions)
 
-		question.POST("/questions", questionHandler.CreateQuestion)
-		question.PUT("/questions/:id", questionHandler.UpdateQuestion)
-		question.DELETE("/questions/:id", questionHandler.DeleteQuestion)
+		authoring := question.Group("", auth.Authenticate(tokens), auth.RequireRole(models.RoleContentEditor, models.RoleTutor))
+		authoring.POST("/questions", questionHandler.CreateQuestion)
+		authoring.POST("/questions/import", importHandler.ImportQuestions)
+		authoring.GET("/questions/export", questionHandler.ExportQuestions)
+		authoring.GET("/questions/worksheet", worksheetHandler.GetWorksheet)
+		authoring.GET("/questions/worksheet/answer-key", worksheetHandler.GetAnswerKey)
+		authoring.PUT("/questions/:id", questionHandler.UpdateQuestion)
+		authoring.DELETE("/questions/:id", questionHandler.DeleteQuestion)
+		authoring.POST("/questions/:id/status", questionHandler.ChangeStatus)
+		authoring.POST("/questions/:id/comments", questionHandler.AddComment)
+		authoring.GET("/questions/:id/history", questionHandler.GetHistory)
+	}
+
+	paperHandler := handlers.NewPaperHandler(repository.NewMenuConfigRepository(db), questionRepo, translationRepo)
+	papers := api.Group("/papers")
+	{
+		papers.GET("", paperHandler.GetPapers)
+		papers.GET("/:id", paperHandler.GetPaper)
+	}
+
+	quizRepo := repository.NewQuizRepository(db)
+	masteryRepo := repository.NewMasteryRepository(db, mastery.DefaultModel)
+	reviewRepo := repository.NewReviewRepository(db, review.NewScheduler(review.SystemClock, review.SriLanka))
+	examRepo := repository.NewExamRepository(db)
+	quizHandler := handlers.NewQuizHandler(questionRepo, quizRepo, examRepo, masteryRepo, reviewRepo, translationRepo)
+	attempts := question.Group("/attempts", auth.OptionalAuthenticate(tokens))
+	{
+		attempts.POST("", quizHandler.StartAttempt)
+		attempts.GET("/:id", quizHandler.GetAttempt)
+		attempts.POST("/:id/submit", quizHandler.SubmitAttempt)
+	}
+
+	examHandler := handlers.NewExamHandler(examRepo, questionRepo, translationRepo)
+	blueprints := api.Group("/exams/blueprints", auth.Authenticate(tokens), auth.RequireRole(models.RoleContentEditor, models.RoleTutor))
+	{
+		blueprints.GET("", examHandler.ListBlueprints)
+		blueprints.POST("", examHandler.CreateBlueprint)
+		blueprints.GET("/:id", examHandler.GetBlueprint)
+		blueprints.PUT("/:id", examHandler.UpdateBlueprint)
+		blueprints.DELETE("/:id", examHandler.DeleteBlueprint)
+		blueprints.GET("/:id/generate", examHandler.Generate)
+	}
+
+	practiceHandler := handlers.NewPracticeHandler(questionRepo, masteryRepo, reviewRepo, translationRepo, mastery.DefaultModel)
+	practice := question.Group("/practice", auth.Authenticate(tokens))
+	{
+		practice.GET("/next", practiceHandler.Next)
+		practice.POST("/answer", practiceHandler.Answer)
+		practice.GET("/mastery", practiceHandler.Mastery)
 	}
 
+	reviewHandler := handlers.NewReviewHandler(reviewRepo, translationRepo)
+	reviews := api.Group("/review", auth.Authenticate(tokens))
+	{
+		reviews.GET("/due", reviewHandler.GetDue)
+		reviews.POST("/cards", reviewHandler.AddCard)
+		reviews.POST("/cards/:id/grade", reviewHandler.GradeCard)
+		reviews.DELETE("/cards/:id", reviewHandler.DeleteCard)
+	}
+
+	searchHandler := handlers.NewSearchHandler(repository.NewSearchRepository(db), translationRepo)
+	api.GET("/search", searchHandler.Search)
+}
+
+// EnsureAdmin creates the first admin account from the given credentials when
+// no admin exists yet, so a fresh deployment can be bootstrapped.
+func EnsureAdmin(ctx context.Context, db *sql.DB, email, password string) error {
+	users := repository.NewUserRepository(db)
+	count, err := users.CountByRole(ctx, models.RoleAdmin)
+	if err != nil || count > 0 {
+		return err
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	_, err = users.Create(ctx, &models.User{
+		Email:        email,
+		Name:         "Administrator",
+		PasswordHash: hash,
+		Role:         models.RoleAdmin,
+	})
+	return err
 }
@@ -0,0 +1,107 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+// Document is one searchable text in a MemoryIndex. Lang is empty for base
+// text and a language code for a translation of the same entity.
+type Document struct {
+	Type       string
+	ID         int64
+	Lang       string
+	Title      string
+	Body       string
+	Breadcrumb []models.SearchCrumb
+}
+
+// titleWeight makes a word in the title count for more than one in the body,
+// like a subtopic named after the search term outranking a passing mention.
+const titleWeight = 2
+
+type posting struct {
+	doc int
+	tf  float64
+}
+
+// MemoryIndex is an in-process inverted index ranking documents by TF-IDF.
+// It is safe for concurrent use.
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	docs     []Document
+	postings map[string][]posting
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{postings: map[string][]posting{}}
+}
+
+// Add indexes docs. Adding the same entity twice indexes it twice; rebuild
+// the index to replace a document.
+func (m *MemoryIndex) Add(docs ...Document) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, d := range docs {
+		idx := len(m.docs)
+		m.docs = append(m.docs, d)
+
+		freq := map[string]float64{}
+		for _, t := range tokenize([]rune(d.Title)) {
+			freq[t.word] += titleWeight
+		}
+		for _, t := range tokenize([]rune(d.Body)) {
+			freq[t.word]++
+		}
+		for word, tf := range freq {
+			m.postings[word] = append(m.postings[word], posting{doc: idx, tf: tf})
+		}
+	}
+}
+
+func (m *MemoryIndex) Search(ctx context.Context, q Query) ([]models.SearchHit, error) {
+	terms := Terms(q.Text)
+	if len(terms) == 0 {
+		return []models.SearchHit{}, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	scores := map[int]float64{}
+	for _, term := range terms {
+		list := m.postings[term]
+		if len(list) == 0 {
+			continue
+		}
+		idf := math.Log(1 + float64(len(m.docs))/float64(len(list)))
+		for _, p := range list {
+			d := m.docs[p.doc]
+			if !q.Wants(d.Type) || (d.Lang != "" && d.Lang != q.Lang) {
+				continue
+			}
+			scores[p.doc] += p.tf * idf
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	hits := make([]models.SearchHit, 0, len(scores))
+	for idx, score := range scores {
+		d := m.docs[idx]
+		hits = append(hits, models.SearchHit{
+			Type:       d.Type,
+			ID:         d.ID,
+			Title:      d.Title,
+			Snippet:    BestSnippet(terms, d.Body, d.Title),
+			Score:      score,
+			Breadcrumb: d.Breadcrumb,
+		})
+	}
+	return Merge(hits, q.Offset, q.Limit), nil
+}
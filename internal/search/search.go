@@ -0,0 +1,240 @@
+// Package search defines the site-wide search over lessons, topics,
+// subtopics, smart notes and questions. Index has a MySQL FULLTEXT
+// implementation in the repository package and an in-process MemoryIndex
+// used in tests and small deployments.
+package search
+
+import (
+	"context"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+// Types lists the hit types in the order they are searched.
+var Types = []string{
+	models.EntityLesson,
+	models.EntityTopic,
+	models.EntitySubtopic,
+	models.EntitySmartNote,
+	models.EntityQuestion,
+}
+
+// SnippetWidth is the approximate number of characters in a snippet.
+const SnippetWidth = 160
+
+// Query is a search request. Text is searched in the base language and, when
+// Lang is another language, in that language's translations too. An empty
+// Types searches everything.
+type Query struct {
+	Text   string
+	Lang   string
+	Types  []string
+	Limit  int
+	Offset int
+}
+
+// Wants reports whether hits of typ are requested.
+func (q Query) Wants(typ string) bool {
+	if len(q.Types) == 0 {
+		return true
+	}
+	for _, t := range q.Types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+type Index interface {
+	Search(ctx context.Context, q Query) ([]models.SearchHit, error)
+}
+
+// ValidType reports whether typ is a searchable hit type.
+func ValidType(typ string) bool {
+	for _, t := range Types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	// marks carry Sinhala and Tamil vowel signs, ZWJ joins Sinhala conjuncts
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '\u200d'
+}
+
+type token struct {
+	start, end int // rune offsets
+	word       string
+}
+
+func tokenize(runes []rune) []token {
+	var tokens []token
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && isWordRune(runes[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			word := make([]rune, i-start)
+			for j := range word {
+				word[j] = unicode.ToLower(runes[start+j])
+			}
+			tokens = append(tokens, token{start: start, end: i, word: string(word)})
+			start = -1
+		}
+	}
+	return tokens
+}
+
+// Terms splits text into distinct lower-cased search terms, dropping
+// single-character words.
+func Terms(text string) []string {
+	seen := map[string]bool{}
+	var terms []string
+	for _, t := range tokenize([]rune(text)) {
+		if len([]rune(t.word)) < 2 || seen[t.word] {
+			continue
+		}
+		seen[t.word] = true
+		terms = append(terms, t.word)
+	}
+	return terms
+}
+
+// Snippet cuts a window of about width characters around the first matching
+// term of text, escapes it for HTML and wraps every matching word in <mark>.
+// Without a match it returns the start of text.
+func Snippet(text string, terms []string, width int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	tokens := tokenize(runes)
+	match := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		match[t] = true
+	}
+
+	first := -1
+	for _, t := range tokens {
+		if match[t.word] {
+			first = t.start
+			break
+		}
+	}
+
+	from, to := 0, len(runes)
+	if first >= 0 && len(runes) > width {
+		from = first - width/3
+		if from < 0 {
+			from = 0
+		}
+	}
+	if to-from > width {
+		to = from + width
+	}
+	// do not cut words in half
+	for from > 0 && isWordRune(runes[from-1]) && isWordRune(runes[from]) {
+		from--
+	}
+	for to < len(runes) && isWordRune(runes[to-1]) && isWordRune(runes[to]) {
+		to++
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, t := range tokens {
+		if t.start < from || t.end > to || !match[t.word] {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:t.start])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[t.start:t.end])))
+		b.WriteString("</mark>")
+		pos = t.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:to])))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// BestSnippet returns the Snippet of the first of texts that contains one of
+// terms, falling back to the first non-empty text.
+func BestSnippet(terms []string, texts ...string) string {
+	fallback := ""
+	for _, text := range texts {
+		if text == "" {
+			continue
+		}
+		if fallback == "" {
+			fallback = text
+		}
+		for _, t := range tokenize([]rune(text)) {
+			for _, term := range terms {
+				if t.word == term {
+					return Snippet(text, terms, SnippetWidth)
+				}
+			}
+		}
+	}
+	return Snippet(fallback, terms, SnippetWidth)
+}
+
+// Merge drops duplicate hits for the same entity, keeping the best scoring
+// one, orders the rest by score and returns the requested page.
+func Merge(hits []models.SearchHit, offset, limit int) []models.SearchHit {
+	best := make(map[string]int, len(hits))
+	unique := make([]models.SearchHit, 0, len(hits))
+	for _, h := range hits {
+		key := h.Type + ":" + strconv.FormatInt(h.ID, 10)
+		if i, ok := best[key]; ok {
+			if h.Score > unique[i].Score {
+				unique[i] = h
+			}
+			continue
+		}
+		best[key] = len(unique)
+		unique = append(unique, h)
+	}
+
+	sort.SliceStable(unique, func(i, j int) bool {
+		if unique[i].Score != unique[j].Score {
+			return unique[i].Score > unique[j].Score
+		}
+		if unique[i].Type != unique[j].Type {
+			return typeOrder(unique[i].Type) < typeOrder(unique[j].Type)
+		}
+		return unique[i].ID < unique[j].ID
+	})
+
+	if offset >= len(unique) {
+		return []models.SearchHit{}
+	}
+	unique = unique[offset:]
+	if limit > 0 && len(unique) > limit {
+		unique = unique[:limit]
+	}
+	return unique
+}
+
+func typeOrder(typ string) int {
+	for i, t := range Types {
+		if t == typ {
+			return i
+		}
+	}
+	return len(Types)
+}
@@ -0,0 +1,94 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+func TestTerms(t *testing.T) {
+	assert.Equal(t, []string{"pythagoras", "theorem"}, Terms("Pythagoras' theorem, a THEOREM"))
+	// vowel signs and the zero width joiner stay inside Sinhala words
+	assert.Equal(t, []string{"ත්‍රිකෝණ", "වර්ගඵලය"}, Terms("ත්‍රිකෝණ වර්ගඵලය"))
+	assert.Empty(t, Terms("a + b"))
+}
+
+func TestSnippetHighlightsAndEscapes(t *testing.T) {
+	got := Snippet("If a < b then the <b>square</b> of a Square is", []string{"square"}, 200)
+	assert.Equal(t, "If a &lt; b then the &lt;b&gt;<mark>square</mark>&lt;/b&gt; of a <mark>Square</mark> is", got)
+}
+
+func TestSnippetWindowsAroundFirstMatch(t *testing.T) {
+	text := strings.Repeat("filler words here ", 20) + "the hypotenuse is the longest side " + strings.Repeat("more text ", 20)
+	got := Snippet(text, []string{"hypotenuse"}, 60)
+
+	assert.True(t, strings.HasPrefix(got, "…"))
+	assert.True(t, strings.HasSuffix(got, "…"))
+	assert.Contains(t, got, "<mark>hypotenuse</mark>")
+	assert.Regexp(t, `^…(filler|words|here) `, got, "words are not cut in half")
+}
+
+func TestMergeKeepsBestScorePerEntity(t *testing.T) {
+	hits := []models.SearchHit{
+		{Type: models.EntityQuestion, ID: 1, Score: 1, Title: "base"},
+		{Type: models.EntityQuestion, ID: 1, Score: 3, Title: "translated"},
+		{Type: models.EntitySubtopic, ID: 1, Score: 2},
+	}
+	got := Merge(hits, 0, 10)
+	require.Len(t, got, 2)
+	assert.Equal(t, "translated", got[0].Title)
+	assert.Equal(t, models.EntitySubtopic, got[1].Type)
+
+	assert.Len(t, Merge(hits, 1, 10), 1)
+	assert.Empty(t, Merge(hits, 5, 10))
+}
+
+func testIndex() *MemoryIndex {
+	path := []models.SearchCrumb{
+		{Type: models.EntityGrade, ID: 1, Name: "Grade 6"},
+		{Type: models.EntityLesson, ID: 4, Name: "Geometry"},
+	}
+	idx := NewMemoryIndex()
+	idx.Add(
+		Document{Type: models.EntitySubtopic, ID: 1, Title: "Triangles", Breadcrumb: path},
+		Document{Type: models.EntitySmartNote, ID: 7, Title: "Pythagoras theorem",
+			Body: "In a right angled triangle the square of the hypotenuse equals the sum of the squares of the other sides.", Breadcrumb: path},
+		Document{Type: models.EntityQuestion, ID: 9, Title: "What is the perimeter of a square with side 5 cm?"},
+		Document{Type: models.EntitySubtopic, ID: 1, Lang: "si", Title: "ත්‍රිකෝණ", Breadcrumb: path},
+	)
+	return idx
+}
+
+func TestMemoryIndexRanksTitleMatchesHigher(t *testing.T) {
+	hits, err := testIndex().Search(context.Background(), Query{Text: "square", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+
+	assert.Equal(t, models.EntityQuestion, hits[0].Type)
+	assert.Equal(t, models.EntitySmartNote, hits[1].Type)
+	assert.Greater(t, hits[0].Score, hits[1].Score)
+	assert.Contains(t, hits[1].Snippet, "the <mark>square</mark> of")
+	assert.Len(t, hits[1].Breadcrumb, 2)
+}
+
+func TestMemoryIndexFiltersTypesAndLanguage(t *testing.T) {
+	idx := testIndex()
+
+	hits, err := idx.Search(context.Background(), Query{Text: "square", Types: []string{models.EntityQuestion}, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, int64(9), hits[0].ID)
+
+	hits, err = idx.Search(context.Background(), Query{Text: "ත්‍රිකෝණ", Lang: "ta", Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, hits, "Sinhala translations are not searched for Tamil readers")
+
+	hits, err = idx.Search(context.Background(), Query{Text: "ත්‍රිකෝණ", Lang: "si", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, "<mark>ත්‍රිකෝණ</mark>", hits[0].Snippet)
+}
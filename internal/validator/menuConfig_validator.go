@@ -1,71 +1,129 @@
 package validator
 
 import (
-	"errors"
+	"fmt"
 	"strings"
 
 	menuconfigmodels "github.com/tharindulakmal/sl-edu-service/internal/models/menuconfig"
 )
 
-var (
-	errNameRequired = errors.New("name is required")
-	errNameTooLong  = errors.New("name must be at most 120 characters")
-)
+// FieldError is what is wrong with one field of a request body; Field is
+// its JSON name.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// FieldErrors lists every field of a request body that failed validation.
+type FieldErrors []FieldError
+
+func (errs FieldErrors) Error() string {
+	parts := make([]string, len(errs))
+	for i, f := range errs {
+		parts[i] = f.Field + " " + f.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (errs *FieldErrors) add(field, message string) {
+	*errs = append(*errs, FieldError{Field: field, Message: message})
+}
+
+// err returns nil rather than an empty list, so callers can check for nil.
+func (errs FieldErrors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
 
-func validateName(name string) error {
+// checkName adds the name's problem, if any, to errs.
+func (errs *FieldErrors) checkName(name string, max int) {
 	trimmed := strings.TrimSpace(name)
-	if trimmed == "" {
-		return errNameRequired
+	switch {
+	case trimmed == "":
+		errs.add("name", "is required")
+	case len(trimmed) > max:
+		errs.add("name", fmt.Sprintf("must be at most %d characters", max))
 	}
-	if len(trimmed) > 120 {
-		return errNameTooLong
+}
+
+// checkParent adds a missing parent id to errs. Whether the parent exists is
+// checked by the repository, in the transaction making the change.
+func (errs *FieldErrors) checkParent(field string, id int64) {
+	if id == 0 {
+		errs.add(field, "is required")
 	}
-	return nil
 }
 
 func ValidateGradeUpsert(input menuconfigmodels.GradeUpsert) error {
-	return validateName(input.Name)
+	var errs FieldErrors
+	errs.checkName(input.Name, 120)
+	return errs.err()
 }
 
 func ValidateSubjectUpsert(input menuconfigmodels.SubjectUpsert) error {
-	if input.GradeID == 0 {
-		return errors.New("gradeId is required")
-	}
-	return validateName(input.Name)
+	var errs FieldErrors
+	errs.checkParent("gradeId", input.GradeID)
+	errs.checkName(input.Name, 120)
+	return errs.err()
 }
 
 func ValidateLessonUpsert(input menuconfigmodels.LessonUpsert) error {
-	if input.SubjectID == 0 {
-		return errors.New("subjectId is required")
-	}
-	return validateName(input.Name)
+	var errs FieldErrors
+	errs.checkParent("subjectId", input.SubjectID)
+	errs.checkName(input.Name, 120)
+	return errs.err()
 }
 
 func ValidateTopicUpsert(input menuconfigmodels.TopicUpsert) error {
-	if input.LessonID == 0 {
-		return errors.New("lessonId is required")
-	}
-	return validateName(input.Name)
+	var errs FieldErrors
+	errs.checkParent("lessonId", input.LessonID)
+	errs.checkName(input.Name, 120)
+	return errs.err()
 }
 
 func ValidateSubtopicUpsert(input menuconfigmodels.SubtopicUpsert) error {
-	if input.TopicID == 0 {
-		return errors.New("topicId is required")
-	}
-	return validateName(input.Name)
+	var errs FieldErrors
+	errs.checkParent("topicId", input.TopicID)
+	errs.checkName(input.Name, 120)
+	return errs.err()
 }
 
 func ValidateTutorUpsert(input menuconfigmodels.TutorUpsert) error {
-	return validateName(input.Name)
+	var errs FieldErrors
+	errs.checkName(input.Name, 120)
+	return errs.err()
 }
 
 func ValidateYearUpsert(input menuconfigmodels.YearUpsert) error {
+	var errs FieldErrors
 	if input.Value < 1900 || input.Value > 2100 {
-		return errors.New("value must be between 1900 and 2100")
+		errs.add("value", "must be between 1900 and 2100")
 	}
-	return nil
+	return errs.err()
 }
 
 func ValidateTutorialUpsert(input menuconfigmodels.TutorialUpsert) error {
-	return validateName(input.Name)
+	var errs FieldErrors
+	errs.checkName(input.Name, 120)
+	return errs.err()
+}
+
+func ValidatePastPaperUpsert(input menuconfigmodels.PastPaperUpsert) error {
+	var errs FieldErrors
+	switch input.ExamType {
+	case menuconfigmodels.ExamTypeScholarship, menuconfigmodels.ExamTypeOL, menuconfigmodels.ExamTypeAL:
+	default:
+		errs.add("examType", "must be grade5_scholarship, ol or al")
+	}
+	errs.checkParent("yearId", input.YearID)
+	if input.PaperNumber < 1 {
+		errs.add("paperNumber", "must be at least 1")
+	}
+	if input.DurationMinutes != nil && (*input.DurationMinutes < 1 || *input.DurationMinutes > 600) {
+		errs.add("durationMinutes", "must be between 1 and 600")
+	}
+	errs.checkName(input.Name, 160)
+	return errs.err()
 }
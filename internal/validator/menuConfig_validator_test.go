@@ -0,0 +1,25 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	menuconfigmodels "github.com/tharindulakmal/sl-edu-service/internal/models/menuconfig"
+)
+
+func TestValidateSubjectUpsertReportsEveryField(t *testing.T) {
+	err := ValidateSubjectUpsert(menuconfigmodels.SubjectUpsert{Name: "  "})
+	var fields FieldErrors
+	require.ErrorAs(t, err, &fields)
+	assert.Equal(t, FieldErrors{
+		{Field: "gradeId", Message: "is required"},
+		{Field: "name", Message: "is required"},
+	}, fields)
+	assert.EqualError(t, err, "gradeId is required; name is required")
+}
+
+func TestValidateSubjectUpsertAcceptsValidInput(t *testing.T) {
+	assert.NoError(t, ValidateSubjectUpsert(menuconfigmodels.SubjectUpsert{GradeID: 6, Name: "Science"}))
+}
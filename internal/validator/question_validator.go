@@ -0,0 +1,110 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/quiz"
+)
+
+// blankMarker is how a fill in the blank question marks each gap.
+var blankMarker = regexp.MustCompile(`_{3,}`)
+
+// ValidateQuestion checks the answer key of a question against the rules of
+// its type. An empty type is a single choice question.
+func ValidateQuestion(q models.Question) error {
+	if strings.TrimSpace(q.Question) == "" {
+		return errors.New("question is required")
+	}
+
+	spec := q.Answer
+	switch quiz.Type(q) {
+	case models.QuestionTypeSingleChoice:
+		if err := requireText("correctAnswer", &q.CorrectAnswer, 255); err != nil {
+			return err
+		}
+		if countDistinct(append([]string{q.CorrectAnswer}, q.OtherAnswers...)) < 2 {
+			return errors.New("otherAnswers must hold at least one answer besides the correct one")
+		}
+
+	case models.QuestionTypeTrueFalse:
+		switch strings.ToLower(strings.TrimSpace(q.CorrectAnswer)) {
+		case "true", "false":
+		default:
+			return errors.New("correctAnswer must be true or false")
+		}
+
+	case models.QuestionTypeMultiSelect:
+		if spec == nil || countDistinct(spec.Correct) == 0 {
+			return errors.New("answer.correct must list the correct options")
+		}
+		if countDistinct(append(append([]string{}, spec.Correct...), q.OtherAnswers...)) < 2 {
+			return errors.New("a multi select question needs at least two options")
+		}
+
+	case models.QuestionTypeNumeric:
+		if spec == nil || spec.Number == nil {
+			return errors.New("answer.number is required")
+		}
+		if math.IsNaN(spec.Tolerance) || spec.Tolerance < 0 {
+			return errors.New("answer.tolerance must not be negative")
+		}
+
+	case models.QuestionTypeFillBlank:
+		if spec == nil || len(spec.Blanks) == 0 {
+			return errors.New("answer.blanks must list the accepted answers for each blank")
+		}
+		for i, accepted := range spec.Blanks {
+			if countDistinct(accepted) == 0 {
+				return fmt.Errorf("answer.blanks[%d] needs at least one accepted answer", i)
+			}
+		}
+		if n := len(blankMarker.FindAllString(q.Question, -1)); n != len(spec.Blanks) {
+			return fmt.Errorf("question has %d blanks (___) but answer.blanks has %d", n, len(spec.Blanks))
+		}
+
+	case models.QuestionTypeMatching:
+		if spec == nil || len(spec.Pairs) < 2 {
+			return errors.New("answer.pairs must hold at least two pairs")
+		}
+		lefts := make([]string, len(spec.Pairs))
+		rights := make([]string, len(spec.Pairs))
+		for i, p := range spec.Pairs {
+			if strings.TrimSpace(p.Left) == "" || strings.TrimSpace(p.Right) == "" {
+				return fmt.Errorf("answer.pairs[%d] needs both left and right", i)
+			}
+			lefts[i], rights[i] = p.Left, p.Right
+		}
+		if countDistinct(lefts) != len(lefts) || countDistinct(rights) != len(rights) {
+			return errors.New("answer.pairs must not repeat a left or right item")
+		}
+
+	default:
+		return fmt.Errorf("type must be one of %s, %s, %s, %s, %s or %s",
+			models.QuestionTypeSingleChoice, models.QuestionTypeMultiSelect, models.QuestionTypeTrueFalse,
+			models.QuestionTypeNumeric, models.QuestionTypeFillBlank, models.QuestionTypeMatching)
+	}
+
+	// the readable key is stored in correct_answer
+	if utf8.RuneCountInString(quiz.AnswerText(q)) > 255 {
+		return errors.New("answer is too long; it must read in at most 255 characters")
+	}
+	return nil
+}
+
+// countDistinct counts the non-blank values, ignoring case and surrounding
+// whitespace.
+func countDistinct(values []string) int {
+	seen := map[string]bool{}
+	for _, v := range values {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			seen[v] = true
+		}
+	}
+	return len(seen)
+}
@@ -0,0 +1,25 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+func ValidateSmartNoteUpsert(input models.SmartNoteUpsert) error {
+	if input.LessonID == 0 {
+		return errors.New("lessonId is required")
+	}
+	if input.SubtopicID != nil && input.TopicID == nil {
+		return errors.New("topicId is required when subtopicId is set")
+	}
+	trimmed := strings.TrimSpace(input.SubTopicName)
+	if trimmed == "" {
+		return errors.New("subTopicName is required")
+	}
+	if len(trimmed) > 255 {
+		return errors.New("subTopicName must be at most 255 characters")
+	}
+	return nil
+}
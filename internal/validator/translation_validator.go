@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+// ValidateTranslation checks that a translation targets a non-base language
+// and only sets the fields its entity has. Lengths are counted in characters
+// since Sinhala and Tamil letters take several bytes.
+func ValidateTranslation(t models.Translation) error {
+	if !i18n.Valid(t.Lang) {
+		return fmt.Errorf("lang must be one of %s", strings.Join(i18n.Supported, ", "))
+	}
+	if t.Lang == i18n.Base {
+		return fmt.Errorf("%s is the base language; edit the %s itself", i18n.Base, t.Entity)
+	}
+
+	f := t.TranslationFields
+	switch t.Entity {
+	case models.EntityGrade, models.EntitySubject, models.EntityLesson, models.EntityTopic, models.EntitySubtopic:
+		if err := requireText("name", f.Name, 120); err != nil {
+			return err
+		}
+		return rejectFields(map[string]bool{
+			"subTopicName": f.SubTopicName != nil, "definition": f.Definition != nil, "example": f.Example != nil,
+			"question": f.Question != nil, "correctAnswer": f.CorrectAnswer != nil, "otherAnswers": f.OtherAnswers != nil,
+			"answer": f.Answer != nil, "theory": f.Theory != nil, "solution": f.Solution != nil,
+		})
+	case models.EntitySmartNote:
+		if err := requireText("subTopicName", f.SubTopicName, 255); err != nil {
+			return err
+		}
+		return rejectFields(map[string]bool{
+			"name": f.Name != nil, "question": f.Question != nil, "correctAnswer": f.CorrectAnswer != nil,
+			"otherAnswers": f.OtherAnswers != nil, "answer": f.Answer != nil, "solution": f.Solution != nil,
+		})
+	case models.EntityQuestion:
+		if err := requireText("question", f.Question, 0); err != nil {
+			return err
+		}
+		if err := requireText("correctAnswer", f.CorrectAnswer, 255); err != nil {
+			return err
+		}
+		// questions keyed by an answer spec translate it instead of the
+		// single choice answers
+		if f.Answer != nil {
+			if err := validateAnswerTranslation(*f.Answer); err != nil {
+				return err
+			}
+		} else if len(f.OtherAnswers) == 0 {
+			return errors.New("otherAnswers is required")
+		}
+		return rejectFields(map[string]bool{
+			"name": f.Name != nil, "subTopicName": f.SubTopicName != nil, "definition": f.Definition != nil,
+			"example": f.Example != nil,
+		})
+	}
+	return fmt.Errorf("unknown entity %q", t.Entity)
+}
+
+// validateAnswerTranslation checks the translated key of a multi select,
+// fill in the blank or matching question. Numbers and tolerances read the
+// same in every language and are not translated.
+func validateAnswerTranslation(a models.AnswerSpec) error {
+	if a.Number != nil || a.Tolerance != 0 || a.Unit != "" {
+		return errors.New("answer only translates correct, blanks or pairs")
+	}
+	set := 0
+	for _, ok := range []bool{len(a.Correct) > 0, len(a.Blanks) > 0, len(a.Pairs) > 0} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("answer must set one of correct, blanks or pairs")
+	}
+	for i, c := range a.Correct {
+		if strings.TrimSpace(c) == "" {
+			return fmt.Errorf("answer.correct[%d] is empty", i)
+		}
+	}
+	for i, accepted := range a.Blanks {
+		if countDistinct(accepted) == 0 {
+			return fmt.Errorf("answer.blanks[%d] needs at least one accepted answer", i)
+		}
+	}
+	for i, p := range a.Pairs {
+		if strings.TrimSpace(p.Left) == "" || strings.TrimSpace(p.Right) == "" {
+			return fmt.Errorf("answer.pairs[%d] needs both left and right", i)
+		}
+	}
+	return nil
+}
+
+func requireText(field string, value *string, maxLen int) error {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+	if maxLen > 0 && utf8.RuneCountInString(strings.TrimSpace(*value)) > maxLen {
+		return fmt.Errorf("%s must be at most %d characters", field, maxLen)
+	}
+	return nil
+}
+
+func rejectFields(set map[string]bool) error {
+	var names []string
+	for name, ok := range set {
+		if ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return fmt.Errorf("unexpected fields for this entity: %s", strings.Join(names, ", "))
+}
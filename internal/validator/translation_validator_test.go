@@ -0,0 +1,31 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/i18n"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+func TestValidateTranslationOfAnswerSpecs(t *testing.T) {
+	text := func(s string) *string { return &s }
+	question := func(answer *models.AnswerSpec, other models.StringArray) models.Translation {
+		return models.Translation{Entity: models.EntityQuestion, EntityID: 1, Lang: i18n.Sinhala,
+			TranslationFields: models.TranslationFields{Question: text("ප්‍රශ්නය"), CorrectAnswer: text("පිළිතුර"), OtherAnswers: other, Answer: answer}}
+	}
+	g := 9.8
+
+	assert.NoError(t, ValidateTranslation(question(&models.AnswerSpec{Pairs: []models.MatchPair{{Left: "ත්‍රිකෝණය", Right: "3"}}}, nil)))
+	assert.NoError(t, ValidateTranslation(question(&models.AnswerSpec{Correct: []string{"2", "3"}}, models.StringArray{"4"})))
+	assert.EqualError(t, ValidateTranslation(question(nil, nil)), "otherAnswers is required")
+	assert.EqualError(t, ValidateTranslation(question(&models.AnswerSpec{}, nil)), "answer must set one of correct, blanks or pairs")
+	assert.EqualError(t, ValidateTranslation(question(&models.AnswerSpec{Number: &g}, nil)), "answer only translates correct, blanks or pairs")
+	assert.EqualError(t, ValidateTranslation(question(&models.AnswerSpec{Blanks: [][]string{{" "}}}, nil)), "answer.blanks[0] needs at least one accepted answer")
+	assert.EqualError(t, ValidateTranslation(question(&models.AnswerSpec{Pairs: []models.MatchPair{{Left: "x"}}}, nil)), "answer.pairs[0] needs both left and right")
+
+	grade := models.Translation{Entity: models.EntityGrade, EntityID: 1, Lang: i18n.Tamil,
+		TranslationFields: models.TranslationFields{Name: text("தரம் 6"), Answer: &models.AnswerSpec{Correct: []string{"x"}}}}
+	assert.EqualError(t, ValidateTranslation(grade), "unexpected fields for this entity: answer")
+}
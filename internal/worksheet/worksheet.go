@@ -0,0 +1,294 @@
+// Package worksheet renders questions as printable PDF worksheets and the
+// matching answer keys.
+package worksheet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+	"github.com/tharindulakmal/sl-edu-service/internal/pdf"
+	"github.com/tharindulakmal/sl-edu-service/internal/quiz"
+)
+
+const (
+	titleSize  = 16.0
+	textSize   = 11.0
+	labelWidth = 24.0
+	imageMaxH  = 220.0
+
+	// imageFetches is how many question images are downloaded at once.
+	imageFetches = 8
+)
+
+// Item is one numbered question with its options in print order.
+type Item struct {
+	Number   int
+	Question models.Question
+	Type     string
+	Prompts  []string // matching questions only
+	Options  []string
+	// Correct holds the indexes in Options of the correct answers; for
+	// matching questions, the match of each prompt in order. It is empty for
+	// questions answered in writing.
+	Correct []int
+}
+
+// Prepare numbers the questions and shuffles each one's options. The order
+// depends only on seed and the question id, so a worksheet and its answer
+// key printed with the same seed always agree.
+func Prepare(questions []models.Question, seed int64) []Item {
+	items := make([]Item, len(questions))
+	for i, q := range questions {
+		rng := rand.New(rand.NewSource(seed + int64(q.ID)))
+		it := Item{Number: i + 1, Question: q, Type: quiz.Type(q), Prompts: q.Answer.Prompts()}
+		for _, opt := range quiz.BuildOptions(q, rng) {
+			it.Options = append(it.Options, strings.TrimSpace(opt))
+		}
+
+		var keys []string
+		switch it.Type {
+		case models.QuestionTypeMultiSelect:
+			if q.Answer != nil {
+				keys = q.Answer.Correct
+			}
+		case models.QuestionTypeMatching:
+			if q.Answer != nil {
+				for _, p := range q.Answer.Pairs {
+					keys = append(keys, p.Right)
+				}
+			}
+		case models.QuestionTypeNumeric, models.QuestionTypeFillBlank:
+		default:
+			keys = []string{q.CorrectAnswer}
+		}
+		for _, key := range keys {
+			for j, opt := range it.Options {
+				if quiz.IsCorrect(opt, key) {
+					it.Correct = append(it.Correct, j)
+					break
+				}
+			}
+		}
+		items[i] = it
+	}
+	return items
+}
+
+// Letter labels the option at index i: A, B, C...
+func Letter(i int) string {
+	if i < 26 {
+		return string(rune('A' + i))
+	}
+	return fmt.Sprintf("%d", i+1)
+}
+
+// ImageLoader fetches question images.
+type ImageLoader interface {
+	Load(ctx context.Context, url string) ([]byte, error)
+}
+
+// HTTPLoader downloads images over http and https.
+type HTTPLoader struct {
+	Client   *http.Client
+	MaxBytes int64
+}
+
+// NewHTTPLoader gives up on an image after five seconds or five megabytes.
+func NewHTTPLoader() *HTTPLoader {
+	return &HTTPLoader{Client: &http.Client{Timeout: 5 * time.Second}, MaxBytes: 5 << 20}
+}
+
+var errImageTooLarge = errors.New("image too large")
+
+func (l *HTTPLoader) Load(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported image url scheme %q", u.Scheme)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := l.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image request returned %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, l.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > l.MaxBytes {
+		return nil, errImageTooLarge
+	}
+	return data, nil
+}
+
+// loadImages fetches the distinct image urls of items. Images that fail to
+// load or decode are left out; the worksheet says so in their place.
+func loadImages(ctx context.Context, loader ImageLoader, items []Item) map[string]*pdf.Image {
+	images := map[string]*pdf.Image{}
+	if loader == nil {
+		return images
+	}
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, imageFetches)
+	)
+	for _, it := range items {
+		u := imageURL(it.Question)
+		if u == "" {
+			continue
+		}
+		if _, ok := images[u]; ok {
+			continue
+		}
+		images[u] = nil
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			data, err := loader.Load(ctx, u)
+			if err != nil {
+				return
+			}
+			img, err := pdf.DecodeImage(data)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			images[u] = img
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return images
+}
+
+func imageURL(q models.Question) string {
+	if q.QuestionImg == nil {
+		return ""
+	}
+	return strings.TrimSpace(*q.QuestionImg)
+}
+
+// Worksheet writes the questions with lettered options and space for the
+// student's name, without answers.
+func Worksheet(ctx context.Context, w io.Writer, title string, items []Item, images ImageLoader) error {
+	loaded := loadImages(ctx, images, items)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	doc := pdf.New(title)
+	doc.Paragraph(title, pdf.HelveticaBold, titleSize, 0)
+	doc.Space(6)
+	doc.Paragraph("Name: ______________________________    Date: ______________", pdf.Helvetica, textSize, 0)
+	doc.Space(4)
+	doc.Rule()
+	doc.Space(12)
+
+	for _, it := range items {
+		doc.Hanging(fmt.Sprintf("%d.", it.Number), it.Question.Question, pdf.Helvetica, textSize, 0, labelWidth)
+		if u := imageURL(it.Question); u != "" {
+			doc.Space(4)
+			if img := loaded[u]; img != nil {
+				doc.Image(img, labelWidth, 0, imageMaxH)
+			} else {
+				doc.Paragraph("[image unavailable]", pdf.Helvetica, textSize-2, labelWidth)
+			}
+		}
+		doc.Space(4)
+		switch it.Type {
+		case models.QuestionTypeNumeric, models.QuestionTypeFillBlank:
+			doc.Paragraph("Answer: ________________________________", pdf.Helvetica, textSize, labelWidth)
+		case models.QuestionTypeMatching:
+			doc.Paragraph("Match each item with a letter from the list below.", pdf.Helvetica, textSize-1, labelWidth)
+			for i, p := range it.Prompts {
+				doc.Hanging(roman(i+1)+")", p+"   ____", pdf.Helvetica, textSize, labelWidth, labelWidth)
+			}
+			doc.Space(4)
+		case models.QuestionTypeMultiSelect:
+			doc.Paragraph("Select all that apply.", pdf.Helvetica, textSize-1, labelWidth)
+		}
+		for i, opt := range it.Options {
+			doc.Hanging("("+Letter(i)+")", opt, pdf.Helvetica, textSize, labelWidth, labelWidth)
+		}
+		doc.Space(12)
+	}
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// answerText gives the letters of the correct options, each with its text,
+// or the written answer for questions without options.
+func answerText(it Item) string {
+	if len(it.Correct) == 0 {
+		return quiz.AnswerText(it.Question)
+	}
+	parts := make([]string, len(it.Correct))
+	for i, j := range it.Correct {
+		parts[i] = fmt.Sprintf("(%s) %s", Letter(j), it.Options[j])
+		if it.Type == models.QuestionTypeMatching {
+			parts[i] = roman(i+1) + ") " + parts[i]
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// roman numbers the prompts of matching questions, so they are not confused
+// with question numbers or option letters.
+func roman(n int) string {
+	if n < 1 || n >= 40 {
+		return strconv.Itoa(n)
+	}
+	var b strings.Builder
+	for _, r := range []struct {
+		value  int
+		symbol string
+	}{{10, "x"}, {9, "ix"}, {5, "v"}, {4, "iv"}, {1, "i"}} {
+		for ; n >= r.value; n -= r.value {
+			b.WriteString(r.symbol)
+		}
+	}
+	return b.String()
+}
+
+// AnswerKey writes the letter and text of each correct answer, or the
+// written answer, followed by the solution where there is one.
+func AnswerKey(w io.Writer, title string, items []Item) error {
+	title += " - Answer key"
+	doc := pdf.New(title)
+	doc.Paragraph(title, pdf.HelveticaBold, titleSize, 0)
+	doc.Space(4)
+	doc.Rule()
+	doc.Space(12)
+
+	for _, it := range items {
+		doc.Hanging(fmt.Sprintf("%d.", it.Number), answerText(it), pdf.HelveticaBold, textSize, 0, labelWidth)
+		if s := it.Question.Solution; s != nil && strings.TrimSpace(*s) != "" {
+			doc.Paragraph(strings.TrimSpace(*s), pdf.Helvetica, textSize-1, labelWidth)
+		}
+		doc.Space(8)
+	}
+	_, err := doc.WriteTo(w)
+	return err
+}
@@ -0,0 +1,135 @@
+package worksheet
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tharindulakmal/sl-edu-service/internal/models"
+)
+
+func ptr(s string) *string { return &s }
+
+func sampleQuestions() []models.Question {
+	return []models.Question{
+		{ID: 1, Question: "2 + 2 = ?", CorrectAnswer: "4", OtherAnswers: models.StringArray{"3", "5", "4", " ", "22"}, Solution: ptr("Add the two numbers.")},
+		{ID: 2, Question: "Which shape has three sides?", CorrectAnswer: "Triangle", OtherAnswers: models.StringArray{"Square", "Circle"},
+			QuestionImg: ptr("https://img.example/shapes.png")},
+	}
+}
+
+func TestPrepareIsDeterministic(t *testing.T) {
+	items := Prepare(sampleQuestions(), 7)
+	require.Len(t, items, 2)
+	assert.Len(t, items[0].Options, 4, "blank and repeated answers are dropped")
+	for _, it := range items {
+		require.Len(t, it.Correct, 1)
+		assert.Equal(t, it.Question.CorrectAnswer, it.Options[it.Correct[0]])
+	}
+	assert.Equal(t, items, Prepare(sampleQuestions(), 7))
+	assert.Equal(t, 2, items[1].Number)
+}
+
+// pageText inflates the content streams of a document.
+func pageText(t *testing.T, doc []byte) string {
+	var text strings.Builder
+	for _, m := range regexp.MustCompile(`(?s)/FlateDecode /Length \d+ >>\nstream\n(.*?)\nendstream`).FindAllSubmatch(doc, -1) {
+		r, err := zlib.NewReader(bytes.NewReader(m[1]))
+		require.NoError(t, err)
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		text.Write(b)
+	}
+	return text.String()
+}
+
+type loaderFunc func(ctx context.Context, url string) ([]byte, error)
+
+func (f loaderFunc) Load(ctx context.Context, url string) ([]byte, error) { return f(ctx, url) }
+
+func TestWorksheet(t *testing.T) {
+	var pngData bytes.Buffer
+	require.NoError(t, png.Encode(&pngData, image.NewGray(image.Rect(0, 0, 8, 8))))
+	items := Prepare(sampleQuestions(), 1)
+
+	var out bytes.Buffer
+	require.NoError(t, Worksheet(context.Background(), &out, "Grade 6 Maths", items,
+		loaderFunc(func(context.Context, string) ([]byte, error) { return pngData.Bytes(), nil })))
+	text := pageText(t, out.Bytes())
+	assert.Contains(t, text, "(Which shape has three sides?)")
+	assert.Contains(t, text, `(\(A\))`)
+	assert.Contains(t, text, "/Im1 Do")
+	assert.NotContains(t, text, "Add the two numbers", "solutions stay in the answer key")
+
+	out.Reset()
+	require.NoError(t, Worksheet(context.Background(), &out, "Grade 6 Maths", items,
+		loaderFunc(func(context.Context, string) ([]byte, error) { return nil, errors.New("offline") })))
+	assert.Contains(t, pageText(t, out.Bytes()), "([image unavailable])")
+}
+
+func TestAnswerKey(t *testing.T) {
+	items := Prepare(sampleQuestions(), 1)
+	var out bytes.Buffer
+	require.NoError(t, AnswerKey(&out, "Grade 6 Maths", items))
+	text := pageText(t, out.Bytes())
+
+	assert.Contains(t, text, "(\\("+Letter(items[0].Correct[0])+"\\) 4)")
+	assert.Contains(t, text, "(Add the two numbers.)")
+}
+
+func TestHTTPLoader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/big" {
+			_, _ = w.Write(make([]byte, 64))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	l := NewHTTPLoader()
+	l.MaxBytes = 32
+	_, err := l.Load(context.Background(), srv.URL+"/big")
+	assert.ErrorIs(t, err, errImageTooLarge)
+	_, err = l.Load(context.Background(), srv.URL+"/missing")
+	assert.Error(t, err)
+	_, err = l.Load(context.Background(), "file:///etc/passwd")
+	assert.Error(t, err)
+}
+
+func TestPrepareAndKeyForOtherTypes(t *testing.T) {
+	g := 9.8
+	items := Prepare([]models.Question{
+		{ID: 3, Type: models.QuestionTypeMultiSelect, Question: "Which are prime?", CorrectAnswer: "2; 3",
+			Answer: &models.AnswerSpec{Correct: []string{"2", "3"}}, OtherAnswers: models.StringArray{"4", "9"}},
+		{ID: 4, Type: models.QuestionTypeMatching, Question: "Match the symbols.", CorrectAnswer: "Na = Sodium; K = Potassium",
+			Answer: &models.AnswerSpec{Pairs: []models.MatchPair{{Left: "Na", Right: "Sodium"}, {Left: "K", Right: "Potassium"}}}},
+		{ID: 5, Type: models.QuestionTypeNumeric, Question: "g in m/s²?", CorrectAnswer: "9.8 m/s²",
+			Answer: &models.AnswerSpec{Number: &g, Unit: "m/s²"}},
+	}, 1)
+
+	require.Len(t, items[0].Correct, 2)
+	for _, j := range items[0].Correct {
+		assert.Contains(t, []string{"2", "3"}, items[0].Options[j])
+	}
+	assert.Equal(t, []string{"Na", "K"}, items[1].Prompts)
+	assert.Equal(t, "Sodium", items[1].Options[items[1].Correct[0]])
+	assert.Empty(t, items[2].Correct)
+	assert.Equal(t, "9.8 m/s²", answerText(items[2]))
+	assert.Contains(t, answerText(items[1]), "ii) (")
+
+	var out bytes.Buffer
+	require.NoError(t, Worksheet(context.Background(), &out, "Science", items, nil))
+	assert.Contains(t, pageText(t, out.Bytes()), "(Select all that apply.)")
+}